@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/go-resty/resty/v2"
+	"strings"
+)
+
+// AuthMethod is a way of sending the access token with a request.
+type AuthMethod int
+
+// Ways of sending the access token.  Query parameters are recorded in the logs
+// of proxies and servers, so AuthQuery should be used only for endpoints that
+// do not accept the header.
+const (
+	AuthHeader AuthMethod = iota // Header X-Rollbar-Access-Token, the default
+	AuthQuery                    // Query parameter access_token
+)
+
+// Names of the header and query parameter carrying the access token.
+const (
+	authHeaderName = "X-Rollbar-Access-Token"
+	authQueryName  = "access_token"
+)
+
+// WithAuthMethod sends the access token using method m to the endpoints with
+// the given path templates, e.g. "/api/1/item/{itemID}", or if no paths are
+// given to every endpoint without a method of its own.
+func WithAuthMethod(m AuthMethod, paths ...string) Option {
+	return func(o *options) {
+		if len(paths) == 0 {
+			o.auth.fallback = m
+			return
+		}
+		if o.auth.byPath == nil {
+			o.auth.byPath = make(map[string]AuthMethod)
+		}
+		for _, p := range paths {
+			o.auth.byPath[p] = m
+		}
+	}
+}
+
+// authStrategy chooses how the access token is sent to each endpoint.
+type authStrategy struct {
+	fallback AuthMethod
+	byPath   map[string]AuthMethod
+}
+
+// method returns the method used to send the access token to the endpoint
+// with path template `path`.
+func (a authStrategy) method(path string) AuthMethod {
+	if m, ok := a.byPath[path]; ok {
+		return m
+	}
+	return a.fallback
+}
+
+// authenticator returns a resty request middleware that sends token with each
+// request, by exactly one method, so that a token already set by the other
+// method never accompanies it.  Requests are matched to endpoints by their URL
+// template, before path parameters are substituted.
+func (a authStrategy) authenticator(baseURL, token string) resty.RequestMiddleware {
+	return func(_ *resty.Client, r *resty.Request) error {
+		r.Header.Del(authHeaderName)
+		r.QueryParam.Del(authQueryName)
+		if token == "" {
+			return nil
+		}
+		path := strings.TrimPrefix(r.URL, baseURL)
+		if i := strings.IndexByte(path, '?'); i >= 0 {
+			path = path[:i]
+		}
+		switch a.method(path) {
+		case AuthQuery:
+			r.SetQueryParam(authQueryName, token)
+		default:
+			r.SetHeader(authHeaderName, token)
+		}
+		return nil
+	}
+}
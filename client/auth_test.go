@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAuthMethod tests sending the access token by header or query parameter.
+func TestAuthMethod(t *testing.T) {
+	var header, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(authHeaderName)
+		query = r.URL.Query().Get(authQueryName)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(loadFixture("project/list.json")))
+	}))
+	defer srv.Close()
+
+	// Header by default
+	c := NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	_, err := c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, "fakeTokenString", header)
+	assert.Empty(t, query)
+
+	// A token in the query parameter is never sent as well
+	_, err = c.Resty.R().SetQueryParam(authQueryName, "staleToken").Get(srv.URL + pathProjectList)
+	assert.Nil(t, err)
+	assert.Equal(t, "fakeTokenString", header)
+	assert.Empty(t, query)
+
+	// Query parameter for one endpoint
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL), WithAuthMethod(AuthQuery, pathProjectList))
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Empty(t, header)
+	assert.Equal(t, "fakeTokenString", query)
+	_, _ = c.ListTeams()
+	assert.Equal(t, "fakeTokenString", header)
+	assert.Empty(t, query)
+
+	// Query parameter for all endpoints but one
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL),
+		WithAuthMethod(AuthQuery),
+		WithAuthMethod(AuthHeader, pathProjectList),
+	)
+	_, _ = c.ListTeams()
+	assert.Empty(t, header)
+	assert.Equal(t, "fakeTokenString", query)
+	_, _ = c.ListProjects()
+	assert.Equal(t, "fakeTokenString", header)
+	assert.Empty(t, query)
+
+	// No token
+	c = NewClient("", WithBaseURL(srv.URL))
+	_, _ = c.ListProjects()
+	assert.Empty(t, header)
+	assert.Empty(t, query)
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keys for list results held by a responseCache.
+const (
+	cacheKeyProjects = "projects"
+	cacheKeyTeams    = "teams"
+)
+
+// responseCache holds the results of list calls, such as ListProjects and
+// ListTeams, for a fixed time-to-live.  Data sources and name based lookups
+// repeatedly list the same entities during a single plan; the cache lets them
+// share one API call.  A nil responseCache caches nothing.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a cached value and the time at which it expires.
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// SetCacheTTL configures the client to cache the results of ListProjects and
+// ListTeams for duration ttl.  Creating, updating or deleting a project or team
+// through the client invalidates the corresponding cached list.  A ttl of zero
+// or less disables caching.
+func (c *RollbarAPIClient) SetCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the unexpired value cached under key, if any.
+func (rc *responseCache) get(key string) (interface{}, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// set caches value under key for the cache's time-to-live.
+func (rc *responseCache) set(key string, value interface{}) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[key] = cacheEntry{
+		value:   value,
+		expires: time.Now().Add(rc.ttl),
+	}
+}
+
+// invalidate removes any value cached under key.
+func (rc *responseCache) invalidate(key string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, key)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/jarcoal/httpmock"
+	"net/http"
+	"time"
+)
+
+// TestCacheTTL tests caching project and team lists, and invalidating the
+// cache on writes.
+func (s *Suite) TestCacheTTL() {
+	s.client.SetCacheTTL(time.Minute)
+	defer s.client.SetCacheTTL(0)
+	httpmock.ZeroCallCounters()
+
+	uProjects := s.client.BaseURL + pathProjectList
+	uTeams := s.client.BaseURL + pathTeamList
+	httpmock.RegisterResponder("GET", uProjects,
+		responderFromFixture("project/list.json", http.StatusOK))
+	httpmock.RegisterResponder("GET", uTeams,
+		responderFromFixture("team/list.json", http.StatusOK))
+	httpmock.RegisterResponder("POST", s.client.BaseURL+pathProjectCreate,
+		responderFromFixture("project/create.json", http.StatusOK))
+	calls := func(u string) int {
+		return httpmock.GetCallCountInfo()["GET "+u]
+	}
+
+	// Repeated lists share one API call
+	first, err := s.client.ListProjects()
+	s.Nil(err)
+	second, err := s.client.ListProjects()
+	s.Nil(err)
+	s.Equal(first, second)
+	s.Equal(1, calls(uProjects))
+	_, err = s.client.ListTeams()
+	s.Nil(err)
+	_, err = s.client.FindTeamID("my-test-team")
+	s.Nil(err)
+	s.Equal(1, calls(uTeams))
+
+	// Callers receive their own copy of the cached list
+	second[0].Name = "mutated"
+	third, err := s.client.ListProjects()
+	s.Nil(err)
+	s.Equal(first, third)
+
+	// Writes invalidate the cache
+	_, err = s.client.CreateProject("foobar")
+	s.Nil(err)
+	_, err = s.client.ListProjects()
+	s.Nil(err)
+	s.Equal(2, calls(uProjects))
+
+	// Disabled cache
+	s.client.SetCacheTTL(0)
+	_, err = s.client.ListTeams()
+	s.Nil(err)
+	s.Equal(2, calls(uTeams))
+}
@@ -20,13 +20,22 @@
  * SOFTWARE.
  */
 
-// Package client is a client library for accessing the Rollbar API.
+// Package client is a client library for accessing the Rollbar API.  It is
+// used by the Terraform provider, but can be used by any Go program:
+//
+//	c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"),
+//		client.WithRetry(3, 0, 0),
+//		client.WithUserAgent("my-program/1.0"),
+//	)
+//	projects, err := c.ListProjects()
 package client
 
 import (
+	"context"
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"sync"
 )
 
 // DefaultBaseURL is the default base URL for the Rollbar API.
@@ -36,43 +45,173 @@ const DefaultBaseURL = "https://api.rollbar.com"
 type RollbarAPIClient struct {
 	BaseURL string // Base URL for Rollbar API
 	Resty   *resty.Client
+
+	// DefaultTeamIDs are teams to be assigned to every project created with
+	// this client, in addition to any requested for the project itself.
+	DefaultTeamIDs []int
+
+	// tokenLists coalesces concurrent listings of the same project's access
+	// tokens, e.g. while many tokens refresh at once, into a single API call.
+	tokenLists *tokenListGroup
+
+	// cache holds list results when enabled with SetCacheTTL.
+	cache *responseCache
+
+	// token is the access token the client authenticates with.
+	token string
+
+	// tokenName is the setting token was configured with; see WithTokenName.
+	tokenName string
+
+	// tokenScopes remembers the result of ProjectTokenScopes.
+	tokenScopes *tokenScopes
+
+	// compat is set by WithCompatibilityMode, for requests bypassing the
+	// response hooks.
+	compat bool
+
+	// ctx bounds the client's requests; see WithContext.
+	ctx context.Context
+
+	// stop abandons the requests of the client and all copies of it; see
+	// WithStopContext.
+	stop context.Context
 }
 
-// NewClient sets up a new Rollbar API client.
-func NewClient(baseURL, token string) *RollbarAPIClient {
+// tokenScopes holds the scopes of the client's access token, once looked up.
+type tokenScopes struct {
+	mu     sync.Mutex
+	known  bool // Whether scopes and err hold the result of a lookup
+	scopes []Scope
+	err    error
+}
+
+// NewClient sets up a new Rollbar API client authenticated with token, which
+// may be an account or a project access token.  By default the client talks to
+// DefaultBaseURL over http.DefaultTransport, without retries or limits; opts
+// change that.
+func NewClient(token string, opts ...Option) *RollbarAPIClient {
 	log.Debug().Msg("Initializing Rollbar client")
+	o := options{baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
 	// New Resty HTTP client
-	r := resty.New()
-
-	// Use default transport - needed for VCR
-	r.SetTransport(http.DefaultTransport)
+	var r *resty.Client
+	if o.httpClient != nil {
+		r = resty.NewWithClient(o.httpClient)
+	} else {
+		r = resty.New()
+		// Use default transport - needed for VCR
+		r.SetTransport(http.DefaultTransport)
+	}
 
 	// Authentication
+	r.OnBeforeRequest(o.auth.authenticator(o.baseURL, token))
 	if token != "" {
-		r = r.SetHeaders(map[string]string{
-			"X-Rollbar-Access-Token": token,
-			"X-Rollbar-Terraform":    "true"})
+		r.SetHeader("X-Rollbar-Terraform", "true")
 	} else {
 		log.Warn().Msg("Rollbar API token not set")
 	}
+	if o.userAgent != "" {
+		r.SetHeader("User-Agent", o.userAgent)
+	}
 
 	// Authentication
-	if baseURL == "" {
+	if o.baseURL == "" {
 		log.Error().Msg("Rollbar API base URL not set")
 	}
 
 	// Configure Resty to use Zerolog for logging
 	r.SetLogger(restyZeroLogger{log.Logger})
 
+	// Correlate each request with its log events and errors
+	r.OnBeforeRequest(setRequestID)
+	r.OnAfterResponse(logResponse)
+	r.OnError(logRequestError)
+	if o.compat {
+		r.OnAfterResponse(normalizeSuccess)
+	}
+
 	// Rollbar client
 	c := RollbarAPIClient{
-		Resty:   r,
-		BaseURL: baseURL,
+		Resty:       r,
+		BaseURL:     o.baseURL,
+		token:       token,
+		tokenName:   o.tokenName,
+		tokenLists:  &tokenListGroup{},
+		tokenScopes: &tokenScopes{},
+		compat:      o.compat,
+	}
+	if o.transport != nil {
+		c.SetTransportOptions(*o.transport)
+	}
+	c.SetLimiter(o.limiter)
+	if o.retries > 0 {
+		c.setRetry(o.retries, o.retryWait, o.retryMaxWait)
 	}
 	return &c
 }
 
+// WithContext returns a copy of the client whose requests are abandoned once
+// ctx is done, e.g. when the deadline of a Terraform operation passes, or once
+// the client's stop context is.  The copy shares its connections, cache and
+// limiter with c.
+func (c *RollbarAPIClient) WithContext(ctx context.Context) *RollbarAPIClient {
+	cc := *c
+	cc.ctx = ctx
+	if c.stop != nil && c.stop.Done() != nil {
+		cc.ctx = mergeContexts(ctx, c.stop)
+	}
+	return &cc
+}
+
+// WithStopContext returns a copy of the client whose requests, and those of
+// every copy made from it with WithContext, are abandoned once stop is done,
+// e.g. when Terraform is interrupted.
+func (c *RollbarAPIClient) WithStopContext(stop context.Context) *RollbarAPIClient {
+	cc := *c
+	cc.stop = stop
+	return &cc
+}
+
+// mergeContexts returns a context derived from ctx that is also cancelled once
+// other is done.
+func mergeContexts(ctx, other context.Context) context.Context {
+	merged, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(other, func() { cancel(context.Cause(other)) })
+	// Forget merged once done, as other may live much longer
+	context.AfterFunc(merged, func() { stop() })
+	return merged
+}
+
+// request starts a new API request, bound to the client's context, or else its
+// stop context, if it has one.
+func (c *RollbarAPIClient) request() *resty.Request {
+	r := c.Resty.R()
+	switch {
+	case c.ctx != nil:
+		r.SetContext(c.ctx)
+	case c.stop != nil:
+		r.SetContext(c.stop)
+	}
+	if c.tokenName != "" {
+		// Read back by errorFromResponse
+		r.SetContext(context.WithValue(r.Context(), tokenNameKey{}, c.tokenName))
+	}
+	return r
+}
+
+// tokenNameKey is the context key under which a request carries the name of
+// the setting its access token was configured with.
+type tokenNameKey struct{}
+
+// HasToken reports whether the client sends an access token with its requests.
+func (c *RollbarAPIClient) HasToken() bool {
+	return c.token != ""
+}
+
 // Status represents the enabled or disabled status of an entity.
 type Status string
 
@@ -89,12 +228,29 @@ func errorFromResponse(resp *resty.Response) error {
 	case http.StatusOK, http.StatusCreated:
 		return nil
 	case http.StatusUnauthorized:
+		if resp.Request != nil {
+			if name, ok := resp.Request.Context().Value(tokenNameKey{}).(string); ok {
+				return &UnauthorizedError{TokenName: name}
+			}
+		}
 		return ErrUnauthorized
 	case http.StatusNotFound:
 		return ErrNotFound
 	default:
-		er := resp.Error().(*ErrorResult)
+		// Resty only decodes error bodies of 4xx and 5xx responses
+		er, ok := resp.Error().(*ErrorResult)
+		if !ok || er == nil {
+			er = &ErrorResult{}
+		}
+		er.StatusCode = resp.StatusCode()
+		if resp.Request != nil {
+			er.Method = resp.Request.Method
+			er.URL = RedactURLPath(resp.Request.URL)
+			er.RequestID = requestID(resp)
+		}
+		er.RateLimitRemaining = resp.Header().Get(HeaderRateLimitRemaining)
 		log.Error().
+			Str("request_id", er.RequestID).
 			Int("StatusCode", resp.StatusCode()).
 			Str("Status", resp.Status()).
 			Interface("ErrorResult", er).
@@ -24,8 +24,11 @@ package client
 
 import (
 	"bytes"
+	"errors"
+	"github.com/jarcoal/httpmock"
 	"github.com/rs/zerolog/log"
 	"io"
+	"net/http"
 	"os"
 )
 
@@ -34,7 +37,7 @@ import (
 func (s *Suite) TestClientNoToken() {
 	var buf bytes.Buffer
 	log.Logger = log.Logger.Output(&buf)
-	NewClient(DefaultBaseURL, "") // Valid, but probably not what you want, thus warn
+	NewClient("") // Valid, but probably not what you want, thus warn
 	bs := buf.String()
 	s.NotZero(bs)
 	s.Contains(bs, "warn")
@@ -47,9 +50,36 @@ func (s *Suite) TestClientNoBaseURL() {
 	var buf bytes.Buffer
 	multiWriter := io.MultiWriter(os.Stderr, &buf)
 	log.Logger = log.Logger.Output(multiWriter)
-	NewClient("", "placeholder") // Invalid base URL
+	NewClient("placeholder", WithBaseURL("")) // Invalid base URL
 	bs := buf.String()
 	s.NotZero(bs)
 	s.Contains(bs, "error")
 	s.Contains(bs, "Rollbar API base URL not set")
 }
+
+// TestRequestID checks that each request carries a new correlation ID, which is
+// logged and recorded in the resulting error.
+func (s *Suite) TestRequestID() {
+	var ids []string
+	u := s.client.BaseURL + pathTeamList
+	httpmock.RegisterResponder("GET", u, func(req *http.Request) (*http.Response, error) {
+		ids = append(ids, req.Header.Get(HeaderRequestID))
+		return httpmock.NewStringResponse(http.StatusUnprocessableEntity, `{"err": 1, "message": "Something went wrong"}`), nil
+	})
+	logger := log.Logger
+	defer func() { log.Logger = logger }()
+	var buf bytes.Buffer
+	log.Logger = log.Logger.Output(&buf)
+
+	_, err := s.client.ListTeams()
+	var er *ErrorResult
+	s.True(errors.As(err, &er))
+	s.Len(ids, 1)
+	s.Len(ids[0], 16)
+	s.Equal(ids[0], er.RequestID)
+	s.Contains(buf.String(), `"request_id":"`+ids[0]+`"`)
+
+	_, _ = s.client.ListTeams()
+	s.Len(ids, 2)
+	s.NotEqual(ids[0], ids[1])
+}
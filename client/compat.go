@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// WithCompatibilityMode relaxes the client's assumptions about the shape of
+// API responses, for older self-hosted Rollbar servers: any 2xx status, such
+// as 202 Accepted or 204 No Content, is treated as success, where normally
+// only 200 OK and 201 Created are.  Missing response fields always decode to
+// their zero values.
+func WithCompatibilityMode() Option {
+	return func(o *options) {
+		o.compat = true
+	}
+}
+
+// normalizeSuccess is a Resty response middleware that reports any successful
+// response as 200 OK, so that errorFromResponse accepts it.
+func normalizeSuccess(_ *resty.Client, resp *resty.Response) error {
+	if resp.RawResponse == nil || !resp.IsSuccess() || resp.StatusCode() == http.StatusOK {
+		return nil
+	}
+	log.Debug().
+		Str("request_id", requestID(resp)).
+		Int("StatusCode", resp.StatusCode()).
+		Msg("Treating successful response as 200 OK")
+	resp.RawResponse.StatusCode = http.StatusOK
+	return nil
+}
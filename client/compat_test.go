@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompatibilityMode tests talking to an older self-hosted server, which
+// serves the API under a path prefix and answers creation with 202 Accepted.
+func TestCompatibilityMode(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(loadFixture("project/create.json")))
+	}))
+	defer srv.Close()
+
+	c := NewClient("fakeTokenString", WithBaseURL(srv.URL+"/rollbar"))
+	_, err := c.CreateProject("foobar")
+	assert.Equal(t, "/rollbar/api/1/projects", path)
+	er, ok := err.(*ErrorResult)
+	if assert.True(t, ok, "%v", err) {
+		assert.Equal(t, http.StatusAccepted, er.StatusCode)
+	}
+
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL+"/rollbar"), WithCompatibilityMode())
+	p, err := c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.Equal(t, "baz", p.Name)
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// EnableCompression configures the client to gzip compress request bodies of
+// at least minRequestSize bytes, such as large notification or project
+// configurations.  Rollbar does not document accepting compressed request
+// bodies, so compression is off unless enabled, and if minRequestSize is zero
+// or less, request bodies are sent uncompressed.  Responses are left to the
+// underlying transport: http.Transport requests gzip compressed responses and
+// decompresses them transparently, unless Accept-Encoding is set explicitly.
+func (c *RollbarAPIClient) EnableCompression(minRequestSize int) {
+	if minRequestSize <= 0 {
+		return
+	}
+	hc := c.Resty.GetClient()
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc.Transport = &compressionTransport{next: next, minRequestSize: minRequestSize}
+}
+
+// compressionTransport is an http.RoundTripper that gzip compresses request
+// bodies.
+type compressionTransport struct {
+	next           http.RoundTripper
+	minRequestSize int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.shouldCompress(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the request, so send a clone
+	out := req.Clone(req.Context())
+	body, err := ioutil.ReadAll(out.Body)
+	_ = out.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(body)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	compressed := buf.Bytes()
+	out.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	out.ContentLength = int64(len(compressed))
+	out.Header.Set("Content-Encoding", "gzip")
+	out.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+	return t.next.RoundTrip(out)
+}
+
+// shouldCompress returns true if the body of req is large enough to compress,
+// and not already encoded.
+func (t *compressionTransport) shouldCompress(req *http.Request) bool {
+	if t.minRequestSize <= 0 || req.Body == nil || req.Body == http.NoBody {
+		return false
+	}
+	if req.Header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return req.ContentLength >= int64(t.minRequestSize)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCompression tests gzip compressing request bodies, leaving compressed
+// responses to the underlying transport.
+func TestCompression(t *testing.T) {
+	var requestEncoding, requestName string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestEncoding = r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if requestEncoding == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = gz
+		}
+		var req struct {
+			Name string `json:"name"`
+		}
+		_ = json.NewDecoder(body).Decode(&req)
+		requestName = req.Name
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(loadFixture("project/create.json")))
+		_ = gz.Close()
+	}))
+	defer srv.Close()
+
+	// Compressed request body
+	c := NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	c.EnableCompression(1)
+	p, err := c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.NotZero(t, p.ID)
+	assert.Equal(t, "gzip", requestEncoding)
+	assert.Equal(t, "foobar", requestName)
+
+	// Request body below the compression threshold
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	c.EnableCompression(1024)
+	p, err = c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.NotZero(t, p.ID)
+	assert.Equal(t, "", requestEncoding)
+	assert.Equal(t, "foobar", requestName)
+
+	// Compression disabled
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	transport := c.Resty.GetClient().Transport
+	c.EnableCompression(0)
+	assert.Equal(t, transport, c.Resty.GetClient().Transport)
+	_, err = c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.Equal(t, "", requestEncoding)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// EnableConditionalRequests configures the client to remember the validators,
+// ETag and Last-Modified, of successful GET responses and to send conditional
+// requests when repeating them.  A 304 Not Modified response is treated as a
+// cache hit, and the remembered response body is returned in its place.  List
+// endpoints, whose responses are largest, benefit most.
+//
+// At most maxEntries responses are remembered, the least recently used being
+// forgotten first.  Responses of access token endpoints, whose bodies hold
+// token values, are never remembered.  If maxEntries is zero or less,
+// conditional requests are not enabled.
+func (c *RollbarAPIClient) EnableConditionalRequests(maxEntries int) {
+	if maxEntries <= 0 {
+		return
+	}
+	hc := c.Resty.GetClient()
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc.Transport = &conditionalTransport{
+		next:       next,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// conditionalEntry is a remembered GET response.
+type conditionalEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+// conditionalTransport is an http.RoundTripper that sends conditional GET
+// requests for responses it has seen before.
+type conditionalTransport struct {
+	next       http.RoundTripper
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]*list.Element // Elements of lru, by URL
+	lru        *list.List               // conditionalEntry values, most recently used first
+}
+
+// noConditionalKey is the context key marking requests whose responses the
+// conditional transport must not remember, e.g. listings streamed rather than
+// held in memory.
+type noConditionalKey struct{}
+
+// conditionalCacheable returns true if responses to req may be remembered.
+func conditionalCacheable(req *http.Request) bool {
+	if skip, _ := req.Context().Value(noConditionalKey{}).(bool); skip {
+		return false
+	}
+	return req.Method == http.MethodGet && !strings.Contains(req.URL.Path, "/access_token")
+}
+
+// get returns the entry remembered for key, if any, marking it most recently
+// used.
+func (t *conditionalTransport) get(key string) (conditionalEntry, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	el, ok := t.entries[key]
+	if !ok {
+		return conditionalEntry{}, false
+	}
+	t.lru.MoveToFront(el)
+	return el.Value.(conditionalEntry), true
+}
+
+// put remembers entry e, forgetting the least recently used entry if there
+// are too many.
+func (t *conditionalTransport) put(e conditionalEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if el, ok := t.entries[e.key]; ok {
+		el.Value = e
+		t.lru.MoveToFront(el)
+		return
+	}
+	t.entries[e.key] = t.lru.PushFront(e)
+	for t.lru.Len() > t.maxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(conditionalEntry).key)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !conditionalCacheable(req) {
+		return t.next.RoundTrip(req)
+	}
+	key := req.URL.String()
+	e, ok := t.get(key)
+
+	// A RoundTripper must not modify the request, so send a clone
+	out := req
+	if ok {
+		out = req.Clone(req.Context())
+		if e.etag != "" {
+			out.Header.Set("If-None-Match", e.etag)
+		}
+		if e.lastModified != "" {
+			out.Header.Set("If-Modified-Since", e.lastModified)
+		}
+	}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ok && resp.StatusCode == http.StatusNotModified:
+		_ = resp.Body.Close()
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        e.header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+			ContentLength: int64(len(e.body)),
+			Request:       req,
+		}, nil
+	case resp.StatusCode == http.StatusOK:
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return resp, nil
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		t.put(conditionalEntry{
+			key:          key,
+			etag:         etag,
+			lastModified: lastModified,
+			header:       resp.Header.Clone(),
+			body:         body,
+		})
+	}
+	return resp, nil
+}
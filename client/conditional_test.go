@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestConditionalRequests tests revalidating GET responses with ETag and
+// Last-Modified validators.
+func TestConditionalRequests(t *testing.T) {
+	const etag = `"abc123"`
+	const lastModified = "Wed, 14 Oct 2026 08:00:00 GMT"
+	body := loadFixture("project/list.json")
+	full, notModified := 0, 0
+	validator := "etag"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch validator {
+		case "etag":
+			if r.Header.Get("If-None-Match") == etag {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		case "last-modified":
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", lastModified)
+		}
+		full++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	c.EnableConditionalRequests(10)
+
+	// ETag
+	first, err := c.ListProjects()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, first)
+	second, err := c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, full)
+	assert.Equal(t, 1, notModified)
+
+	// Last-Modified
+	validator = "last-modified"
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, full)
+	assert.Equal(t, 2, notModified)
+
+	// Responses without validators are not revalidated
+	validator = ""
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, full)
+	assert.Equal(t, 2, notModified)
+}
+
+// TestConditionalRequestsBounded tests that conditional requests remember a
+// bounded number of responses, and never responses holding access tokens or
+// streamed listings.
+func TestConditionalRequestsBounded(t *testing.T) {
+	fixtures := map[string]string{
+		"/api/1/projects":                     loadFixture("project/list.json"),
+		"/api/1/project/411708":               loadFixture("project/read.json"),
+		"/api/1/project/411708/access_tokens": loadFixture("project_access_token/list.json"),
+		"/api/1/users":                        loadFixture("user/list.json"),
+	}
+	notModified := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified[r.URL.Path]++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fixtures[r.URL.Path]))
+	}))
+	defer srv.Close()
+
+	c := NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	c.EnableConditionalRequests(1)
+
+	// Reading the project forgets the list of projects
+	_, err := c.ListProjects()
+	assert.Nil(t, err)
+	_, err = c.ReadProject(411708)
+	assert.Nil(t, err)
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Empty(t, notModified)
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"/api/1/projects": 1}, notModified)
+
+	// Access tokens are always read in full
+	for i := 0; i < 2; i++ {
+		_, err = c.ListProjectAccessTokens(411708)
+		assert.Nil(t, err)
+	}
+	assert.Zero(t, notModified["/api/1/project/411708/access_tokens"])
+
+	// Nor are users, whose listing is streamed
+	for i := 0; i < 2; i++ {
+		_, err = c.ListUsers()
+		assert.Nil(t, err)
+	}
+	assert.Zero(t, notModified["/api/1/users"])
+
+	// Disabled
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL))
+	transport := c.Resty.GetClient().Transport
+	c.EnableConditionalRequests(0)
+	assert.Equal(t, transport, c.Resty.GetClient().Transport)
+}
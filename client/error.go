@@ -24,16 +24,61 @@ package client
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 )
 
+// HeaderRateLimitRemaining is the response header in which the Rollbar API
+// reports the number of requests remaining in the current rate limit window.
+const HeaderRateLimitRemaining = "X-Rate-Limit-Remaining"
+
 // ErrorResult represents an error result returned by Rollbar API.
 type ErrorResult struct {
 	Err     int
 	Message string
+
+	// The failed request, recorded by the client rather than returned by the
+	// API
+	Method             string `json:"-"`
+	URL                string `json:"-"` // Path only, with any access token redacted
+	StatusCode         int    `json:"-"`
+	RequestID          string `json:"-"` // Sent in header HeaderRequestID
+	RateLimitRemaining string `json:"-"` // From header HeaderRateLimitRemaining, if sent
 }
 
 func (er ErrorResult) Error() string {
-	return fmt.Sprintf("%v %v", er.Err, er.Message)
+	msg := fmt.Sprintf("%v %v", er.Err, er.Message)
+	if er.Method == "" {
+		return msg
+	}
+	msg = fmt.Sprintf("%s %s: HTTP %d: %s", er.Method, er.URL, er.StatusCode, msg)
+	var context []string
+	if er.RequestID != "" {
+		context = append(context, "request ID "+er.RequestID)
+	}
+	if er.RateLimitRemaining != "" {
+		context = append(context, "rate limit remaining "+er.RateLimitRemaining)
+	}
+	if len(context) > 0 {
+		msg += " (" + strings.Join(context, ", ") + ")"
+	}
+	return msg
+}
+
+// RedactURLPath returns the path of a request URL, with the access token in
+// any project access token path redacted.
+func RedactURLPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(u.Path, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i-1] == "access_token" && segments[i] != "" {
+			segments[i] = RedactToken(segments[i])
+		}
+	}
+	return strings.Join(segments, "/")
 }
 
 // ErrNotFound is returned when the API returns a '404 Not Found' error.
@@ -41,3 +86,18 @@ var ErrNotFound = fmt.Errorf("not found")
 
 // ErrUnauthorized is returned when the API returns a '401 Unauthorized' error.
 var ErrUnauthorized = fmt.Errorf("unauthorized")
+
+// UnauthorizedError is returned instead of ErrUnauthorized by a client
+// configured with WithTokenName.  It matches ErrUnauthorized with errors.Is.
+type UnauthorizedError struct {
+	TokenName string // Setting the rejected token was configured with
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("%v: check the token set by %s", ErrUnauthorized, e.TokenName)
+}
+
+// Unwrap returns ErrUnauthorized.
+func (e *UnauthorizedError) Unwrap() error {
+	return ErrUnauthorized
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// TestErrorResultError tests the text of Rollbar API error results.
+func TestErrorResultError(t *testing.T) {
+	er := ErrorResult{Err: 1, Message: "Something went wrong"}
+	assert.Equal(t, "1 Something went wrong", er.Error())
+
+	er.Method = "DELETE"
+	er.URL = "/api/1/project/1/access_token/****ae5a"
+	er.StatusCode = http.StatusInternalServerError
+	assert.Equal(t, "DELETE /api/1/project/1/access_token/****ae5a: HTTP 500: 1 Something went wrong", er.Error())
+
+	er.RequestID = "0123456789abcdef"
+	er.RateLimitRemaining = "42"
+	assert.Equal(t, "DELETE /api/1/project/1/access_token/****ae5a: HTTP 500: 1 Something went wrong (request ID 0123456789abcdef, rate limit remaining 42)", er.Error())
+}
+
+// TestRedactURLPath tests recording request URLs in errors without the
+// access tokens they may contain.
+func TestRedactURLPath(t *testing.T) {
+	assert.Equal(t, "/api/1/projects", RedactURLPath("https://api.rollbar.com/api/1/projects?page=2"))
+	assert.Equal(t, "/api/1/project/1/access_token/****ae5a", RedactURLPath("https://api.rollbar.com/api/1/project/1/access_token/d19f7ada16534b1c94e91d9da3dbae5a"))
+	assert.Equal(t, "/api/1/project/1/access_tokens", RedactURLPath("https://api.rollbar.com/api/1/project/1/access_tokens"))
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// fuzzStatuses are the HTTP status codes with which fuzzed responses are
+// served.  They cover success as well as each class of error the client
+// handles.
+var fuzzStatuses = []int{
+	http.StatusOK,
+	http.StatusCreated,
+	http.StatusNoContent,
+	http.StatusNotModified,
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusPaymentRequired,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusUnprocessableEntity,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+}
+
+// fuzzOp is a client method exercised by FuzzResponse, with a fixture used as
+// the seed for its response body.
+type fuzzOp struct {
+	fixture string
+	call    func(c *RollbarAPIClient) error
+}
+
+// fuzzOps are the client methods whose response parsing is fuzzed.
+var fuzzOps = []fuzzOp{
+	{"project/list.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListProjects()
+		return err
+	}},
+	{"project/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadProject(411334)
+		return err
+	}},
+	{"project/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateProject("foobar")
+		return err
+	}},
+	{"project_access_token/list.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListProjectAccessTokens(411334)
+		return err
+	}},
+	{"project_access_token/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadProjectAccessToken(411334, "fakeToken")
+		return err
+	}},
+	{"project_access_token/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateProjectAccessToken(ProjectAccessTokenCreateArgs{
+			ProjectID: 411334,
+			Name:      "foobar",
+			Scopes:    []Scope{ScopeRead},
+			Status:    StatusEnabled,
+		})
+		return err
+	}},
+	{"project_access_token/update.json", func(c *RollbarAPIClient) error {
+		return c.UpdateProjectAccessToken(ProjectAccessTokenUpdateArgs{
+			ProjectID:   411334,
+			AccessToken: "fakeToken",
+		})
+	}},
+	{"team/list.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListTeams()
+		return err
+	}},
+	{"team/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadTeam(676971)
+		return err
+	}},
+	{"team/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateTeam("foobar", "standard")
+		return err
+	}},
+	{"team/list_projects_689493.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListTeamProjectIDs(689493)
+		return err
+	}},
+	{"user/list.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListUsers()
+		return err
+	}},
+	{"user/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadUser(238101)
+		return err
+	}},
+	{"invitation/list.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListInvitations(676971)
+		return err
+	}},
+	{"notification/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadNotification(5127954, "email")
+		return err
+	}},
+	{"person/list_page1.json", func(c *RollbarAPIClient) error {
+		_, err := c.ListPeople()
+		return err
+	}},
+	{"item/read.json", func(c *RollbarAPIClient) error {
+		_, err := c.ReadItemByCounter(1)
+		return err
+	}},
+}
+
+// FuzzResponse serves arbitrary bodies with a variety of HTTP statuses to the
+// client's API methods, checking that malformed or surprising payloads produce
+// errors rather than panics.  The seed corpus is the sample API responses in
+// 'client/fixtures/'.
+//
+//	make fuzz
+func FuzzResponse(f *testing.F) {
+	for i, op := range fuzzOps {
+		body := []byte(loadFixture(op.fixture))
+		f.Add(uint8(i), uint8(0), body)
+		f.Add(uint8(i), uint8(4), []byte(`{"err": 1, "message": "Invalid request"}`))
+	}
+	f.Add(uint8(0), uint8(0), []byte(`{"err": 0, "result": null}`))
+	f.Add(uint8(0), uint8(0), []byte(`{"err": 0, "result": {}}`))
+	f.Add(uint8(0), uint8(0), []byte(`[]`))
+	f.Add(uint8(0), uint8(4), []byte(`{"err": "1", "message": 42}`))
+	f.Add(uint8(0), uint8(11), []byte(`<html>Internal Server Error</html>`))
+
+	f.Fuzz(func(t *testing.T, opIndex, statusIndex uint8, body []byte) {
+		op := fuzzOps[int(opIndex)%len(fuzzOps)]
+		status := fuzzStatuses[int(statusIndex)%len(fuzzStatuses)]
+		// Serve the fuzzed response once.  Methods that page through results
+		// then get a 404, rather than the same page forever.
+		var served int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.AddInt32(&served, 1) > 1 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write(body)
+		}))
+		defer ts.Close()
+		c := NewClient("fakeTokenString", WithBaseURL(ts.URL))
+		c.Resty.SetTransport(ts.Client().Transport)
+
+		err := op.call(c)
+		if err == nil && status >= http.StatusBadRequest {
+			t.Fatalf("%s: no error for HTTP status %d", op.fixture, status)
+		}
+		var er *ErrorResult
+		if errors.As(err, &er) && er.StatusCode == 0 {
+			t.Fatalf("%s: ErrorResult for HTTP status %d has no status code", op.fixture, status)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/jarcoal/httpmock"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+/*
+ * Golden files for request bodies
+ *
+ * Each case below makes one create or update call and compares the JSON body
+ * sent to the API, byte for byte, with a golden file in
+ * 'client/fixtures/requests/'.  After an intended change to a request body,
+ * regenerate the golden files with:
+ *
+ *	ROLLBAR_GOLDEN_UPDATE=1 go test ./client -run TestRollbarClientTestSuite/TestRequestBodies
+ */
+
+// goldenFolder holds the expected request bodies.
+const goldenFolder = "fixtures/requests/"
+
+// goldenCase is a client call whose request body is compared to a golden file.
+type goldenCase struct {
+	golden  string // Name of the golden file
+	fixture string // Response fixture served to the call
+	call    func(c *RollbarAPIClient) error
+}
+
+// goldenCases are the client calls whose request bodies are checked.
+var goldenCases = []goldenCase{
+	{"project_create.json", "project/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateProject("foobar")
+		return err
+	}},
+	{"project_update.json", "project/update.json", func(c *RollbarAPIClient) error {
+		_, err := c.UpdateProject(411334, "foobar-renamed")
+		return err
+	}},
+	{"project_access_token_create.json", "project_access_token/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateProjectAccessToken(ProjectAccessTokenCreateArgs{
+			ProjectID: 411334,
+			Name:      "foobar",
+			Scopes:    []Scope{ScopeRead, ScopeWrite},
+			Status:    StatusEnabled,
+		})
+		return err
+	}},
+	{"project_access_token_create_rate_limit.json", "project_access_token/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateProjectAccessToken(ProjectAccessTokenCreateArgs{
+			ProjectID:            411334,
+			Name:                 "foobar",
+			Scopes:               []Scope{ScopePostServerItem},
+			Status:               StatusDisabled,
+			RateLimitWindowSize:  60,
+			RateLimitWindowCount: 500,
+		})
+		return err
+	}},
+	{"project_access_token_update.json", "project_access_token/update.json", func(c *RollbarAPIClient) error {
+		return c.UpdateProjectAccessToken(ProjectAccessTokenUpdateArgs{
+			ProjectID:            411334,
+			AccessToken:          "fakeToken",
+			RateLimitWindowSize:  3600,
+			RateLimitWindowCount: 1000,
+			Status:               StatusDisabled,
+		})
+	}},
+	// A blank status leaves the token's status unchanged, so must be omitted
+	{"project_access_token_update_no_status.json", "project_access_token/update.json", func(c *RollbarAPIClient) error {
+		return c.UpdateProjectAccessToken(ProjectAccessTokenUpdateArgs{
+			ProjectID:   411334,
+			AccessToken: "fakeToken",
+		})
+	}},
+	{"team_create.json", "team/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateTeam("foobar", "standard")
+		return err
+	}},
+	{"team_update.json", "team/update.json", func(c *RollbarAPIClient) error {
+		_, err := c.UpdateTeam(676971, "foobar-renamed", "light")
+		return err
+	}},
+	{"invitation_create.json", "invitation/create.json", func(c *RollbarAPIClient) error {
+		_, err := c.CreateInvitation(676971, "someone@example.com")
+		return err
+	}},
+	{"notification_create.json", "notification/create.json", func(c *RollbarAPIClient) error {
+		filters := []map[string]interface{}{{"type": "level", "operation": "gte", "value": "error"}}
+		config := map[string]interface{}{"users": []string{"someone@example.com"}, "teams": []string{}}
+		_, err := c.CreateNotification("email", filters, "new_item", config)
+		return err
+	}},
+	{"notification_update.json", "notification/update.json", func(c *RollbarAPIClient) error {
+		filters := []map[string]interface{}{{"type": "rate", "period": 300, "count": 10}}
+		config := map[string]interface{}{"users": []string{}, "teams": []string{"foobar"}}
+		_, err := c.UpdateNotification(5127954, "email", filters, "occurrence_rate", config)
+		return err
+	}},
+}
+
+// TestRequestBodies tests the JSON bodies sent by create and update calls
+// against golden files.
+func (s *Suite) TestRequestBodies() {
+	update := os.Getenv("ROLLBAR_GOLDEN_UPDATE") == "1"
+	for _, gc := range goldenCases {
+		var bodies []string
+		httpmock.Reset()
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			if req.Body != nil {
+				b, err := ioutil.ReadAll(req.Body)
+				s.Nil(err)
+				if len(b) > 0 {
+					bodies = append(bodies, string(b))
+				}
+			}
+			return responseFromFixture(gc.fixture, http.StatusOK), nil
+		})
+		err := gc.call(s.client)
+		s.Nil(err, gc.golden)
+		if !s.Len(bodies, 1, gc.golden) {
+			continue
+		}
+
+		path := goldenFolder + gc.golden
+		if update {
+			err = ioutil.WriteFile(path, []byte(bodies[0]+"\n"), 0600)
+			s.Nil(err)
+			continue
+		}
+		b, err := ioutil.ReadFile(path) // #nosec
+		s.Nil(err, "missing golden file; set ROLLBAR_GOLDEN_UPDATE=1 to create it")
+		s.Equal(strings.TrimSuffix(string(b), "\n"), bodies[0], gc.golden)
+	}
+}
@@ -54,7 +54,7 @@ func (c *RollbarAPIClient) ListInvitations(teamID int) (invs []Invitation, err e
 	l.Debug().Msg("Listing invitations")
 
 	for hasNextPage {
-		resp, err := c.Resty.R().
+		resp, err := c.request().
 			SetPathParams(map[string]string{
 				"teamID": strconv.Itoa(teamID),
 			}).
@@ -136,7 +136,7 @@ func (c *RollbarAPIClient) CreateInvitation(teamID int, email string) (Invitatio
 
 	u := c.BaseURL + pathInvitations
 	var inv Invitation
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID": strconv.Itoa(teamID),
 		}).
@@ -169,7 +169,7 @@ func (c *RollbarAPIClient) ReadInvitation(inviteID int) (inv Invitation, err err
 	l.Debug().Msg("Reading invitation from Rollbar API")
 	u := c.BaseURL + pathInvitation
 	u = strings.ReplaceAll(u, "{inviteID}", strconv.Itoa(inviteID))
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(invitationResponse{}).
 		SetError(ErrorResult{}).
 		Get(u)
@@ -200,7 +200,7 @@ func (c *RollbarAPIClient) CancelInvitation(id int) (err error) {
 	l.Debug().Msg("Canceling invitation")
 
 	u := c.BaseURL + pathInvitation
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"inviteID": strconv.Itoa(id),
 		}).
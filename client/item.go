@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Item represents a Rollbar item, a group of similar occurrences.
+type Item struct {
+	ID          int    `mapstructure:"id"`
+	Counter     int    `mapstructure:"counter"`
+	ProjectID   int    `json:"project_id" mapstructure:"project_id"`
+	Title       string `mapstructure:"title"`
+	Level       string `mapstructure:"level"`
+	Status      string `mapstructure:"status"`
+	Environment string `mapstructure:"environment"`
+}
+
+// ReadItemByCounter reads a Rollbar item by its project counter, the number
+// shown in the item's URL in the Rollbar UI.  If no matching item is found,
+// returns error ErrNotFound.
+func (c *RollbarAPIClient) ReadItemByCounter(counter int) (Item, error) {
+	var item Item
+	l := log.With().Int("counter", counter).Logger()
+	l.Debug().Msg("Reading item by counter from API")
+	// The API answers with a redirect to the item, which is followed
+	// transparently.
+	resp, err := c.request().
+		SetPathParams(map[string]string{"counter": strconv.Itoa(counter)}).
+		SetResult(itemReadResponse{}).
+		SetError(ErrorResult{}).
+		Get(c.BaseURL + pathItemByCounter)
+	if err != nil {
+		l.Err(err).Msg("Error reading item by counter from API")
+		return item, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error reading item by counter from API")
+		return item, err
+	}
+	item = resp.Result().(*itemReadResponse).Result
+	l.Debug().Int("item_id", item.ID).Msg("Successfully read item by counter from API")
+	return item, nil
+}
+
+// Occurrence represents a single occurrence of a Rollbar item.
+type Occurrence struct {
+	ID        int `json:"id"`
+	Timestamp int `json:"timestamp"`
+	Data      struct {
+		UUID string `json:"uuid"`
+	} `json:"data"`
+}
+
+// UUID returns the UUID of the occurrence, as shown in the Rollbar UI.
+func (o Occurrence) UUID() string {
+	return o.Data.UUID
+}
+
+// ListItemOccurrences lists one page of occurrences of a Rollbar item, most
+// recent first.  Pages are numbered from 1; an empty page means there are no
+// more occurrences.
+func (c *RollbarAPIClient) ListItemOccurrences(itemID, page int) ([]Occurrence, error) {
+	l := log.With().
+		Int("itemID", itemID).
+		Int("page", page).
+		Logger()
+	l.Debug().Msg("Listing item occurrences")
+	resp, err := c.request().
+		SetPathParams(map[string]string{"itemID": strconv.Itoa(itemID)}).
+		SetResult(occurrenceListResponse{}).
+		SetError(ErrorResult{}).
+		Get(c.BaseURL + pathItemOccurrences + fmt.Sprintf("?page=%d", page))
+	if err != nil {
+		l.Err(err).Msg("Error listing item occurrences")
+		return nil, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error listing item occurrences")
+		return nil, err
+	}
+	occurrences := resp.Result().(*occurrenceListResponse).Result.Instances
+	l.Debug().
+		Int("count", len(occurrences)).
+		Msg("Successfully listed item occurrences")
+	return occurrences, nil
+}
+
+/*
+ * Containers for unmarshalling Rollbar API responses
+ */
+
+type itemReadResponse struct {
+	Err    int  `json:"err"`
+	Result Item `json:"result"`
+}
+
+type occurrenceListResponse struct {
+	Err    int `json:"err"`
+	Result struct {
+		Instances []Occurrence `json:"instances"`
+	} `json:"result"`
+}
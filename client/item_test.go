@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// TestReadItemByCounter tests reading a Rollbar item by its project counter.
+func (s *Suite) TestReadItemByCounter() {
+	counter := 42
+	itemID := 1021510621
+	u := s.client.BaseURL + pathItemByCounter
+	u = strings.ReplaceAll(u, "{counter}", strconv.Itoa(counter))
+	itemURL := s.client.BaseURL + pathItem
+	itemURL = strings.ReplaceAll(itemURL, "{itemID}", strconv.Itoa(itemID))
+
+	// The API redirects to the item
+	redirect, err := httpmock.NewJsonResponse(http.StatusMovedPermanently, nil)
+	s.Nil(err)
+	redirect.Header.Set("Location", itemURL)
+	httpmock.RegisterResponder("GET", u, httpmock.ResponderFromResponse(redirect))
+	r := responderFromFixture("item/read.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", itemURL, r)
+	expected := Item{
+		ID:          itemID,
+		Counter:     counter,
+		ProjectID:   411703,
+		Title:       "TypeError: Cannot read property 'foo' of undefined",
+		Level:       "error",
+		Status:      "active",
+		Environment: "production",
+	}
+	actual, err := s.client.ReadItemByCounter(counter)
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	s.checkServerErrors("GET", u, func() error {
+		_, err := s.client.ReadItemByCounter(counter)
+		return err
+	})
+}
+
+// TestListItemOccurrences tests listing occurrences of a Rollbar item.
+func (s *Suite) TestListItemOccurrences() {
+	itemID := 1021510621
+	u := s.client.BaseURL + pathItemOccurrences
+	u = strings.ReplaceAll(u, "{itemID}", strconv.Itoa(itemID))
+	r := responderFromFixture("item/list_occurrences.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=1", r)
+
+	actual, err := s.client.ListItemOccurrences(itemID, 1)
+	s.Nil(err)
+	s.Len(actual, 2)
+	s.Equal(3145844542, actual[0].ID)
+	s.Equal(1602085345, actual[0].Timestamp)
+	s.Equal("6e8b3f2c-6d1b-4b5a-9a3e-5c2f3c1d2e4f", actual[0].UUID())
+
+	s.checkServerErrors("GET", u+"?page=1", func() error {
+		_, err := s.client.ListItemOccurrences(itemID, 1)
+		return err
+	})
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+/*
+ * Iterators
+ *
+ * Iterators lazily fetch a list one page at a time, so that callers looking for
+ * particular records can stop early rather than listing everything.  They are
+ * used like bufio.Scanner:
+ *
+ *	it := c.PeopleIterator()
+ *	for it.Next() {
+ *		p := it.Person()
+ *		...
+ *	}
+ *	if err := it.Err(); err != nil {
+ *		...
+ *	}
+ */
+
+// pageIterator iterates over records fetched one page at a time.  Pages are
+// numbered from 1; an empty page means there are no more records.  If the
+// endpoint is not paginated, the single page holds all records.
+type pageIterator[T any] struct {
+	fetch     func(page int) ([]T, error)
+	paginated bool
+	page      int
+	buf       []T
+	cur       T
+	done      bool
+	err       error
+}
+
+// Next advances to the next record, fetching another page if needed.  It returns
+// false when there are no more records or an error occurs.
+func (it *pageIterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.page++
+		buf, err := it.fetch(it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !it.paginated || len(buf) == 0 {
+			it.done = true
+		}
+		it.buf = buf
+	}
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+// Err returns the first error encountered while fetching pages, if any.
+func (it *pageIterator[T]) Err() error {
+	return it.err
+}
+
+// Page returns the number of the last page fetched.
+func (it *pageIterator[T]) Page() int {
+	return it.page
+}
+
+// ProjectsIterator iterates over all Rollbar projects.
+type ProjectsIterator struct {
+	pageIterator[Project]
+}
+
+// ProjectsIterator returns an iterator over all Rollbar projects.  The API does
+// not paginate projects, so they are all fetched by the first call to Next.
+func (c *RollbarAPIClient) ProjectsIterator() *ProjectsIterator {
+	return &ProjectsIterator{pageIterator[Project]{
+		fetch: func(int) ([]Project, error) {
+			return c.ListProjects()
+		},
+	}}
+}
+
+// Project returns the current project.
+func (it *ProjectsIterator) Project() Project {
+	return it.cur
+}
+
+// TokensIterator iterates over the access tokens of a Rollbar project.
+type TokensIterator struct {
+	pageIterator[ProjectAccessToken]
+}
+
+// TokensIterator returns an iterator over the access tokens of a Rollbar
+// project.  The API does not paginate tokens, so they are all fetched by the
+// first call to Next.
+func (c *RollbarAPIClient) TokensIterator(projectID int) *TokensIterator {
+	return &TokensIterator{pageIterator[ProjectAccessToken]{
+		fetch: func(int) ([]ProjectAccessToken, error) {
+			return c.ListProjectAccessTokens(projectID)
+		},
+	}}
+}
+
+// Token returns the current project access token.
+func (it *TokensIterator) Token() ProjectAccessToken {
+	return it.cur
+}
+
+// PeopleIterator iterates over the persons tracked by the project to which the
+// client's project access token belongs.
+type PeopleIterator struct {
+	pageIterator[Person]
+}
+
+// PeopleIterator returns an iterator over the persons tracked by the project to
+// which the client's project access token belongs.
+func (c *RollbarAPIClient) PeopleIterator() *PeopleIterator {
+	return &PeopleIterator{pageIterator[Person]{
+		fetch:     c.listPeoplePage,
+		paginated: true,
+	}}
+}
+
+// Person returns the current person.
+func (it *PeopleIterator) Person() Person {
+	return it.cur
+}
+
+// OccurrencesIterator iterates over the occurrences of a Rollbar item.
+type OccurrencesIterator struct {
+	pageIterator[Occurrence]
+}
+
+// OccurrencesIterator returns an iterator over the occurrences of a Rollbar
+// item, most recent first.
+func (c *RollbarAPIClient) OccurrencesIterator(itemID int) *OccurrencesIterator {
+	return &OccurrencesIterator{pageIterator[Occurrence]{
+		fetch: func(page int) ([]Occurrence, error) {
+			return c.ListItemOccurrences(itemID, page)
+		},
+		paginated: true,
+	}}
+}
+
+// Occurrence returns the current occurrence.
+func (it *OccurrencesIterator) Occurrence() Occurrence {
+	return it.cur
+}
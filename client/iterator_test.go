@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/jarcoal/httpmock"
+	"net/http"
+)
+
+// TestPeopleIterator tests lazily listing people, stopping early.
+func (s *Suite) TestPeopleIterator() {
+	httpmock.ZeroCallCounters()
+	u := s.client.BaseURL + pathPeople
+	r := responderFromFixture("person/list_page1.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=1", r)
+	r = responderFromFixture("person/list_page2.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=2", r)
+
+	// Stopping at the first match fetches a single page
+	it := s.client.PeopleIterator()
+	s.True(it.Next())
+	s.Equal("alice", it.Person().Username)
+	s.Nil(it.Err())
+	s.Equal(1, it.Page())
+	s.Equal(0, httpmock.GetCallCountInfo()["GET "+u+"?page=2"])
+
+	// Iterating to the end fetches every page
+	it = s.client.PeopleIterator()
+	var usernames []string
+	for it.Next() {
+		usernames = append(usernames, it.Person().Username)
+	}
+	s.Nil(it.Err())
+	s.Equal([]string{"alice", "bob"}, usernames)
+	s.Equal(2, it.Page())
+	s.False(it.Next())
+
+	// Errors stop iteration
+	s.checkServerErrors("GET", u+"?page=1", func() error {
+		it := s.client.PeopleIterator()
+		s.False(it.Next())
+		return it.Err()
+	})
+}
+
+// TestProjectsIterator tests iterating over the unpaginated project list.
+func (s *Suite) TestProjectsIterator() {
+	u := s.client.BaseURL + pathProjectList
+	r := responderFromFixture("project/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+
+	projects, err := s.client.ListProjects()
+	s.Nil(err)
+	it := s.client.ProjectsIterator()
+	var actual []Project
+	for it.Next() {
+		actual = append(actual, it.Project())
+	}
+	s.Nil(it.Err())
+	s.Equal(projects, actual)
+	s.Equal(1, it.Page())
+}
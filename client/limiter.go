@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Limiter limits the number of concurrent requests made to the Rollbar API.  A
+// single Limiter may be shared between several clients, so that all of them
+// together stay within the limit.
+type Limiter struct {
+	sem chan struct{}
+}
+
+// NewLimiter creates a Limiter allowing at most n concurrent requests.  A nil
+// Limiter, returned when n is zero or less, imposes no limit.
+func NewLimiter(n int) *Limiter {
+	if n <= 0 {
+		return nil
+	}
+	return &Limiter{sem: make(chan struct{}, n)}
+}
+
+// SetLimiter configures the client to wait for a free slot in Limiter l before
+// sending each request.
+func (c *RollbarAPIClient) SetLimiter(l *Limiter) {
+	if l == nil {
+		return
+	}
+	hc := c.Resty.GetClient()
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc.Transport = &limitedTransport{limiter: l, next: next}
+}
+
+// limitedTransport is an http.RoundTripper that holds a slot in a Limiter for
+// the duration of each request, until its response body is closed.
+type limitedTransport struct {
+	limiter *Limiter
+	next    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.limiter.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-t.limiter.sem })
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// limitedBody is a response body that frees its request's slot in a Limiter
+// once closed.
+type limitedBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements io.Closer.
+func (b *limitedBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyRecorder is an http.RoundTripper that records the greatest number
+// of requests it has handled at once.
+type concurrencyRecorder struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (r *concurrencyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.current++
+	if r.current > r.max {
+		r.max = r.current
+	}
+	r.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	r.mu.Lock()
+	r.current--
+	r.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"err": 0, "result": []}`)),
+		Request:    req,
+	}, nil
+}
+
+// TestLimiter tests limiting concurrent requests shared between clients.
+func TestLimiter(t *testing.T) {
+	syncLogger(t)
+	assert.Nil(t, NewLimiter(0))
+
+	rec := &concurrencyRecorder{}
+	l := NewLimiter(2)
+	clients := []*RollbarAPIClient{
+		NewClient("fakeTokenString"),
+		NewClient("fakeTokenString"),
+	}
+	for _, c := range clients {
+		c.Resty.GetClient().Transport = rec
+		c.SetLimiter(l)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(c *RollbarAPIClient) {
+			defer wg.Done()
+			_, err := c.ListProjects()
+			assert.Nil(t, err)
+		}(clients[i%2])
+	}
+	wg.Wait()
+	assert.Equal(t, 2, rec.max)
+
+	// A slot is held until the response body is closed
+	l = NewLimiter(1)
+	lt := &limitedTransport{limiter: l, next: rec}
+	req, err := http.NewRequest(http.MethodGet, "https://api.rollbar.com/api/1/projects", nil)
+	assert.Nil(t, err)
+	resp, err := lt.RoundTrip(req)
+	assert.Nil(t, err)
+	_, err = ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Len(t, l.sem, 1)
+	assert.Nil(t, resp.Body.Close())
+	assert.Len(t, l.sem, 0)
+	assert.Nil(t, resp.Body.Close())
+	assert.Len(t, l.sem, 0)
+}
@@ -46,7 +46,7 @@ func (c *RollbarAPIClient) CreateNotification(channel string, filters, trigger,
 		Logger()
 	l.Debug().Msg("Creating new notification")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetBody([]map[string]interface{}{{"filters": filters, "trigger": trigger, "config": config}}).
 		SetResult(notificationsResponse{}).
 		SetError(ErrorResult{}).
@@ -66,6 +66,36 @@ func (c *RollbarAPIClient) CreateNotification(channel string, filters, trigger,
 
 }
 
+// ListNotifications lists the notification rules of channel `channel` in the
+// project of the client's project access token.
+func (c *RollbarAPIClient) ListNotifications(channel string) ([]Notification, error) {
+	u := c.BaseURL + pathNotificationList
+	l := log.With().
+		Str("channel", channel).
+		Logger()
+	l.Debug().Msg("Listing notifications")
+
+	resp, err := c.request().
+		SetResult(notificationsResponse{}).
+		SetError(ErrorResult{}).
+		SetPathParams(map[string]string{
+			"channel": channel,
+		}).
+		Get(u)
+	if err != nil {
+		l.Err(err).Msg("Error listing notifications")
+		return nil, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Send()
+		return nil, err
+	}
+	nr := resp.Result().(*notificationsResponse)
+	l.Debug().Int("count", len(nr.Result)).Msg("Notifications successfully listed")
+	return nr.Result, nil
+}
+
 // UpdateNotification updates a Rollbar notification.
 func (c *RollbarAPIClient) UpdateNotification(notificationID int, channel string, filters, trigger, config interface{}) (*Notification, error) {
 	u := c.BaseURL + pathNotificationReadOrDeleteOrUpdate
@@ -74,7 +104,7 @@ func (c *RollbarAPIClient) UpdateNotification(notificationID int, channel string
 		Logger()
 	l.Debug().Msg("Updating notification")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetBody(map[string]interface{}{"filters": filters, "trigger": trigger, "config": config}).
 		SetResult(notificationResponse{}).
 		SetError(ErrorResult{}).
@@ -108,7 +138,7 @@ func (c *RollbarAPIClient) ReadNotification(notificationID int, channel string)
 		Logger()
 	l.Debug().Msg("Reading notification from API")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(notificationResponse{}).
 		SetError(ErrorResult{}).
 		SetPathParams(map[string]string{
@@ -145,7 +175,7 @@ func (c *RollbarAPIClient) DeleteNotification(notificationID int, channel string
 		Logger()
 	l.Debug().Msg("Deleting notification")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetError(ErrorResult{}).
 		SetPathParams(map[string]string{
 			"notificationID": strconv.Itoa(notificationID),
@@ -100,6 +100,27 @@ func (s *Suite) TestUpdateNotification() {
 	})
 }
 
+// TestListNotifications tests listing the Rollbar notifications of a channel.
+func (s *Suite) TestListNotifications() {
+	channel := "email"
+	u := s.client.BaseURL + pathNotificationList
+	u = strings.ReplaceAll(u, "{channel}", channel)
+
+	r := responderFromFixture("notification/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+	notifications, err := s.client.ListNotifications(channel)
+	s.Nil(err)
+	s.Len(notifications, 2)
+	s.Equal(5127954, notifications[0].ID)
+	s.Equal("new_item", notifications[0].Trigger)
+	s.Equal("occurrence_rate", notifications[1].Trigger)
+
+	s.checkServerErrors("GET", u, func() error {
+		_, err := s.client.ListNotifications(channel)
+		return err
+	})
+}
+
 // TestReadNotification tests reading a Rollbar notification.
 func (s *Suite) TestReadNotification() {
 
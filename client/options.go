@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"time"
+)
+
+// Option configures a client constructed by NewClient.
+type Option func(*options)
+
+// options collects the Options passed to NewClient, so they take effect in a
+// fixed order whatever order they are given in.
+type options struct {
+	baseURL      string
+	httpClient   *http.Client
+	transport    *TransportOptions
+	limiter      *Limiter
+	retries      int
+	retryWait    time.Duration
+	retryMaxWait time.Duration
+	userAgent    string
+	auth         authStrategy
+	compat       bool
+	tokenName    string
+}
+
+// WithBaseURL sets the base URL of the Rollbar API, replacing DefaultBaseURL,
+// e.g. to use a proxy or a fake API in tests.  Any path in baseURL, such as
+// that of a self-hosted server, prefixes every API path.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sends requests with hc, e.g. to reuse a program's own
+// instrumented HTTP client.  Its transport is wrapped, not replaced, by the
+// client's limiting, compression and conditional request handling, but is
+// replaced if WithTransportOptions is also given.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = hc
+	}
+}
+
+// WithTransportOptions tunes the client's HTTP connections, as
+// SetTransportOptions does.
+func WithTransportOptions(t TransportOptions) Option {
+	return func(o *options) {
+		o.transport = &t
+	}
+}
+
+// WithRateLimit makes the client hold a slot in Limiter l for the duration of
+// each request.  A Limiter shared between clients bounds the number of
+// requests they make at once to the Rollbar API, whose rate limits are easily
+// exceeded by many concurrent requests.  A nil Limiter imposes no limit.
+func WithRateLimit(l *Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithRetry makes the client retry a request up to `count` more times when it
+// fails with HTTP status 429 Too Many Requests, or, for idempotent requests
+// only, a network error, or for GET requests only, a 5xx server error.  Retries back off exponentially from
+// `wait` to at most `maxWait`; zero values keep resty's defaults of 100
+// milliseconds and 2 seconds.
+func WithRetry(count int, wait, maxWait time.Duration) Option {
+	return func(o *options) {
+		o.retries = count
+		o.retryWait = wait
+		o.retryMaxWait = maxWait
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request, so that the
+// program using the client can be identified.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithTokenName names the setting the client's access token was configured
+// with, e.g. a provider argument, so that when the API rejects the token the
+// error returned, an UnauthorizedError, says which one to check.
+func WithTokenName(name string) Option {
+	return func(o *options) {
+		o.tokenName = name
+	}
+}
+
+// setRetry configures the client's retries, as described by WithRetry.
+func (c *RollbarAPIClient) setRetry(count int, wait, maxWait time.Duration) {
+	c.Resty.SetRetryCount(count)
+	if wait > 0 {
+		c.Resty.SetRetryWaitTime(wait)
+	}
+	if maxWait > 0 {
+		c.Resty.SetRetryMaxWaitTime(maxWait)
+	}
+	c.Resty.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false // Abandoned, e.g. when Terraform is interrupted
+		}
+		if err != nil || resp == nil {
+			// Network error, after which the request may have been
+			// processed anyway
+			return resp != nil && resp.Request != nil && idempotent(resp.Request.Method)
+		}
+		switch {
+		case resp.StatusCode() == http.StatusTooManyRequests:
+			return true
+		case resp.StatusCode() >= http.StatusInternalServerError:
+			return resp.Request != nil && resp.Request.Method == http.MethodGet
+		}
+		return false
+	})
+}
+
+// idempotent returns true if repeating a request with `method` has the same
+// effect as sending it once, as defined by RFC 9110.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTransport is an http.RoundTripper that counts requests, failing the
+// next `fail` of them with a network error.
+type countingTransport struct {
+	n    int
+	fail int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n++
+	if t.fail > 0 {
+		t.fail--
+		return nil, errors.New("connection reset by peer")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestOptions tests constructing a client with functional options.
+func TestOptions(t *testing.T) {
+	var userAgent string
+	failures := 0 // Number of requests to fail before succeeding
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		if failures > 0 {
+			failures--
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"err": 1, "message": "Try again"}`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(loadFixture("project/create.json")))
+			return
+		}
+		_, _ = w.Write([]byte(loadFixture("project/list.json")))
+	}))
+	defer srv.Close()
+
+	// Defaults
+	c := NewClient("fakeTokenString")
+	assert.Equal(t, DefaultBaseURL, c.BaseURL)
+
+	// Base URL, HTTP client and user agent
+	ct := &countingTransport{}
+	c = NewClient("fakeTokenString",
+		WithBaseURL(srv.URL),
+		WithHTTPClient(&http.Client{Transport: ct}),
+		WithUserAgent("my-program/1.0"),
+	)
+	_, err := c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, ct.n)
+	assert.Equal(t, "my-program/1.0", userAgent)
+
+	// Without retries, the first failure is returned
+	failures = 1
+	_, err = c.ListProjects()
+	assert.NotNil(t, err)
+
+	// GET requests are retried on server errors
+	ct = &countingTransport{}
+	c = NewClient("fakeTokenString",
+		WithBaseURL(srv.URL),
+		WithHTTPClient(&http.Client{Transport: ct}),
+		WithRetry(2, time.Millisecond, time.Millisecond),
+	)
+	failures = 2
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, ct.n)
+
+	// Other requests are not, lest they be repeated
+	ct.n = 0
+	failures = 1
+	_, err = c.CreateProject("foobar")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, ct.n)
+	failures = 0
+
+	// Likewise on network errors, as the request may have reached the API
+	ct.n = 0
+	ct.fail = 1
+	_, err = c.CreateProject("foobar")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, ct.n)
+	ct.n = 0
+	ct.fail = 1
+	_, err = c.ListProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, ct.n)
+
+	// But all requests are retried when rate limited
+	ct.n = 0
+	failures = 1
+	status = http.StatusTooManyRequests
+	_, err = c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, ct.n)
+
+	// A rejected token is reported by the name it was configured with
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL), WithTokenName("api_key"))
+	failures = 1
+	status = http.StatusUnauthorized
+	_, err = c.ListProjects()
+	var ue *UnauthorizedError
+	assert.True(t, errors.As(err, &ue), "%v", err)
+	assert.Equal(t, "api_key", ue.TokenName)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.EqualError(t, err, "unauthorized: check the token set by api_key")
+
+	// Requests wait for a slot in a rate limiting Limiter
+	l := NewLimiter(1)
+	c = NewClient("fakeTokenString", WithBaseURL(srv.URL), WithRateLimit(l))
+	l.sem <- struct{}{}
+	done := make(chan error)
+	go func() {
+		_, err := c.ListProjects()
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("request should wait for the limiter")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-l.sem
+	assert.Nil(t, <-done)
+}
+
+// TestStopContext tests that stopping a client abandons its requests at once,
+// whether waiting for a response or between retries.
+func TestStopContext(t *testing.T) {
+	var hang atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"err": 1, "message": "Try again"}`))
+	}))
+	defer srv.Close()
+	c := NewClient("fakeTokenString",
+		WithBaseURL(srv.URL),
+		WithRetry(5, 2*time.Second, 2*time.Second),
+	)
+
+	for _, h := range []bool{true, false} {
+		hang.Store(h)
+		stop, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		start := time.Now()
+		_, err := c.WithStopContext(stop).WithContext(context.Background()).ListProjects()
+		assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+		assert.Less(t, time.Since(start), time.Second)
+	}
+
+	// Operation contexts still apply
+	stop, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx, cancelOp := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelOp()
+	_, err := c.WithStopContext(stop).WithContext(ctx).ListProjects()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+}
@@ -28,20 +28,29 @@ const (
 	pathProjectDelete                    = "/api/1/project/{projectID}"
 	pathProjectList                      = "/api/1/projects"
 	pathProjectRead                      = "/api/1/project/{projectID}"
+	pathProjectUpdate                    = "/api/1/project/{projectID}"
 	pathProjectToken                     = "/api/1/project/{projectID}/access_token/{accessToken}"
 	pathProjectTokens                    = "/api/1/project/{projectID}/access_tokens"
 	pathTeamCreate                       = "/api/1/teams"
 	pathTeamRead                         = "/api/1/team/{teamID}"
 	pathTeamList                         = "/api/1/teams"
 	pathTeamDelete                       = "/api/1/team/{teamID}"
+	pathTeamUpdate                       = "/api/1/team/{teamID}"
 	pathTeamUser                         = "/api/1/team/{teamID}/user/{userID}"
+	pathTeamUsers                        = "/api/1/team/{teamID}/users"
 	pathTeamProject                      = "/api/1/team/{teamID}/project/{projectID}"
 	pathTeamProjects                     = "/api/1/team/{teamID}/projects"
 	pathUser                             = "/api/1/user/{userID}"
 	pathUserTeams                        = "/api/1/user/{userID}/teams"
 	pathUsers                            = "/api/1/users"
+	pathItem                             = "/api/1/item/{itemID}"
+	pathItemByCounter                    = "/api/1/item_by_counter/{counter}"
+	pathItemOccurrences                  = "/api/1/item/{itemID}/instances"
+	pathPeople                           = "/api/1/people"
+	pathPerson                           = "/api/1/person/{personID}"
 	pathInvitation                       = "/api/1/invite/{inviteID}"
 	pathInvitations                      = "/api/1/team/{teamID}/invites"
 	pathNotificationCreate               = "/api/1/notifications/{channel}/rules"
+	pathNotificationList                 = "/api/1/notifications/{channel}/rules"
 	pathNotificationReadOrDeleteOrUpdate = "/api/1/notifications/{channel}/rule/{notificationID}"
 )
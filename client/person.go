@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Person represents a person tracked by a Rollbar project, i.e. an end user of
+// the monitored application.
+type Person struct {
+	ID       int    `mapstructure:"id"`
+	Username string `mapstructure:"username"`
+	Email    string `mapstructure:"email"`
+	LastSeen int    `json:"last_seen" mapstructure:"last_seen"`
+}
+
+// ListPeople lists all persons tracked by the project to which the client's
+// project access token belongs.
+func (c *RollbarAPIClient) ListPeople() ([]Person, error) {
+	people := []Person{}
+	it := c.PeopleIterator()
+	for it.Next() {
+		people = append(people, it.Person())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	log.Debug().
+		Int("count", len(people)).
+		Msg("Successfully listed people")
+	return people, nil
+}
+
+// listPeoplePage lists one page of the persons tracked by the project to which
+// the client's project access token belongs.
+func (c *RollbarAPIClient) listPeoplePage(page int) ([]Person, error) {
+	l := log.With().Int("page", page).Logger()
+	l.Debug().Msg("Listing people")
+	resp, err := c.request().
+		SetResult(personListResponse{}).
+		SetError(ErrorResult{}).
+		Get(c.BaseURL + pathPeople + fmt.Sprintf("?page=%d", page))
+	if err != nil {
+		l.Err(err).Msg("Error listing people")
+		return nil, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error listing people")
+		return nil, err
+	}
+	return resp.Result().(*personListResponse).Result.People, nil
+}
+
+// ReadPerson reads a person tracked by a Rollbar project from the API.  If no
+// matching person is found, returns error ErrNotFound.
+func (c *RollbarAPIClient) ReadPerson(personID int) (Person, error) {
+	var p Person
+	l := log.With().Int("personID", personID).Logger()
+	l.Debug().Msg("Reading person from API")
+	resp, err := c.request().
+		SetPathParams(map[string]string{"personID": strconv.Itoa(personID)}).
+		SetResult(personReadResponse{}).
+		SetError(ErrorResult{}).
+		Get(c.BaseURL + pathPerson)
+	if err != nil {
+		l.Err(err).Msg("Error reading person from API")
+		return p, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error reading person from API")
+		return p, err
+	}
+	p = resp.Result().(*personReadResponse).Result
+	l.Debug().Msg("Successfully read person from API")
+	return p, nil
+}
+
+// DeletePersonData submits a job deleting all data about a person tracked by a
+// Rollbar project, including the occurrences they are attached to.  Deletion
+// happens asynchronously; the job is complete once ReadPerson returns
+// ErrNotFound.
+func (c *RollbarAPIClient) DeletePersonData(personID int) error {
+	l := log.With().Int("personID", personID).Logger()
+	l.Debug().Msg("Submitting person data deletion")
+	resp, err := c.request().
+		SetPathParams(map[string]string{"personID": strconv.Itoa(personID)}).
+		SetError(ErrorResult{}).
+		Delete(c.BaseURL + pathPerson)
+	if err != nil {
+		l.Err(err).Msg("Error submitting person data deletion")
+		return err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error submitting person data deletion")
+		return err
+	}
+	l.Debug().Msg("Successfully submitted person data deletion")
+	return nil
+}
+
+/*
+ * Containers for unmarshalling Rollbar API responses
+ */
+
+type personListResponse struct {
+	Err    int `json:"err"`
+	Result struct {
+		People []Person `json:"people"`
+	} `json:"result"`
+}
+
+type personReadResponse struct {
+	Err    int    `json:"err"`
+	Result Person `json:"result"`
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// TestListPeople tests listing all persons tracked by a Rollbar project.
+func (s *Suite) TestListPeople() {
+	u := s.client.BaseURL + pathPeople
+	r := responderFromFixture("person/list_page1.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=1", r)
+	r = responderFromFixture("person/list_page2.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=2", r)
+	expected := []Person{
+		{
+			ID:       862115,
+			Username: "alice",
+			Email:    "alice@example.com",
+			LastSeen: 1602085345,
+		},
+		{
+			ID:       862116,
+			Username: "bob",
+			Email:    "bob@example.com",
+			LastSeen: 1602085340,
+		},
+	}
+	actual, err := s.client.ListPeople()
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	s.checkServerErrors("GET", u+"?page=1", func() error {
+		_, err := s.client.ListPeople()
+		return err
+	})
+}
+
+// TestReadPerson tests reading a person tracked by a Rollbar project.
+func (s *Suite) TestReadPerson() {
+	personID := 862115
+	u := s.client.BaseURL + pathPerson
+	u = strings.ReplaceAll(u, "{personID}", strconv.Itoa(personID))
+	r := responderFromFixture("person/read.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+	expected := Person{
+		ID:       personID,
+		Username: "alice",
+		Email:    "alice@example.com",
+		LastSeen: 1602085345,
+	}
+	actual, err := s.client.ReadPerson(personID)
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	s.checkServerErrors("GET", u, func() error {
+		_, err := s.client.ReadPerson(personID)
+		return err
+	})
+}
+
+// TestDeletePersonData tests submitting deletion of a person's data.
+func (s *Suite) TestDeletePersonData() {
+	personID := 862115
+	u := s.client.BaseURL + pathPerson
+	u = strings.ReplaceAll(u, "{personID}", strconv.Itoa(personID))
+	r := responderFromFixture("person/delete.json", http.StatusOK)
+	httpmock.RegisterResponder("DELETE", u, r)
+	err := s.client.DeletePersonData(personID)
+	s.Nil(err)
+
+	s.checkServerErrors("DELETE", u, func() error {
+		return s.client.DeletePersonData(personID)
+	})
+}
@@ -76,9 +76,13 @@ type Project struct {
 
 // ListProjects lists all Rollbar projects.
 func (c *RollbarAPIClient) ListProjects() ([]Project, error) {
+	if v, ok := c.cache.get(cacheKeyProjects); ok {
+		log.Debug().Msg("Using cached project list")
+		return append([]Project(nil), v.([]Project)...), nil
+	}
 	u := c.BaseURL + pathProjectList
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(projectListResponse{}).
 		SetError(ErrorResult{}).
 		Get(u)
@@ -107,9 +111,35 @@ func (c *RollbarAPIClient) ListProjects() ([]Project, error) {
 		Int("raw_projects", len(lpr.Result)).
 		Int("cleaned_projects", len(cleaned)).
 		Msg("Successfully listed projects")
+	c.cache.set(cacheKeyProjects, append([]Project(nil), cleaned...))
 	return cleaned, nil
 }
 
+// GetProjectByName finds a Rollbar project by name. If no matching project is
+// found, returns error ErrNotFound.  The API supports neither filtering nor
+// paginating the project list, so this costs a single list call and stops at
+// the first match.
+func (c *RollbarAPIClient) GetProjectByName(name string) (*Project, error) {
+	l := log.With().
+		Str("name", name).
+		Logger()
+	l.Debug().Msg("Finding project by name")
+	it := c.ProjectsIterator()
+	for it.Next() {
+		p := it.Project()
+		if p.Name == name {
+			l.Debug().Int("project_id", p.ID).Msg("Found project")
+			return &p, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		l.Err(err).Send()
+		return nil, err
+	}
+	l.Debug().Msg("Could not find project")
+	return nil, ErrNotFound
+}
+
 // CreateProject creates a new Rollbar project.
 func (c *RollbarAPIClient) CreateProject(name string) (*Project, error) {
 	u := c.BaseURL + pathProjectCreate
@@ -118,7 +148,7 @@ func (c *RollbarAPIClient) CreateProject(name string) (*Project, error) {
 		Logger()
 	l.Debug().Msg("Creating new project")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetBody(map[string]interface{}{"name": name}).
 		SetResult(projectResponse{}).
 		SetError(ErrorResult{}).
@@ -133,6 +163,7 @@ func (c *RollbarAPIClient) CreateProject(name string) (*Project, error) {
 		return nil, err
 	}
 	l.Debug().Msg("Project successfully created")
+	c.cache.invalidate(cacheKeyProjects)
 	pr := resp.Result().(*projectResponse)
 	return &pr.Result, nil
 
@@ -148,7 +179,7 @@ func (c *RollbarAPIClient) ReadProject(projectID int) (*Project, error) {
 		Logger()
 	l.Debug().Msg("Reading project from API")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(projectResponse{}).
 		SetError(ErrorResult{}).
 		SetPathParams(map[string]string{
@@ -176,6 +207,39 @@ func (c *RollbarAPIClient) ReadProject(projectID int) (*Project, error) {
 
 }
 
+// UpdateProject renames a Rollbar project. If no matching project is found,
+// returns error ErrNotFound.
+func (c *RollbarAPIClient) UpdateProject(projectID int, name string) (*Project, error) {
+	u := c.BaseURL + pathProjectUpdate
+	l := log.With().
+		Int("projectID", projectID).
+		Str("name", name).
+		Logger()
+	l.Debug().Msg("Updating project")
+
+	resp, err := c.request().
+		SetBody(map[string]interface{}{"name": name}).
+		SetResult(projectResponse{}).
+		SetError(ErrorResult{}).
+		SetPathParams(map[string]string{
+			"projectID": strconv.Itoa(projectID),
+		}).
+		Patch(u)
+	if err != nil {
+		l.Err(err).Msg("Error updating project")
+		return nil, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Send()
+		return nil, err
+	}
+	l.Debug().Msg("Project successfully updated")
+	c.cache.invalidate(cacheKeyProjects)
+	pr := resp.Result().(*projectResponse)
+	return &pr.Result, nil
+}
+
 // DeleteProject deletes a Rollbar project. If no matching project is found,
 // returns error ErrNotFound.
 func (c *RollbarAPIClient) DeleteProject(projectID int) error {
@@ -185,7 +249,7 @@ func (c *RollbarAPIClient) DeleteProject(projectID int) error {
 		Logger()
 	l.Debug().Msg("Deleting project")
 
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetError(ErrorResult{}).
 		SetPathParams(map[string]string{
 			"projectID": strconv.Itoa(projectID),
@@ -201,6 +265,7 @@ func (c *RollbarAPIClient) DeleteProject(projectID int) error {
 		return err
 	}
 	l.Debug().Msg("Project successfully deleted")
+	c.cache.invalidate(cacheKeyProjects)
 	return nil
 }
 
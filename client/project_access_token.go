@@ -23,9 +23,15 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 // ProjectAccessToken represents a Rollbar project access token.
@@ -54,6 +60,61 @@ const (
 	ScopePostClientItem = Scope("post_client_item")
 )
 
+// Scopes lists all valid project access token scopes.  It is the single source
+// of truth for scope validation, so a new Rollbar scope need only be added here.
+var Scopes = []Scope{
+	ScopeRead,
+	ScopeWrite,
+	ScopePostServerItem,
+	ScopePostClientItem,
+}
+
+// Valid returns true if s is a project access token scope accepted by the
+// Rollbar API.
+func (s Scope) Valid() bool {
+	for _, scope := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeStrings returns the valid project access token scopes as strings.
+func ScopeStrings() []string {
+	ss := make([]string, len(Scopes))
+	for i, s := range Scopes {
+		ss[i] = string(s)
+	}
+	return ss
+}
+
+// RedactToken masks an access token so it can be safely included in logs and
+// error messages.  Only the last four characters are preserved, which is enough
+// to tell tokens apart when debugging.
+func RedactToken(token string) string {
+	const visible = 4
+	if len(token) <= visible*2 {
+		return "****"
+	}
+	return "****" + token[len(token)-visible:]
+}
+
+// redactTokenError scrubs an access token from an error message, e.g. a
+// transport error that includes the request URL.  Sentinel errors are returned
+// unchanged so they can still be compared.
+func redactTokenError(err error, token string) error {
+	if err == nil || token == "" || !strings.Contains(err.Error(), token) {
+		return err
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		// Keep the cause, e.g. a passed deadline, for callers to inspect
+		return &url.Error{Op: ue.Op, URL: strings.ReplaceAll(ue.URL, token, RedactToken(token)), Err: ue.Err}
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), token, RedactToken(token)))
+}
+
 // ProjectAccessTokenCreateArgs encapsulates arguments for creating a Rollbar
 // project access token.
 type ProjectAccessTokenCreateArgs struct {
@@ -84,12 +145,7 @@ func (args *ProjectAccessTokenCreateArgs) sanityCheck() error {
 		errors = append(errors, err)
 	}
 	for _, s := range args.Scopes {
-		switch s {
-		case ScopeRead, ScopeWrite, ScopePostClientItem, ScopePostServerItem:
-			// Passed sanity check
-		default:
-			// FIXME: Default switch case needs test coverage.
-			//  https://github.com/rollbar/terraform-provider-rollbar/issues/39
+		if !s.Valid() {
 			err := fmt.Errorf("invalid scope")
 			errors = append(errors, err)
 		}
@@ -130,6 +186,7 @@ type ProjectAccessTokenUpdateArgs struct {
 	AccessToken          string `json:"-"`
 	RateLimitWindowSize  int    `json:"rate_limit_window_size"`
 	RateLimitWindowCount int    `json:"rate_limit_window_count"`
+	Status               Status `json:"status,omitempty"` // Unchanged if blank
 }
 
 // sanityCheck checks that the arguments are sane.
@@ -146,6 +203,13 @@ func (args *ProjectAccessTokenUpdateArgs) sanityCheck() error {
 		err := fmt.Errorf("access token cannot be blank")
 		errors = append(errors, err)
 	}
+	switch args.Status {
+	case "", StatusEnabled, StatusDisabled:
+		// Passed sanity check
+	default:
+		err := fmt.Errorf("invalid status")
+		errors = append(errors, err)
+	}
 	if args.RateLimitWindowCount < 0 {
 		err := fmt.Errorf("rate limit window count must be zero or greater")
 		errors = append(errors, err)
@@ -171,8 +235,78 @@ func (c *RollbarAPIClient) ListProjectAccessTokens(projectID int) ([]ProjectAcce
 		Logger()
 	l.Debug().Msg("Listing project access tokens")
 
+	// Concurrent callers for the same project share the result of one call.
+	// The call is bound only to the stop context, not to the context of
+	// whichever caller started it, so one caller giving up does not fail the
+	// others.
+	detached := *c
+	detached.ctx = nil
+	ch := c.tokenLists.group.DoChan(c.tokenLists.key(projectID), func() (interface{}, error) {
+		return detached.listProjectAccessTokens(projectID)
+	})
+	if c.tokenLists.joined != nil {
+		c.tokenLists.joined()
+	}
+	var done <-chan struct{}
+	if c.ctx != nil {
+		done = c.ctx.Done()
+	}
+	select {
+	case <-done:
+		err := fmt.Errorf("listing project access tokens: %w", context.Cause(c.ctx))
+		l.Err(err).Send()
+		return nil, err
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		if res.Shared {
+			l.Debug().Msg("Shared project access token list with concurrent callers")
+		}
+		pats := res.Val.([]ProjectAccessToken)
+		return append([]ProjectAccessToken(nil), pats...), nil
+	}
+}
+
+// tokenListGroup coalesces concurrent listings of the same project's access
+// tokens into a single API call.
+type tokenListGroup struct {
+	group singleflight.Group
+
+	mu     sync.Mutex
+	writes map[int]int // Count of writes to each project's tokens
+
+	// joined, if set, is called once a listing is waiting for its result.
+	joined func()
+}
+
+// key returns the key under which listings of a project's tokens are shared.
+// It changes with every write to them, so a listing following a write never
+// joins one started before it.
+func (g *tokenListGroup) key(projectID int) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return fmt.Sprintf("%d/%d", projectID, g.writes[projectID])
+}
+
+// wrote records a write to a project's tokens.
+func (g *tokenListGroup) wrote(projectID int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.writes == nil {
+		g.writes = make(map[int]int)
+	}
+	g.writes[projectID]++
+}
+
+// listProjectAccessTokens lists all access tokens for a Rollbar project with a
+// single API call.
+func (c *RollbarAPIClient) listProjectAccessTokens(projectID int) ([]ProjectAccessToken, error) {
+	l := log.With().
+		Int("projectID", projectID).
+		Logger()
 	u := c.BaseURL + pathProjectTokens
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(patListResponse{}).
 		SetError(ErrorResult{}).
 		SetPathParams(map[string]string{
@@ -192,35 +326,40 @@ func (c *RollbarAPIClient) ListProjectAccessTokens(projectID int) ([]ProjectAcce
 	return pats, nil
 }
 
-// ReadProjectAccessToken reads a Rollbar project access token from the API.  It
-// returns the first token that matches `name`. If no matching token is found,
-// returns error ErrNotFound.
+// ReadProjectAccessToken reads a Rollbar project access token from the API,
+// by its token value. If no matching token is found, returns error
+// ErrNotFound.
 func (c *RollbarAPIClient) ReadProjectAccessToken(projectID int, token string) (ProjectAccessToken, error) {
 	l := log.With().
 		Int("projectID", projectID).
-		Str("token", token).
+		Str("token", RedactToken(token)).
 		Logger()
 	l.Debug().Msg("Reading project access token")
 
 	var pat ProjectAccessToken
-	tokens, err := c.ListProjectAccessTokens(projectID)
+	resp, err := c.request().
+		SetPathParams(map[string]string{
+			"projectID":   strconv.Itoa(projectID),
+			"accessToken": token,
+		}).
+		SetResult(patReadResponse{}).
+		SetError(ErrorResult{}).
+		Get(c.BaseURL + pathProjectToken)
 	if err != nil {
-		l.Err(err).
-			Msg("Error listing project access tokens")
+		err = redactTokenError(err, token)
+		l.Err(err).Msg("Error reading project access token")
 		return pat, err
 	}
-
-	for _, t := range tokens {
-		if t.AccessToken == token {
-			l.Debug().
-				Interface("token", t).
-				Msg("Found matching project access token")
-			return t, nil
-		}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error reading project access token")
+		return pat, err
 	}
-
-	l.Warn().Msg("Could not find matching project access token")
-	return pat, ErrNotFound
+	pat = resp.Result().(*patReadResponse).Result
+	l.Debug().
+		Str("name", pat.Name).
+		Msg("Successfully read project access token")
+	return pat, nil
 }
 
 // ReadProjectAccessTokenByName reads a Rollbar project access token from the
@@ -256,12 +395,13 @@ func (c *RollbarAPIClient) ReadProjectAccessTokenByName(projectID int, name stri
 func (c *RollbarAPIClient) DeleteProjectAccessToken(projectID int, token string) error {
 	l := log.With().
 		Int("projectID", projectID).
-		Str("token", token).
+		Str("token", RedactToken(token)).
 		Logger()
 	l.Debug().Msg("Deleting project access token")
+	defer c.tokenLists.wrote(projectID)
 
 	u := c.BaseURL + pathProjectToken
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"projectID":   strconv.Itoa(projectID),
 			"accessToken": token,
@@ -269,6 +409,7 @@ func (c *RollbarAPIClient) DeleteProjectAccessToken(projectID int, token string)
 		SetError(ErrorResult{}).
 		Delete(u)
 	if err != nil {
+		err = redactTokenError(err, token)
 		l.Err(err).Send()
 		return err
 	}
@@ -293,9 +434,10 @@ func (c *RollbarAPIClient) CreateProjectAccessToken(args ProjectAccessTokenCreat
 		l.Err(err).Msg("Arguments to create project access token failed sanity check.")
 		return pat, err
 	}
+	defer c.tokenLists.wrote(args.ProjectID)
 
 	u := c.BaseURL + pathProjectTokens
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"projectID": strconv.Itoa(args.ProjectID),
 		}).
@@ -315,7 +457,7 @@ func (c *RollbarAPIClient) CreateProjectAccessToken(args ProjectAccessTokenCreat
 	r := resp.Result().(*patCreateResponse)
 	pat = r.Result
 	l.Debug().
-		Interface("token", pat).
+		Str("token", RedactToken(pat.AccessToken)).
 		Msg("Successfully created new project access token")
 	return pat, nil
 }
@@ -332,9 +474,10 @@ func (c *RollbarAPIClient) UpdateProjectAccessToken(args ProjectAccessTokenUpdat
 		l.Err(err).Msg("Arguments to update project access token failed sanity check.")
 		return err
 	}
+	defer c.tokenLists.wrote(args.ProjectID)
 
 	u := c.BaseURL + pathProjectToken
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"projectID":   strconv.Itoa(args.ProjectID),
 			"accessToken": args.AccessToken,
@@ -344,6 +487,7 @@ func (c *RollbarAPIClient) UpdateProjectAccessToken(args ProjectAccessTokenUpdat
 		SetError(ErrorResult{}).
 		Patch(u)
 	if err != nil {
+		err = redactTokenError(err, args.AccessToken)
 		l.Err(err).Msg("Error updating project access token")
 		return err
 	}
@@ -359,6 +503,11 @@ type patListResponse struct {
 	Result []ProjectAccessToken
 }
 
+type patReadResponse struct {
+	Error  int `json:"err"`
+	Result ProjectAccessToken
+}
+
 type patCreateResponse struct {
 	Error  int `json:"err"`
 	Result ProjectAccessToken
@@ -367,3 +516,83 @@ type patCreateResponse struct {
 type patUpdateResponse struct {
 	Error int `json:"err"`
 }
+
+// FindProjectAccessToken finds the project access token with value `token`
+// in projects `projectIDs`, or if none are given in any of the account's
+// projects, returning ErrNotFound if there is none.  The API cannot introspect
+// a token, so this lists the tokens of each project until it is found.
+func (c *RollbarAPIClient) FindProjectAccessToken(token string, projectIDs ...int) (ProjectAccessToken, error) {
+	l := log.With().Str("token", RedactToken(token)).Logger()
+	l.Debug().Msg("Finding project access token")
+	if len(projectIDs) == 0 {
+		projects, err := c.ListProjects()
+		if err != nil {
+			l.Err(err).Send()
+			return ProjectAccessToken{}, err
+		}
+		for _, p := range projects {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+	for _, id := range projectIDs {
+		tokens, err := c.ListProjectAccessTokens(id)
+		if err != nil && err != ErrNotFound {
+			l.Err(err).Send()
+			return ProjectAccessToken{}, err
+		}
+		for _, t := range tokens {
+			if t.AccessToken == token {
+				l.Debug().Int("project_id", id).Msg("Found project access token")
+				return t, nil
+			}
+		}
+	}
+	l.Debug().Msg("Project access token not found")
+	return ProjectAccessToken{}, ErrNotFound
+}
+
+// MaxTokenScopeSearch is the most projects ProjectTokenScopes searches for a
+// token, so that looking up its scopes costs at most that many API calls plus
+// one, however many projects the account has.
+const MaxTokenScopeSearch = 10
+
+// ErrTokenScopesUnknown is returned by ProjectTokenScopes when the account has
+// too many projects to search for the token.
+var ErrTokenScopesUnknown = errors.New("token scopes unknown: too many projects to search")
+
+// ProjectTokenScopes returns the scopes of the project access token that c
+// authenticates with, found with account client `account` as by
+// FindProjectAccessToken, or ErrTokenScopesUnknown if the account has more
+// than MaxTokenScopeSearch projects.  Concurrent calls wait for a single
+// search.  Its result is remembered, so later calls do not search again,
+// unless it failed with an error that may not recur, e.g. a network error.
+func (c *RollbarAPIClient) ProjectTokenScopes(account *RollbarAPIClient) ([]Scope, error) {
+	ts := c.tokenScopes
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.known {
+		return ts.scopes, ts.err
+	}
+	projects, err := account.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) > MaxTokenScopeSearch {
+		ts.known, ts.err = true, ErrTokenScopesUnknown
+		return nil, ts.err
+	}
+	if len(projects) == 0 {
+		ts.known, ts.err = true, ErrNotFound
+		return nil, ts.err
+	}
+	ids := make([]int, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	t, err := account.FindProjectAccessToken(c.token, ids...)
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	ts.known, ts.scopes, ts.err = true, t.Scopes, err
+	return ts.scopes, ts.err
+}
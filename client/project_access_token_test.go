@@ -23,12 +23,20 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/jarcoal/httpmock"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // TestListProjectAccessTokens tests listing Rollbar project access tokens.
@@ -114,17 +122,155 @@ func (s *Suite) TestListProjectAccessTokens() {
 	s.checkServerErrors("GET", u, testFunc)
 }
 
+// TestListProjectAccessTokensConcurrent tests that concurrent listings of the
+// same project's access tokens share a single API call.
+func (s *Suite) TestListProjectAccessTokensConcurrent() {
+	syncLogger(s.T())
+	projectID := 12116
+	u := s.client.BaseURL + pathProjectTokens
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+
+	var calls int32
+	release := make(chan struct{})
+	fixture := responderFromFixture("project_access_token/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+		}
+		return fixture(req)
+	})
+	const callers = 5
+	var joined sync.WaitGroup
+	joined.Add(callers)
+	s.client.tokenLists.joined = joined.Done
+	defer func() { s.client.tokenLists.joined = nil }()
+
+	var wg sync.WaitGroup
+	results := make([][]ProjectAccessToken, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = s.client.ListProjectAccessTokens(projectID)
+		}(i)
+	}
+	// Hold the call until every caller is waiting for it
+	joined.Wait()
+	close(release)
+	wg.Wait()
+
+	s.Equal(int32(1), atomic.LoadInt32(&calls))
+	for i := 0; i < callers; i++ {
+		s.Nil(errs[i])
+		s.Len(results[i], 4)
+	}
+
+	// Callers receive their own copy of the shared result
+	results[0][0].Name = "mutated"
+	s.NotEqual("mutated", results[1][0].Name)
+}
+
+// TestListProjectAccessTokensAbandoned tests that a caller giving up on a
+// shared listing of access tokens does not fail the other callers.
+func (s *Suite) TestListProjectAccessTokensAbandoned() {
+	syncLogger(s.T())
+	projectID := 12117
+	u := s.client.BaseURL + pathProjectTokens
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+
+	var calls int32
+	release := make(chan struct{})
+	fixture := responderFromFixture("project_access_token/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return fixture(req)
+	})
+	var joined sync.WaitGroup
+	joined.Add(2)
+	s.client.tokenLists.joined = joined.Done
+	defer func() { s.client.tokenLists.joined = nil }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	abandoned := make(chan error)
+	go func() {
+		_, err := s.client.WithContext(ctx).ListProjectAccessTokens(projectID)
+		abandoned <- err
+	}()
+	var pats []ProjectAccessToken
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pats, err = s.client.ListProjectAccessTokens(projectID)
+	}()
+	joined.Wait()
+
+	cancel()
+	s.True(errors.Is(<-abandoned, context.Canceled))
+	close(release)
+	<-done
+	s.Nil(err)
+	s.Len(pats, 4)
+	s.Equal(int32(1), atomic.LoadInt32(&calls))
+}
+
+// TestListProjectAccessTokensAfterWrite tests that listing access tokens after
+// writing one does not share a listing started before the write.
+func (s *Suite) TestListProjectAccessTokensAfterWrite() {
+	syncLogger(s.T())
+	projectID := 12118
+	token := "d19f7ada16534b1c94e91d9da3dbae5a"
+	u := s.client.BaseURL + pathProjectTokens
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+	du := s.client.BaseURL + pathProjectToken
+	du = strings.ReplaceAll(du, "{projectID}", strconv.Itoa(projectID))
+	du = strings.ReplaceAll(du, "{accessToken}", token)
+
+	var calls int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	fixture := responderFromFixture("project_access_token/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(entered)
+			<-release
+		}
+		return fixture(req)
+	})
+	httpmock.RegisterResponder("DELETE", du, httpmock.NewStringResponder(http.StatusOK, `{"err": 0}`))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.client.ListProjectAccessTokens(projectID)
+	}()
+	<-entered
+
+	// The listing in flight started before the write, so is not shared
+	s.Nil(s.client.DeleteProjectAccessToken(projectID, token))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pats, err := s.client.WithContext(ctx).ListProjectAccessTokens(projectID)
+	s.Nil(err)
+	s.Len(pats, 4)
+	s.Equal(int32(2), atomic.LoadInt32(&calls))
+	close(release)
+	<-done
+}
+
 // TestReadProjectAccessToken tests reading a Rollbar project access token from
 // the API.
 func (s *Suite) TestReadProjectAccessToken() {
 	projectID := 411334
-	u := s.client.BaseURL + pathProjectTokens
+	accessToken := "80f235b890c34ca49bcea692c2b90421"
+	u := s.client.BaseURL + pathProjectToken
 	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+	u = strings.ReplaceAll(u, "{accessToken}", accessToken)
 
-	r := responderFromFixture("project_access_token/list.json", http.StatusOK)
+	r := responderFromFixture("project_access_token/read.json", http.StatusOK)
 	httpmock.RegisterResponder("GET", u, r)
 
-	accessToken := "80f235b890c34ca49bcea692c2b90421"
 	// PAT exists
 	expected := ProjectAccessToken{
 		AccessToken: accessToken,
@@ -145,12 +291,8 @@ func (s *Suite) TestReadProjectAccessToken() {
 	s.Nil(err)
 	s.Equal(expected, actual)
 
-	// PAT does not exist
-	_, err = s.client.ReadProjectAccessToken(projectID, "does-not-exist")
-	s.Equal(ErrNotFound, err)
-
 	s.checkServerErrors("GET", u, func() error {
-		_, err = s.client.ReadProjectAccessToken(projectID, "does-not-exist")
+		_, err = s.client.ReadProjectAccessToken(projectID, accessToken)
 		return err
 	})
 }
@@ -196,6 +338,39 @@ func (s *Suite) TestReadProjectAccessTokenByName() {
 
 }
 
+// TestFindProjectAccessToken tests finding a project access token by value.
+func (s *Suite) TestFindProjectAccessToken() {
+	httpmock.RegisterResponder("GET", s.client.BaseURL+pathProjectList,
+		responderFromFixture("project/list.json", http.StatusOK))
+	tokensURL := func(projectID int) string {
+		u := s.client.BaseURL + pathProjectTokens
+		return strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+	}
+	httpmock.RegisterResponder("GET", tokensURL(411704),
+		httpmock.NewStringResponder(http.StatusOK, `{"err": 0, "result": []}`))
+	httpmock.RegisterResponder("GET", tokensURL(411703),
+		responderFromFixture("project_access_token/list.json", http.StatusOK))
+
+	// Token in the second project listed
+	actual, err := s.client.FindProjectAccessToken("90b2521327a647f9aa80ef6d84427485")
+	s.Nil(err)
+	s.Equal("read", actual.Name)
+	s.Equal([]Scope{ScopeRead}, actual.Scopes)
+
+	// Token in no project
+	_, err = s.client.FindProjectAccessToken("this-token-does-not-exist")
+	s.Equal(ErrNotFound, err)
+
+	// Token in another project than those searched
+	_, err = s.client.FindProjectAccessToken("90b2521327a647f9aa80ef6d84427485", 411704)
+	s.Equal(ErrNotFound, err)
+
+	s.checkServerErrors("GET", tokensURL(411703), func() error {
+		_, err := s.client.FindProjectAccessToken("90b2521327a647f9aa80ef6d84427485")
+		return err
+	})
+}
+
 // TestDeleteProjectAccessToken tests deleting a Rollbar project access token.
 func (s *Suite) TestDeleteProjectAccessToken() {
 	projectID := 428325
@@ -309,6 +484,7 @@ func (s *Suite) TestUpdateProjectAccessToken() {
 		AccessToken:          accessToken,
 		RateLimitWindowSize:  1000,
 		RateLimitWindowCount: 2500,
+		Status:               StatusDisabled,
 	}
 	u := s.client.BaseURL + pathProjectToken
 	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projID))
@@ -323,6 +499,7 @@ func (s *Suite) TestUpdateProjectAccessToken() {
 		s.Nil(err)
 		s.Equal(args.RateLimitWindowCount, a.RateLimitWindowCount)
 		s.Equal(args.RateLimitWindowSize, a.RateLimitWindowSize)
+		s.Equal(args.Status, a.Status)
 		return rs, nil
 	}
 	httpmock.RegisterResponder("PATCH", u, r)
@@ -344,6 +521,11 @@ func (s *Suite) TestUpdateProjectAccessToken() {
 	badArgs.AccessToken = ""
 	err = s.client.UpdateProjectAccessToken(badArgs)
 	s.NotNil(err)
+	// Invalid status
+	badArgs = args
+	badArgs.Status = Status("avocado")
+	err = s.client.UpdateProjectAccessToken(badArgs)
+	s.NotNil(err)
 	// Invalid rate limit window size
 	badArgs = args
 	badArgs.RateLimitWindowSize = -33
@@ -363,3 +545,98 @@ func (s *Suite) TestUpdateProjectAccessToken() {
 		return s.client.UpdateProjectAccessToken(args)
 	})
 }
+
+// TestScopeValid tests validation of project access token scopes.
+func (s *Suite) TestScopeValid() {
+	for _, scope := range []string{"read", "write", "post_server_item", "post_client_item"} {
+		s.True(Scope(scope).Valid())
+	}
+	s.False(Scope("avocado").Valid())
+	s.False(Scope("").Valid())
+	s.Equal(len(Scopes), len(ScopeStrings()))
+}
+
+// TestRedactToken tests masking of access tokens for logs and errors.
+func (s *Suite) TestRedactToken() {
+	token := "d19f7ada16534b1c94e91d9da3dbae5a"
+	s.Equal("****ae5a", RedactToken(token))
+	s.Equal("****", RedactToken("short"))
+	s.Equal("****", RedactToken(""))
+
+	err := redactTokenError(fmt.Errorf("Delete \"https://api.rollbar.com/api/1/project/1/access_token/%s\": EOF", token), token)
+	s.NotContains(err.Error(), token)
+	s.Contains(err.Error(), "****ae5a")
+	s.Equal(ErrNotFound, redactTokenError(ErrNotFound, token))
+}
+
+// TestReadProjectAccessTokenConnectionError tests that a failed connection
+// while reading a token is reported and logged without the token's value.
+func (s *Suite) TestReadProjectAccessTokenConnectionError() {
+	token := "d19f7ada16534b1c94e91d9da3dbae5a"
+	logger := log.Logger
+	defer func() { log.Logger = logger }()
+	var buf bytes.Buffer
+	log.Logger = log.Logger.Output(&buf)
+
+	c := NewClient("fakeTokenString", WithBaseURL("http://127.0.0.1:1"))
+	_, err := c.ReadProjectAccessToken(1, token)
+	s.NotNil(err)
+	var ue *url.Error
+	s.True(errors.As(err, &ue), "%v", err)
+	s.NotContains(err.Error(), token)
+	s.Contains(err.Error(), RedactToken(token))
+	s.Contains(buf.String(), "Error reading project access token")
+	s.NotContains(buf.String(), token)
+}
+
+// TestProjectAccessTokenDeadline tests that a request abandoned with its
+// context reports why, without the token's value.
+func (s *Suite) TestProjectAccessTokenDeadline() {
+	projectID := 428325
+	token := "bccf06c897d74020a80cb72407abb4ee"
+	u := s.client.BaseURL + pathProjectToken
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+	u = strings.ReplaceAll(u, "{accessToken}", token)
+	httpmock.RegisterResponder("DELETE", u, func(req *http.Request) (*http.Response, error) {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(50 * time.Millisecond):
+			return httpmock.NewStringResponse(http.StatusOK, `{"err": 0}`), nil
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.client.WithContext(ctx).DeleteProjectAccessToken(projectID, token)
+	s.True(errors.Is(err, context.DeadlineExceeded), "%v", err)
+	s.NotContains(err.Error(), token)
+
+	// The client itself is not bound to the context
+	s.Nil(s.client.DeleteProjectAccessToken(projectID, token))
+}
+
+// TestProjectAccessTokenErrorContext tests that API errors about a token record
+// the request without the token's value.
+func (s *Suite) TestProjectAccessTokenErrorContext() {
+	projectID := 428325
+	token := "bccf06c897d74020a80cb72407abb4ee"
+	u := s.client.BaseURL + pathProjectToken
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+	u = strings.ReplaceAll(u, "{accessToken}", token)
+	httpmock.RegisterResponder("DELETE", u, func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusUnprocessableEntity, `{"err": 1, "message": "Something went wrong"}`)
+		resp.Header.Set(HeaderRateLimitRemaining, "42")
+		return resp, nil
+	})
+
+	err := s.client.DeleteProjectAccessToken(projectID, token)
+	var er *ErrorResult
+	s.Require().True(errors.As(err, &er))
+	s.Equal("DELETE", er.Method)
+	s.Equal("/api/1/project/428325/access_token/****b4ee", er.URL)
+	s.Equal(http.StatusUnprocessableEntity, er.StatusCode)
+	s.Equal("42", er.RateLimitRemaining)
+	s.NotContains(err.Error(), token)
+	s.Contains(err.Error(), "HTTP 422")
+}
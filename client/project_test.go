@@ -25,12 +25,9 @@ package client
 import (
 	"encoding/json"
 	"github.com/brianvoe/gofakeit/v5"
-	"github.com/dnaeon/go-vcr/cassette"
-	"github.com/dnaeon/go-vcr/recorder"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -72,6 +69,35 @@ func (s *Suite) TestListProjects() {
 	})
 }
 
+// TestGetProjectByName tests finding a Rollbar project by name.
+func (s *Suite) TestGetProjectByName() {
+	u := s.client.BaseURL + pathProjectList
+
+	// Success
+	r := responderFromFixture("project/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+	expected := &Project{
+		ID:           411703,
+		Name:         "foo",
+		AccountID:    317418,
+		Status:       "enabled",
+		DateCreated:  1602085340,
+		DateModified: 1602085340,
+	}
+	actual, err := s.client.GetProjectByName("foo")
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	// Not found
+	_, err = s.client.GetProjectByName("baz")
+	s.Equal(ErrNotFound, err)
+
+	s.checkServerErrors("GET", u, func() error {
+		_, err = s.client.GetProjectByName("foo")
+		return err
+	})
+}
+
 // TestCreateProject tests creating a Rollbar project.
 func (s *Suite) TestCreateProject() {
 	u := s.client.BaseURL + pathProjectCreate
@@ -131,6 +157,34 @@ func (s *Suite) TestReadProject() {
 	s.Equal(ErrNotFound, err)
 }
 
+// TestUpdateProject tests renaming a Rollbar project.
+func (s *Suite) TestUpdateProject() {
+	projectID := 411708
+	name := "quux"
+	u := s.client.BaseURL + pathProjectUpdate
+	u = strings.ReplaceAll(u, "{projectID}", strconv.Itoa(projectID))
+
+	// Success
+	rs := responseFromFixture("project/update.json", http.StatusOK)
+	r := func(req *http.Request) (*http.Response, error) {
+		p := Project{}
+		err := json.NewDecoder(req.Body).Decode(&p)
+		s.Nil(err)
+		s.Equal(name, p.Name)
+		return rs, nil
+	}
+	httpmock.RegisterResponder("PATCH", u, r)
+	proj, err := s.client.UpdateProject(projectID, name)
+	s.Nil(err)
+	s.Equal(name, proj.Name)
+	s.Equal(projectID, proj.ID)
+
+	s.checkServerErrors("PATCH", u, func() error {
+		_, err = s.client.UpdateProject(projectID, name)
+		return err
+	})
+}
+
 // TestDeleteProject tests deleting a Rollbar project.
 func (s *Suite) TestDeleteProject() {
 	delID := gofakeit.Number(0, 1000000)
@@ -201,23 +255,7 @@ func (s *Suite) TestFindProjectTeamIDs() {
 // TestUpdateProjectTeams tests updating the set of teams attached to a Rollbar
 // project.
 func TestUpdateProjectTeams(t *testing.T) {
-	// Setup go-vcr
-	httpmock.Deactivate()
-	r, err := recorder.New("fixtures/vcr/update_project_teams")
-	assert.Nil(t, err)
-	defer func() {
-		err := r.Stop()
-		if err != nil {
-			panic(err)
-		}
-	}()
-	r.AddFilter(func(i *cassette.Interaction) error {
-		delete(i.Request.Headers, "X-Rollbar-Access-Token")
-		return nil
-	})
-
-	c := NewClient(DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
-	c.Resty.GetClient().Transport = r
+	c := newVCRClient(t, "update_project_teams")
 
 	prefix := "tf-acc-test-updateprojectteams"
 	projectName := prefix
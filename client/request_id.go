@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// HeaderRequestID is the header carrying the correlation ID generated for each
+// request to the Rollbar API.  The same ID is logged with the request and its
+// response, and recorded in any ErrorResult, so a failed call in a long log can
+// be found and quoted to Rollbar support.
+const HeaderRequestID = "X-Request-ID"
+
+// newRequestID returns a random correlation ID.
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// setRequestID is a resty request middleware that sets a new correlation ID on
+// each request, including each retry.
+func setRequestID(_ *resty.Client, r *resty.Request) error {
+	id := newRequestID()
+	r.SetHeader(HeaderRequestID, id)
+	log.Debug().
+		Str("request_id", id).
+		Str("method", r.Method).
+		Str("url", r.URL).
+		Msg("Sending request to Rollbar API")
+	return nil
+}
+
+// logResponse is a resty response middleware that logs each response with the
+// correlation ID of its request.
+func logResponse(_ *resty.Client, resp *resty.Response) error {
+	log.Debug().
+		Str("request_id", requestID(resp)).
+		Int("status_code", resp.StatusCode()).
+		Dur("duration", resp.Time()).
+		Msg("Received response from Rollbar API")
+	return nil
+}
+
+// logRequestError is a resty error hook that logs requests which failed
+// without a response, e.g. on a network error or timeout.
+func logRequestError(r *resty.Request, err error) {
+	if _, ok := err.(*resty.ResponseError); ok {
+		return // Logged by logResponse
+	}
+	log.Err(err).
+		Str("request_id", r.Header.Get(HeaderRequestID)).
+		Str("method", r.Method).
+		Str("url", r.URL).
+		Msg("Request to Rollbar API failed")
+}
+
+// requestID returns the correlation ID of the request answered by resp, or ""
+// if unknown.
+func requestID(resp *resty.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
+	}
+	return resp.Request.Header.Get(HeaderRequestID)
+}
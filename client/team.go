@@ -39,6 +39,40 @@ type Team struct {
 	AccessLevel string `json:"access_level"`
 }
 
+// Possible access levels of a custom Rollbar team.  The system teams
+// "Everyone" and "Owners" have access levels "everyone" and "owner", which
+// cannot be assigned.
+const (
+	TeamAccessLevelStandard = "standard"
+	TeamAccessLevelLight    = "light"
+	TeamAccessLevelView     = "view"
+)
+
+// Access levels of the system teams, which cannot be assigned to a custom team.
+const (
+	TeamAccessLevelEveryone = "everyone"
+	TeamAccessLevelOwner    = "owner"
+)
+
+// TeamAccessLevels lists all access levels assignable to a custom team.  It is
+// the single source of truth for access level validation, so a new Rollbar
+// access level need only be added here.
+var TeamAccessLevels = []string{
+	TeamAccessLevelStandard,
+	TeamAccessLevelLight,
+	TeamAccessLevelView,
+}
+
+// ValidTeamAccessLevel returns true if level can be assigned to a custom team.
+func ValidTeamAccessLevel(level string) bool {
+	for _, l := range TeamAccessLevels {
+		if level == l {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateTeam creates a new Rollbar team.
 func (c *RollbarAPIClient) CreateTeam(name, level string) (Team, error) {
 	var t Team
@@ -55,7 +89,7 @@ func (c *RollbarAPIClient) CreateTeam(name, level string) (Team, error) {
 	}
 
 	u := c.BaseURL + pathTeamCreate
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetBody(map[string]interface{}{
 			"name":         name,
 			"access_level": level,
@@ -77,15 +111,20 @@ func (c *RollbarAPIClient) CreateTeam(name, level string) (Team, error) {
 	l.Debug().
 		Int("id", t.ID).
 		Msg("Successfully created new team")
+	c.cache.invalidate(cacheKeyTeams)
 	return t, nil
 }
 
 // ListTeams lists all Rollbar teams.
 func (c *RollbarAPIClient) ListTeams() ([]Team, error) {
 	log.Debug().Msg("Listing all teams")
+	if v, ok := c.cache.get(cacheKeyTeams); ok {
+		log.Debug().Msg("Using cached team list")
+		return append([]Team(nil), v.([]Team)...), nil
+	}
 	var teams []Team
 	u := c.BaseURL + pathTeamList
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(teamListResponse{}).
 		SetError(ErrorResult{}).
 		Get(u)
@@ -102,6 +141,7 @@ func (c *RollbarAPIClient) ListTeams() ([]Team, error) {
 	teams = r.Result
 	count := len(teams)
 	log.Debug().Int("count", count).Msg("Successfully listed teams")
+	c.cache.set(cacheKeyTeams, append([]Team(nil), teams...))
 	return teams, nil
 }
 
@@ -138,7 +178,7 @@ func (c *RollbarAPIClient) ReadTeam(id int) (Team, error) {
 
 	u := c.BaseURL + pathTeamRead
 	u = strings.ReplaceAll(u, "{teamID}", strconv.Itoa(id))
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetResult(teamReadResponse{}).
 		SetError(ErrorResult{}).
 		Get(u)
@@ -160,6 +200,53 @@ func (c *RollbarAPIClient) ReadTeam(id int) (Team, error) {
 	return t, nil
 }
 
+// UpdateTeam updates the name and access level of a Rollbar team. If no
+// matching team is found, returns error ErrNotFound.
+func (c *RollbarAPIClient) UpdateTeam(id int, name, level string) (Team, error) {
+	var t Team
+	l := log.With().
+		Int("id", id).
+		Str("name", name).
+		Str("access_level", level).
+		Logger()
+	l.Debug().Msg("Updating team")
+
+	// Sanity check
+	if id == 0 {
+		return t, fmt.Errorf("id must be non-zero")
+	}
+	if name == "" {
+		return t, fmt.Errorf("name cannot be blank")
+	}
+
+	u := c.BaseURL + pathTeamUpdate
+	resp, err := c.request().
+		SetPathParams(map[string]string{
+			"teamID": strconv.Itoa(id),
+		}).
+		SetBody(map[string]interface{}{
+			"name":         name,
+			"access_level": level,
+		}).
+		SetResult(teamReadResponse{}).
+		SetError(ErrorResult{}).
+		Patch(u)
+	if err != nil {
+		l.Err(err).Msg("Error updating team")
+		return t, err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error updating team")
+		return t, err
+	}
+	r := resp.Result().(*teamReadResponse)
+	t = r.Result
+	l.Debug().Msg("Successfully updated team")
+	c.cache.invalidate(cacheKeyTeams)
+	return t, nil
+}
+
 // DeleteTeam deletes a Rollbar team. If no matching team is found, returns
 // error ErrNotFound.
 func (c *RollbarAPIClient) DeleteTeam(id int) error {
@@ -175,7 +262,7 @@ func (c *RollbarAPIClient) DeleteTeam(id int) error {
 
 	u := c.BaseURL + pathTeamDelete
 	u = strings.ReplaceAll(u, "{teamID}", strconv.Itoa(id))
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetError(ErrorResult{}).
 		Delete(u)
 	if err != nil {
@@ -188,6 +275,7 @@ func (c *RollbarAPIClient) DeleteTeam(id int) error {
 		return err
 	}
 	l.Debug().Msg("Successfully deleted team")
+	c.cache.invalidate(cacheKeyTeams)
 	return nil
 }
 
@@ -195,7 +283,7 @@ func (c *RollbarAPIClient) DeleteTeam(id int) error {
 func (c *RollbarAPIClient) AssignUserToTeam(teamID, userID int) error {
 	l := log.With().Int("userID", userID).Int("teamID", teamID).Logger()
 	l.Debug().Msg("Assigning user to team")
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID": strconv.Itoa(teamID),
 			"userID": strconv.Itoa(userID),
@@ -228,7 +316,7 @@ func (c *RollbarAPIClient) IsUserAssignedToTeam(teamID, userID int) (bool, error
 		Int("teamID", teamID).
 		Logger()
 	l.Debug().Msg("Checking if user is assigned to team")
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID": strconv.Itoa(teamID),
 			"userID": strconv.Itoa(userID),
@@ -256,7 +344,7 @@ func (c *RollbarAPIClient) IsUserAssignedToTeam(teamID, userID int) (bool, error
 func (c *RollbarAPIClient) RemoveUserFromTeam(userID, teamID int) error {
 	l := log.With().Int("userID", userID).Int("teamID", teamID).Logger()
 	l.Debug().Msg("Removing user from team")
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID": strconv.Itoa(teamID),
 			"userID": strconv.Itoa(userID),
@@ -305,6 +393,25 @@ func (c *RollbarAPIClient) FindTeamID(name string) (int, error) {
 	return 0, ErrNotFound
 }
 
+// FindOwnersTeamID finds the ID of the system team "Owners", whose members own
+// the account.
+func (c *RollbarAPIClient) FindOwnersTeamID() (int, error) {
+	log.Debug().Msg("Finding owners team ID")
+	teams, err := c.ListTeams()
+	if err != nil {
+		log.Err(err).Send()
+		return 0, err
+	}
+	for _, t := range teams {
+		if t.AccessLevel == TeamAccessLevelOwner {
+			log.Debug().Int("team_id", t.ID).Msg("Found owners team ID")
+			return t.ID, nil
+		}
+	}
+	log.Debug().Msg("Could not find owners team ID")
+	return 0, ErrNotFound
+}
+
 // ListTeamProjectIDs lists IDs of all Rollbar projects to which a given team is
 // assigned.
 func (c *RollbarAPIClient) ListTeamProjectIDs(teamID int) ([]int, error) {
@@ -317,7 +424,7 @@ func (c *RollbarAPIClient) ListTeamProjectIDs(teamID int) ([]int, error) {
 
 	for hasNextPage {
 		l.Debug().Msg(fmt.Sprintf("Listing projects for team (page: %d)", page))
-		resp, err := c.Resty.R().
+		resp, err := c.request().
 			SetPathParams(map[string]string{
 				"teamID": strconv.Itoa(teamID),
 			}).
@@ -345,6 +452,46 @@ func (c *RollbarAPIClient) ListTeamProjectIDs(teamID int) ([]int, error) {
 	return projectIDs, nil
 }
 
+// ListTeamUserIDs lists IDs of all registered Rollbar users belonging to a
+// given team.  Users who have been invited but not yet registered are not
+// included; see ListPendingInvitations.
+func (c *RollbarAPIClient) ListTeamUserIDs(teamID int) ([]int, error) {
+
+	userIDs := []int{}
+	hasNextPage := true
+	page := 1
+
+	l := log.With().Int("teamID", teamID).Logger()
+
+	for hasNextPage {
+		l.Debug().Msg(fmt.Sprintf("Listing users for team (page: %d)", page))
+		resp, err := c.request().
+			SetPathParams(map[string]string{
+				"teamID": strconv.Itoa(teamID),
+			}).
+			SetResult(teamUserListResponse{}).
+			SetError(ErrorResult{}).
+			Get(c.BaseURL + pathTeamUsers + fmt.Sprintf("?page=%d", page))
+		if err != nil {
+			l.Err(err).Msg("Error listing users for team")
+			return nil, err
+		}
+		err = errorFromResponse(resp)
+		if err != nil {
+			l.Err(err).Msg("Error listing users for team")
+			return nil, err
+		}
+		result := resp.Result().(*teamUserListResponse).Result
+		hasNextPage = len(result) > 0
+		for _, item := range result {
+			userIDs = append(userIDs, item.UserID)
+		}
+		page++
+	}
+	l.Debug().Msg("Successfully listed users for team")
+	return userIDs, nil
+}
+
 // AssignTeamToProject assigns a Rollbar team to a project.
 func (c *RollbarAPIClient) AssignTeamToProject(teamID, projectID int) error {
 	l := log.With().
@@ -352,7 +499,7 @@ func (c *RollbarAPIClient) AssignTeamToProject(teamID, projectID int) error {
 		Int("projectID", projectID).
 		Logger()
 	l.Debug().Msg("Assigning team to project")
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID":    strconv.Itoa(teamID),
 			"projectID": strconv.Itoa(projectID),
@@ -379,7 +526,7 @@ func (c *RollbarAPIClient) RemoveTeamFromProject(teamID, projectID int) error {
 		Int("projectID", projectID).
 		Logger()
 	l.Debug().Msg("Removing team from project")
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{
 			"teamID":    strconv.Itoa(teamID),
 			"projectID": strconv.Itoa(projectID),
@@ -435,6 +582,14 @@ type teamReadResponse struct {
 	Result Team
 }
 
+type teamUserListResponse struct {
+	Err    int
+	Result []struct {
+		TeamID int `json:"team_id"`
+		UserID int `json:"user_id"`
+	}
+}
+
 type teamProjectListResponse struct {
 	Err    int
 	Result []struct {
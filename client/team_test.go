@@ -141,6 +141,47 @@ func (s *Suite) TestReadTeam() {
 	})
 }
 
+func (s *Suite) TestUpdateTeam() {
+	// Setup API mock
+	teamID := 676974
+	teamName := "bazquux"
+	accessLevel := "standard"
+	u := s.client.BaseURL + pathTeamUpdate
+	u = strings.ReplaceAll(u, "{teamID}", strconv.Itoa(teamID))
+	expected := Team{
+		ID:          teamID,
+		AccountID:   317418,
+		Name:        teamName,
+		AccessLevel: accessLevel,
+	}
+	sr := responseFromFixture("team/update.json", http.StatusOK)
+	r := func(req *http.Request) (*http.Response, error) {
+		b := make(map[string]interface{})
+		err := json.NewDecoder(req.Body).Decode(&b)
+		s.Nil(err)
+		s.Equal(teamName, b["name"])
+		s.Equal(accessLevel, b["access_level"])
+		return sr, nil
+	}
+	httpmock.RegisterResponder("PATCH", u, r)
+
+	// Successful update
+	actual, err := s.client.UpdateTeam(teamID, teamName, accessLevel)
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	// Invalid arguments
+	_, err = s.client.UpdateTeam(0, teamName, accessLevel)
+	s.NotNil(err)
+	_, err = s.client.UpdateTeam(teamID, "", accessLevel)
+	s.NotNil(err)
+
+	s.checkServerErrors("PATCH", u, func() error {
+		_, err := s.client.UpdateTeam(teamID, teamName, accessLevel)
+		return err
+	})
+}
+
 func (s *Suite) TestDeleteTeam() {
 	// Setup API mock
 	teamID := 676974
@@ -296,6 +337,22 @@ func (s *Suite) TestFindTeamID() {
 	})
 }
 
+func (s *Suite) TestFindOwnersTeamID() {
+	expected := 662036
+	u := s.client.BaseURL + pathTeamList
+	r := responderFromFixture("team/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+
+	actual, err := s.client.FindOwnersTeamID()
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	s.checkServerErrors("GET", u, func() error {
+		_, err := s.client.FindOwnersTeamID()
+		return err
+	})
+}
+
 func (s *Suite) TestListTeamProjects() {
 	teamID := 689492
 	expected := []int{423092}
@@ -316,6 +373,28 @@ func (s *Suite) TestListTeamProjects() {
 	})
 }
 
+// TestListTeamUserIDs tests listing the registered users belonging to a
+// Rollbar team.
+func (s *Suite) TestListTeamUserIDs() {
+	teamID := 689492
+	expected := []int{238101, 238102}
+	u := s.client.BaseURL + pathTeamUsers
+	u = strings.ReplaceAll(u, "{teamID}", strconv.Itoa(teamID))
+	r := responderFromFixture("team/list_users_689492.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=1", r)
+	r = responderFromFixture("team/list_users_689493.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u+"?page=2", r)
+
+	actual, err := s.client.ListTeamUserIDs(teamID)
+	s.Nil(err)
+	s.Equal(expected, actual)
+
+	s.checkServerErrors("GET", u+"?page=1", func() error {
+		_, err := s.client.ListTeamUserIDs(teamID)
+		return err
+	})
+}
+
 // TestAssignTeamToProject tests assigning a Rollbar team to a project.
 func (s *Suite) TestAssignTeamToProject() {
 	teamID := 689492
@@ -353,3 +432,12 @@ func (s *Suite) TestRemoveTeamFromProject() {
 		return err
 	})
 }
+
+// TestValidTeamAccessLevel tests validation of team access levels.
+func (s *Suite) TestValidTeamAccessLevel() {
+	for _, level := range []string{"standard", "light", "view"} {
+		s.True(ValidTeamAccessLevel(level))
+	}
+	s.False(ValidTeamAccessLevel("owner"))
+	s.False(ValidTeamAccessLevel(""))
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes the HTTP connections used by a client.  A zero value
+// for any field keeps the corresponding default of http.DefaultTransport, or,
+// for RequestTimeout, means requests never time out.
+type TransportOptions struct {
+	MaxIdleConns        int           // Maximum idle connections across all hosts
+	MaxIdleConnsPerHost int           // Maximum idle connections to the Rollbar API
+	IdleConnTimeout     time.Duration // How long an idle connection is kept open
+	KeepAlive           time.Duration // Interval between TCP keep-alive probes
+	RequestTimeout      time.Duration // Time limit for each request, including reading the response
+}
+
+// SetTransportOptions configures the client's HTTP connections.  It replaces
+// the client's transport, so must be called before SetLimiter or
+// EnableConditionalRequests, which wrap it.
+func (c *RollbarAPIClient) SetTransportOptions(o TransportOptions) {
+	if o.RequestTimeout > 0 {
+		c.Resty.SetTimeout(o.RequestTimeout)
+	}
+	if o.MaxIdleConns <= 0 && o.MaxIdleConnsPerHost <= 0 && o.IdleConnTimeout <= 0 && o.KeepAlive <= 0 {
+		return
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.MaxIdleConns > 0 {
+		t.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = o.IdleConnTimeout
+	}
+	if o.KeepAlive > 0 {
+		// Same dial timeout as http.DefaultTransport
+		d := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: o.KeepAlive,
+		}
+		t.DialContext = d.DialContext
+	}
+	c.Resty.SetTransport(t)
+}
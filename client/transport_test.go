@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestSetTransportOptions tests tuning a client's HTTP connections.
+func TestSetTransportOptions(t *testing.T) {
+	// Zero options keep the default transport
+	c := NewClient("fakeTokenString")
+	c.SetTransportOptions(TransportOptions{})
+	assert.Equal(t, http.DefaultTransport, c.Resty.GetClient().Transport)
+	assert.Equal(t, time.Duration(0), c.Resty.GetClient().Timeout)
+
+	c = NewClient("fakeTokenString")
+	c.SetTransportOptions(TransportOptions{
+		MaxIdleConns:        200,
+		MaxIdleConnsPerHost: 50,
+		IdleConnTimeout:     2 * time.Minute,
+		KeepAlive:           15 * time.Second,
+		RequestTimeout:      time.Minute,
+	})
+	tr, ok := c.Resty.GetClient().Transport.(*http.Transport)
+	assert.True(t, ok)
+	assert.Equal(t, 200, tr.MaxIdleConns)
+	assert.Equal(t, 50, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, 2*time.Minute, tr.IdleConnTimeout)
+	assert.NotNil(t, tr.DialContext)
+	assert.Equal(t, time.Minute, c.Resty.GetClient().Timeout)
+
+	// Unset options keep their defaults
+	def := http.DefaultTransport.(*http.Transport)
+	c = NewClient("fakeTokenString")
+	c.SetTransportOptions(TransportOptions{MaxIdleConnsPerHost: 10})
+	tr = c.Resty.GetClient().Transport.(*http.Transport)
+	assert.Equal(t, def.MaxIdleConns, tr.MaxIdleConns)
+	assert.Equal(t, 10, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, def.IdleConnTimeout, tr.IdleConnTimeout)
+}
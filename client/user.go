@@ -23,7 +23,13 @@
 package client
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/rs/zerolog/log"
+	"io"
 	"strconv"
 )
 
@@ -37,29 +43,71 @@ type User struct {
 	// EmailEnabled bool   `json:"email_enabled"`
 }
 
-// ListUsers lists all Rollbar users.
+// ListUsers lists all Rollbar users.  For accounts with very many users, prefer
+// EachUser, which does not hold them all in memory at once.
 func (c *RollbarAPIClient) ListUsers() (users []User, err error) {
+	users = []User{}
+	err = c.EachUser(func(u User) error {
+		users = append(users, u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// EachUser calls fn with each Rollbar user in turn.  The API returns all users
+// in a single response, which is decoded one user at a time as it is read, so
+// that callers keeping only the users they need process even very large
+// accounts without holding the response in memory.  If fn returns an error,
+// EachUser stops and returns that error.
+func (c *RollbarAPIClient) EachUser(fn func(User) error) error {
 	log.Debug().Msg("Listing users")
 	u := c.BaseURL + pathUsers
-	resp, err := c.Resty.R().
-		SetResult(userListResponse{}).
+	r := c.request().
 		SetError(ErrorResult{}).
-		Get(u)
+		SetDoNotParseResponse(true)
+	r.SetContext(context.WithValue(r.Context(), noConditionalKey{}, true))
+	resp, err := r.Get(u)
 	if err != nil {
 		log.Err(err).Msg("Error listing users")
-		return
+		return err
+	}
+	body := resp.RawBody()
+	defer func() { _ = body.Close() }()
+
+	// Responses not parsed by resty skip its OnAfterResponse hooks
+	_ = logResponse(c.Resty, resp)
+	if c.compat {
+		_ = normalizeSuccess(c.Resty, resp)
+	}
+	if resp.IsError() {
+		// Nor are error bodies decoded
+		_ = json.NewDecoder(body).Decode(resp.Error())
 	}
 	err = errorFromResponse(resp)
 	if err != nil {
 		log.Err(err).Msg("Error listing users")
-		return
+		return err
+	}
+	count := 0
+	br := bufio.NewReader(body)
+	if empty, err := blankBody(br); err != nil || empty {
+		return err // An empty body is as if there were no users
+	}
+	err = decodeUserList(br, func(u User) error {
+		count++
+		return fn(u)
+	})
+	if err != nil {
+		log.Err(err).Msg("Error listing users")
+		return err
 	}
-	users = resp.Result().(*userListResponse).Result.Users
-	count := len(users)
 	log.Debug().
 		Int("count", count).
 		Msg("Successfully listed users")
-	return
+	return nil
 }
 
 // ReadUser reads a Rollbar user from the API.
@@ -67,7 +115,7 @@ func (c *RollbarAPIClient) ReadUser(id int) (user User, err error) {
 	l := log.With().Int("id", id).Logger()
 	l.Debug().Msg("Reading user from API")
 	u := c.BaseURL + pathUser
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{"userID": strconv.Itoa(id)}).
 		SetResult(userReadResponse{}).
 		SetError(ErrorResult{}).
@@ -93,19 +141,24 @@ func (c *RollbarAPIClient) ReadUser(id int) (user User, err error) {
 func (c *RollbarAPIClient) FindUserID(email string) (int, error) {
 	l := log.With().Str("email", email).Logger()
 	l.Debug().Msg("Getting user ID from email")
-	users, err := c.ListUsers()
-	if err != nil {
+	userID := 0
+	err := c.EachUser(func(u User) error {
+		if u.Email == email {
+			userID = u.ID
+			return errStopListing
+		}
+		return nil
+	})
+	if err != nil && err != errStopListing {
 		l.Err(err).Msg("Error getting user ID from email")
 		return 0, err
 	}
-	for _, u := range users {
-		if u.Email == email {
-			l.Debug().Int("user_id", u.ID).Msg("Found user")
-			return u.ID, nil
-		}
+	if userID == 0 {
+		l.Debug().Msg("No user found")
+		return 0, ErrNotFound
 	}
-	l.Debug().Msg("No user found")
-	return 0, ErrNotFound
+	l.Debug().Int("user_id", userID).Msg("Found user")
+	return userID, nil
 }
 
 // ListUserTeams lists a Rollbar user's teams.
@@ -113,7 +166,7 @@ func (c *RollbarAPIClient) ListUserTeams(userID int) (teams []Team, err error) {
 	l := log.With().Int("userID", userID).Logger()
 	l.Debug().Msg("Reading teams for Rollbar user")
 	u := c.BaseURL + pathUserTeams
-	resp, err := c.Resty.R().
+	resp, err := c.request().
 		SetPathParams(map[string]string{"userID": strconv.Itoa(userID)}).
 		SetResult(userTeamListResponse{}).
 		SetError(ErrorResult{}).
@@ -134,6 +187,28 @@ func (c *RollbarAPIClient) ListUserTeams(userID int) (teams []Team, err error) {
 	return
 }
 
+// RemoveUserFromAccount removes a registered Rollbar user from the account
+// entirely, revoking access to all teams and projects.
+func (c *RollbarAPIClient) RemoveUserFromAccount(userID int) error {
+	l := log.With().Int("userID", userID).Logger()
+	l.Debug().Msg("Removing user from account")
+	resp, err := c.request().
+		SetPathParams(map[string]string{"userID": strconv.Itoa(userID)}).
+		SetError(ErrorResult{}).
+		Delete(c.BaseURL + pathUser)
+	if err != nil {
+		l.Err(err).Msg("Error removing user from account")
+		return err
+	}
+	err = errorFromResponse(resp)
+	if err != nil {
+		l.Err(err).Msg("Error removing user from account")
+		return err
+	}
+	l.Debug().Msg("Successfully removed user from account")
+	return nil
+}
+
 // ListUserCustomTeams lists a Rollbar user's custom defined teams, excluding
 // system teams "Everyone" and "Owners".
 func (c *RollbarAPIClient) ListUserCustomTeams(userID int) (teams []Team, err error) {
@@ -146,13 +221,6 @@ func (c *RollbarAPIClient) ListUserCustomTeams(userID int) (teams []Team, err er
  * Containers for unmarshalling Rollbar API responses
  */
 
-type userListResponse struct {
-	Error  int `json:"err"`
-	Result struct {
-		Users []User `json:"users"`
-	} `json:"result"`
-}
-
 type userReadResponse struct {
 	Error  int  `json:"err"`
 	Result User `json:"result"`
@@ -164,3 +232,95 @@ type userTeamListResponse struct {
 		Teams []Team `json:"teams"`
 	} `json:"result"`
 }
+
+// errStopListing is returned by callbacks of EachUser to stop listing early.
+var errStopListing = errors.New("stop listing")
+
+// blankBody returns true if r holds nothing but whitespace, leaving anything
+// else to be read.
+func blankBody(r *bufio.Reader) (bool, error) {
+	for {
+		b, err := r.ReadByte()
+		switch {
+		case err == io.EOF:
+			return true, nil
+		case err != nil:
+			return false, err
+		case b != ' ' && b != '\t' && b != '\r' && b != '\n':
+			return false, r.UnreadByte()
+		}
+	}
+}
+
+// decodeUserList decodes a user list response, {"result": {"users": [...]}},
+// from r, calling fn with each user as it is decoded.
+func decodeUserList(r io.Reader, fn func(User) error) error {
+	dec := json.NewDecoder(r)
+	return decodeObject(dec, func(key string) error {
+		if key != "result" {
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(key string) error {
+			if key != "users" {
+				return skipValue(dec)
+			}
+			return decodeArray(dec, func() error {
+				var u User
+				if err := dec.Decode(&u); err != nil {
+					return err
+				}
+				return fn(u)
+			})
+		})
+	})
+}
+
+// decodeObject decodes a JSON object from dec, calling field with the key of
+// each member, which must decode or skip the member's value.  A null is taken
+// for an empty object.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	t, err := dec.Token()
+	if err != nil || t == nil {
+		return err
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf("expected JSON object, got %v", t)
+	}
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		if err = field(key); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // }
+	return err
+}
+
+// decodeArray decodes a JSON array from dec, calling elem to decode each
+// element.  A null is taken for an empty array.
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	t, err := dec.Token()
+	if err != nil || t == nil {
+		return err
+	}
+	if t != json.Delim('[') {
+		return fmt.Errorf("expected JSON array, got %v", t)
+	}
+	for dec.More() {
+		if err = elem(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // ]
+	return err
+}
+
+// skipValue skips the next JSON value from dec.
+func skipValue(dec *json.Decoder) error {
+	var v json.RawMessage
+	return dec.Decode(&v)
+}
@@ -23,10 +23,13 @@
 package client
 
 import (
+	"errors"
 	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"strconv"
 	"strings"
+	"testing"
 )
 
 // TestListUsers tests listing all Rollbar users.
@@ -59,6 +62,82 @@ func (s *Suite) TestListUsers() {
 	})
 }
 
+// TestEachUser tests processing Rollbar users one at a time.
+func (s *Suite) TestEachUser() {
+	u := s.client.BaseURL + pathUsers
+	r := responderFromFixture("user/list.json", http.StatusOK)
+	httpmock.RegisterResponder("GET", u, r)
+
+	var ids []int
+	err := s.client.EachUser(func(u User) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	s.Nil(err)
+	s.Len(ids, 2)
+	s.Contains(ids, 238101)
+
+	// Errors from the callback stop listing
+	calls := 0
+	stop := errors.New("stop")
+	err = s.client.EachUser(func(u User) error {
+		calls++
+		return stop
+	})
+	s.Equal(stop, err)
+	s.Equal(1, calls)
+
+	// An empty body is as if there were no users
+	httpmock.RegisterResponder("GET", u, httpmock.NewStringResponder(http.StatusOK, " \n"))
+	calls = 0
+	err = s.client.EachUser(func(u User) error {
+		calls++
+		return nil
+	})
+	s.Nil(err)
+	s.Zero(calls)
+
+	// Error bodies are decoded although the response is not parsed by resty
+	httpmock.RegisterResponder("GET", u, httpmock.NewJsonResponderOrPanic(http.StatusForbidden,
+		ErrorResult{Err: 1, Message: "Forbidden"}))
+	err = s.client.EachUser(func(User) error { return nil })
+	var er *ErrorResult
+	if s.True(errors.As(err, &er)) {
+		s.Equal("Forbidden", er.Message)
+	}
+
+	s.checkServerErrors("GET", u, func() error {
+		return s.client.EachUser(func(User) error { return nil })
+	})
+}
+
+// TestDecodeUserList tests decoding user list responses incrementally.
+func TestDecodeUserList(t *testing.T) {
+	decode := func(body string) ([]User, error) {
+		var users []User
+		err := decodeUserList(strings.NewReader(body), func(u User) error {
+			users = append(users, u)
+			return nil
+		})
+		return users, err
+	}
+
+	users, err := decode(`{"err": 0, "result": {"page": 1, "users": [{"id": 1, "email": "a@example.com"}, {"id": 2, "username": "b"}], "extra": {"x": [1]}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []User{{ID: 1, Email: "a@example.com"}, {ID: 2, Username: "b"}}, users)
+
+	for _, body := range []string{`{}`, `{"result": null}`, `{"result": {"users": null}}`, `null`} {
+		users, err = decode(body)
+		assert.NoError(t, err, body)
+		assert.Empty(t, users, body)
+	}
+
+	for _, body := range []string{`[]`, `{"result": []}`, `{"result": {"users": {}}}`, `{"result": {"users": [{"id": "x"}]}}`, `{"result": {"users": [`} {
+		_, err = decode(body)
+		assert.Error(t, err, body)
+	}
+}
+
 // TestReadUser tests reading a Rollbar user from the API.
 func (s *Suite) TestReadUser() {
 	userID := 238101
@@ -147,6 +226,23 @@ func (s *Suite) TestListUserTeams() {
 	})
 }
 
+// TestRemoveUserFromAccount tests removing a Rollbar user from the account.
+func (s *Suite) TestRemoveUserFromAccount() {
+	userID := 238101
+	u := s.client.BaseURL + pathUser
+	u = strings.ReplaceAll(u, "{userID}", strconv.Itoa(userID))
+
+	// Success
+	r := responderFromFixture("user/remove.json", http.StatusOK)
+	httpmock.RegisterResponder("DELETE", u, r)
+	err := s.client.RemoveUserFromAccount(userID)
+	s.Nil(err)
+
+	s.checkServerErrors("DELETE", u, func() error {
+		return s.client.RemoveUserFromAccount(userID)
+	})
+}
+
 // TestListUserTeams tests listing custom defined teams for a Rollbar user.
 func (s *Suite) TestListUserCustomTeams() {
 	userID := 238101
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestClientLifecycle exercises create, read, update, and delete of projects,
+// project access tokens, and teams.  The cassette it replays is synthetic,
+// written by hand rather than recorded.
+//
+// To replace it with a recording against a live Rollbar account:
+//
+//	ROLLBAR_VCR_RECORD=1 ROLLBAR_API_KEY=<token> go test ./client -run TestClientLifecycle
+func TestClientLifecycle(t *testing.T) {
+	c := newVCRClient(t, "client_lifecycle_synthetic")
+	name := "tf-acc-test-clientlifecycle"
+
+	// Project
+	project, err := c.CreateProject(name)
+	assert.Nil(t, err)
+	assert.Equal(t, name, project.Name)
+	project, err = c.ReadProject(project.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, name, project.Name)
+	project, err = c.UpdateProject(project.ID, name+"-1")
+	assert.Nil(t, err)
+	assert.Equal(t, name+"-1", project.Name)
+
+	// Project access token
+	pat, err := c.CreateProjectAccessToken(ProjectAccessTokenCreateArgs{
+		ProjectID:            project.ID,
+		Name:                 name,
+		Scopes:               []Scope{ScopeRead},
+		Status:               StatusEnabled,
+		RateLimitWindowSize:  60,
+		RateLimitWindowCount: 500,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, name, pat.Name)
+	readPat, err := c.ReadProjectAccessToken(project.ID, pat.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, pat, readPat)
+	err = c.DeleteProjectAccessToken(project.ID, pat.AccessToken)
+	assert.Nil(t, err)
+
+	// Team
+	team, err := c.CreateTeam(name, "standard")
+	assert.Nil(t, err)
+	assert.Equal(t, name, team.Name)
+	team, err = c.UpdateTeam(team.ID, name+"-1", "standard")
+	assert.Nil(t, err)
+	assert.Equal(t, name+"-1", team.Name)
+	team, err = c.ReadTeam(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, name+"-1", team.Name)
+	err = c.DeleteTeam(team.ID)
+	assert.Nil(t, err)
+
+	// Cleanup
+	err = c.DeleteProject(project.ID)
+	assert.Nil(t, err)
+	_, err = c.ReadProject(project.ID)
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// wireLogSecrets matches the access token header, JSON fields holding access
+// tokens or service keys, and access tokens in query parameters or, as in
+// RedactURLPath, in project access token paths, capturing the secret to be
+// redacted.
+var wireLogSecrets = []*regexp.Regexp{
+	regexp.MustCompile(`(?im)^X-Rollbar-Access-Token: (.*?)\r?$`),
+	regexp.MustCompile(`"(?:access_token|service_key)"\s*:\s*"([^"]*)"`),
+	regexp.MustCompile(`[?&]access_token=([^&\s]*)`),
+	regexp.MustCompile(`/access_token/([^/?#\s"]*)`),
+}
+
+// EnableWireLog configures the client to write a trace of every request and
+// response, including headers and bodies, to w.  Access tokens and service keys
+// are redacted.  Unlike resty's debug mode, this needs no rebuild, and bodies
+// are traced as sent and received before compression.
+func (c *RollbarAPIClient) EnableWireLog(w io.Writer) {
+	hc := c.Resty.GetClient()
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc.Transport = &wireLogTransport{next: next, w: w}
+}
+
+// wireLogTransport is an http.RoundTripper that traces requests and responses.
+type wireLogTransport struct {
+	next http.RoundTripper
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *wireLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var buf bytes.Buffer
+	start := time.Now()
+	fmt.Fprintf(&buf, "---- %s request\n", start.UTC().Format(time.RFC3339Nano))
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(dump)
+
+	resp, err := t.next.RoundTrip(req)
+	fmt.Fprintf(&buf, "\n---- response after %s\n", time.Since(start))
+	if err != nil {
+		fmt.Fprintf(&buf, "error: %s\n", err)
+	} else {
+		dump, err = httputil.DumpResponse(resp, true)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		buf.Write(dump)
+	}
+	buf.WriteString("\n")
+	t.write(buf.Bytes())
+	return resp, err
+}
+
+// write writes a redacted trace in a single call, so traces of concurrent
+// requests are not interleaved.
+func (t *wireLogTransport) write(trace []byte) {
+	for _, re := range wireLogSecrets {
+		trace = re.ReplaceAllFunc(trace, func(match []byte) []byte {
+			secret := re.FindSubmatch(match)[1]
+			if len(secret) == 0 {
+				return match
+			}
+			return bytes.Replace(match, secret, []byte(RedactToken(string(secret))), 1)
+		})
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(trace)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package client
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWireLog tests tracing requests and responses with secrets redacted.
+func TestWireLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"err": 0, "result": {"name": "foobar", "access_token": "secret-token-1234"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := NewClient("secret-api-key-5678", WithBaseURL(srv.URL))
+	c.EnableCompression(1)
+	c.EnableWireLog(&buf)
+	_, err := c.CreateProject("foobar")
+	assert.Nil(t, err)
+
+	trace := buf.String()
+	assert.Contains(t, trace, "POST /api/1/projects")
+	assert.Contains(t, trace, `{"name":"foobar"}`, "request body should be traced uncompressed")
+	assert.Contains(t, trace, "200 OK")
+	assert.Contains(t, trace, "X-Rollbar-Access-Token: ****5678")
+	assert.Contains(t, trace, `"access_token": "****1234"`)
+	assert.NotContains(t, trace, "secret-api-key")
+	assert.NotContains(t, trace, "secret-token")
+
+	// Token sent as a query parameter
+	buf.Reset()
+	c = NewClient("secret-api-key-5678", WithBaseURL(srv.URL), WithAuthMethod(AuthQuery))
+	c.EnableWireLog(&buf)
+	_, err = c.CreateProject("foobar")
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "?access_token=****5678")
+	assert.NotContains(t, buf.String(), "secret-api-key")
+
+	// Token in the path of project access token requests
+	const token = "secret-path-token-9abc"
+	buf.Reset()
+	c = NewClient("secret-api-key-5678", WithBaseURL(srv.URL))
+	c.EnableWireLog(&buf)
+	_, err = c.ReadProjectAccessToken(1, token)
+	assert.Nil(t, err)
+	err = c.UpdateProjectAccessToken(ProjectAccessTokenUpdateArgs{ProjectID: 1, AccessToken: token, RateLimitWindowSize: 60})
+	assert.Nil(t, err)
+	err = c.DeleteProjectAccessToken(1, token)
+	assert.Nil(t, err)
+	trace = buf.String()
+	assert.Contains(t, trace, "GET /api/1/project/1/access_token/****9abc")
+	assert.Contains(t, trace, "PATCH /api/1/project/1/access_token/****9abc")
+	assert.Contains(t, trace, "DELETE /api/1/project/1/access_token/****9abc")
+	assert.NotContains(t, trace, "secret-path-token")
+
+	// Token in the URL of a connection error
+	buf.Reset()
+	c = NewClient("secret-api-key-5678", WithBaseURL("http://127.0.0.1:1"))
+	c.EnableWireLog(&buf)
+	_, err = c.ReadProjectAccessToken(1, token)
+	assert.NotNil(t, err)
+	trace = buf.String()
+	assert.Contains(t, trace, "error: ")
+	assert.Contains(t, trace, "/access_token/****9abc")
+	assert.NotContains(t, trace, "secret-path-token")
+}
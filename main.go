@@ -23,13 +23,20 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/rollbar/terraform-provider-rollbar/rollbar"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os"
 )
 
+// providerAddress is the Terraform registry address of the provider.
+const providerAddress = "registry.terraform.io/rollbar/rollbar"
+
 func main() {
 	// Configure logging
 	if os.Getenv("TERRAFORM_PROVIDER_ROLLBAR_DEBUG") == "1" {
@@ -46,11 +53,29 @@ func main() {
 			With().Caller().
 			Logger()
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-
+	} else {
+		// Until the provider is configured, log as set in the environment
+		err := rollbar.ConfigureLogging(os.Getenv("ROLLBAR_LOG_LEVEL"), os.Getenv("ROLLBAR_LOG_FORMAT"))
+		if err != nil {
+			log.Warn().Err(err).Msg("Invalid logging configuration")
+		}
 	}
 
-	// Serve the plugin
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: rollbar.Provider,
+	// Serve the plugin.  The SDK provider implements most resources; the
+	// framework provider adds features the SDK lacks, e.g. ephemeral resources.
+	// Plans replacing SDK resources are annotated with a warning.
+	ctx := context.Background()
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		rollbar.WithReplacementNotes(rollbar.Provider().GRPCProvider),
+		providerserver.NewProtocol5(rollbar.NewFrameworkProvider()),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating provider server")
+	}
+	err = tf5server.Serve(providerAddress, func() tfprotov5.ProviderServer {
+		return muxServer.ProviderServer()
 	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error serving provider")
+	}
 }
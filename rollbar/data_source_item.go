@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+func dataSourceItem() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceItemRead,
+
+		Schema: map[string]*schema.Schema{
+			"counter": {
+				Description: "Project counter of the item, the number shown in the item's URL in the Rollbar UI",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+
+			"item_id": {
+				Description: "Internal ID of the item",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"project_id": {
+				Description: "ID of the project to which the item belongs",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"title": {
+				Description: "Title of the item",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"level": {
+				Description: "Level of the item, e.g. `error` or `warning`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "Status of the item, e.g. `active` or `resolved`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"environment": {
+				Description: "Environment in which the item occurred",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceItemRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	counter := d.Get("counter").(int)
+	l := log.With().
+		Int("counter", counter).
+		Logger()
+	l.Debug().Msg("Reading item by counter from API")
+
+	// Items belong to a project, so are read with the project access token
+	c, err := clientFor(ctx, m, "rollbar_item", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	item, err := c.ReadItemByCounter(counter)
+	if err == client.ErrNotFound {
+		return diag.Errorf("no item with the counter %d found", counter)
+	}
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err, "counter")
+	}
+
+	d.SetId(strconv.Itoa(item.ID))
+	mustSet(d, "item_id", item.ID)
+	mustSet(d, "project_id", item.ProjectID)
+	mustSet(d, "title", item.Title)
+	mustSet(d, "level", item.Level)
+	mustSet(d, "status", item.Status)
+	mustSet(d, "environment", item.Environment)
+	l.Debug().Msg("Successfully read item by counter from API")
+	return nil
+}
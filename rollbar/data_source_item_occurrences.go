@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rs/zerolog/log"
+)
+
+func dataSourceItemOccurrences() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceItemOccurrencesRead,
+
+		Schema: map[string]*schema.Schema{
+			"item_id": {
+				Description: "Internal ID of the item",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"page": {
+				Description:  "First page of occurrences to read.  Pages are numbered from 1, most recent occurrences first",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"max_pages": {
+				Description:  "Maximum number of pages of occurrences to read",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"occurrences": {
+				Description: "Occurrences of the item, most recent first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of occurrence",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"uuid": {
+							Description: "UUID of occurrence",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"timestamp": {
+							Description: "Date of occurrence",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceItemOccurrencesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	itemID := d.Get("item_id").(int)
+	page := d.Get("page").(int)
+	maxPages := d.Get("max_pages").(int)
+	l := log.With().
+		Int("item_id", itemID).
+		Int("page", page).
+		Int("max_pages", maxPages).
+		Logger()
+	l.Debug().Msg("Reading item occurrences from API")
+
+	// Items belong to a project, so are read with the project access token
+	c, err := clientFor(ctx, m, "rollbar_item_occurrences", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	occurrences := []map[string]interface{}{}
+	for p := page; p < page+maxPages; p++ {
+		result, err := c.ListItemOccurrences(itemID, p)
+		if err != nil {
+			l.Err(err).Send()
+			return diagFromErr(err, "item_id")
+		}
+		if len(result) == 0 {
+			break
+		}
+		for _, o := range result {
+			occurrences = append(occurrences, map[string]interface{}{
+				"id":        o.ID,
+				"uuid":      o.UUID(),
+				"timestamp": o.Timestamp,
+			})
+		}
+	}
+	mustSet(d, "occurrences", occurrences)
+
+	d.SetId(fmt.Sprintf("%d/%d/%d", itemID, page, maxPages))
+	l.Debug().
+		Int("count", len(occurrences)).
+		Msg("Successfully read item occurrences from API")
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineItemOccurrencesDataSource tests reading pages of an item's
+// occurrences, starting from `page` and stopping after `max_pages` or at the
+// first empty page.
+func TestOfflineItemOccurrencesDataSource(t *testing.T) {
+	f := newFakeAPI(t)
+	f.items[1] = client.Item{ID: 1, Counter: 1}
+	for id := 105; id > 100; id-- {
+		o := client.Occurrence{ID: id, Timestamp: 1600000000 + id}
+		o.Data.UUID = fmt.Sprintf("uuid-%d", id)
+		f.occurrences[1] = append(f.occurrences[1], o)
+	}
+	m := offlineMeta(f)
+	r := dataSourceItemOccurrences()
+	ctx := context.Background()
+
+	read := func(config map[string]interface{}) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		require.False(t, r.ReadContext(ctx, d, m).HasError())
+		return d
+	}
+	ids := func(d *schema.ResourceData) []int {
+		ids := []int{}
+		for _, o := range d.Get("occurrences").([]interface{}) {
+			ids = append(ids, o.(map[string]interface{})["id"].(int))
+		}
+		return ids
+	}
+
+	// One page by default
+	d := read(map[string]interface{}{"item_id": 1})
+	assert.Equal(t, "1/1/1", d.Id())
+	assert.Equal(t, []int{105, 104}, ids(d))
+	assert.Equal(t, "uuid-105", d.Get("occurrences.0.uuid"))
+	assert.Equal(t, 1600000105, d.Get("occurrences.0.timestamp"))
+
+	// Several pages from a later one
+	d = read(map[string]interface{}{"item_id": 1, "page": 2, "max_pages": 2})
+	assert.Equal(t, "1/2/2", d.Id())
+	assert.Equal(t, []int{103, 102, 101}, ids(d))
+
+	// Reading stops at the first empty page
+	requests := f.requests
+	d = read(map[string]interface{}{"item_id": 1, "max_pages": 10})
+	assert.Equal(t, []int{105, 104, 103, 102, 101}, ids(d))
+	assert.Equal(t, 4, f.requests-requests)
+
+	// Pages past the last are empty
+	d = read(map[string]interface{}{"item_id": 1, "page": 4})
+	assert.Equal(t, []int{}, ids(d))
+
+	// An unknown item fails
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"item_id": 2})
+	assert.True(t, r.ReadContext(ctx, d, m).HasError())
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineItemDataSource tests reading an item by its project counter.
+func TestOfflineItemDataSource(t *testing.T) {
+	f := newFakeAPI(t)
+	f.items[1021510621] = client.Item{
+		ID:          1021510621,
+		Counter:     42,
+		ProjectID:   411703,
+		Title:       "TypeError: Cannot read property 'foo' of undefined",
+		Level:       "error",
+		Status:      "active",
+		Environment: "production",
+	}
+	m := offlineMeta(f)
+	r := dataSourceItem()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"counter": 42})
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, "1021510621", d.Id())
+	assert.Equal(t, 1021510621, d.Get("item_id"))
+	assert.Equal(t, 411703, d.Get("project_id"))
+	assert.Equal(t, "TypeError: Cannot read property 'foo' of undefined", d.Get("title"))
+	assert.Equal(t, "error", d.Get("level"))
+	assert.Equal(t, "active", d.Get("status"))
+	assert.Equal(t, "production", d.Get("environment"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"counter": 43})
+	diags := r.ReadContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "no item with the counter 43 found", diags[0].Summary)
+}
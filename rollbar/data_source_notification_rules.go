@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// dataSourceNotificationRules is a data source listing the notification rules
+// of a channel in the project of the provider's project access token.
+func dataSourceNotificationRules() *schema.Resource {
+	computed := func(typ schema.ValueType, description string) *schema.Schema {
+		return &schema.Schema{Type: typ, Computed: true, Description: description}
+	}
+	computedList := func(description string) *schema.Schema {
+		return &schema.Schema{Type: schema.TypeList, Computed: true, Description: description, Elem: &schema.Schema{Type: schema.TypeString}}
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceNotificationRulesRead,
+		Schema: map[string]*schema.Schema{
+			"channel": {
+				Description: "Notification channel, e.g. `email`, `slack` or `pagerduty`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"rules": {
+				Description: "Notification rules of the channel",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":      computed(schema.TypeInt, "ID of the rule"),
+						"action":  computed(schema.TypeString, "Action taken when the rule is triggered"),
+						"trigger": computed(schema.TypeString, "Trigger"),
+						"filters": {
+							Description: "Filters",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type":      computed(schema.TypeString, "Type"),
+									"operation": computed(schema.TypeString, "Operation"),
+									"value":     computed(schema.TypeString, "Value"),
+									"path":      computed(schema.TypeString, "Path of the occurrence field compared (path filters only)"),
+									"period":    computed(schema.TypeFloat, "Period (rate filters only)"),
+									"count":     computed(schema.TypeFloat, "Count (rate filters only)"),
+								},
+							},
+						},
+						"config": {
+							Description: "Channel configuration, without any service key",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"users":                computedList("Users (email)"),
+									"teams":                computedList("Teams (email)"),
+									"message_template":     computed(schema.TypeString, "Message template (slack)"),
+									"channel":              computed(schema.TypeString, "Channel (slack)"),
+									"show_message_buttons": computed(schema.TypeBool, "Show message buttons (slack)"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceNotificationRulesRead reads the notification rules of a channel
+// from the API.
+func dataSourceNotificationRulesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	channel := d.Get("channel").(string)
+	l := log.With().Str("channel", channel).Logger()
+	l.Debug().Msg("Reading notification rules from API")
+	c, err := clientFor(ctx, m, "rollbar_notification_rules", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	notifications, err := c.ListNotifications(channel)
+	if err != nil {
+		return diagFromErr(err, "channel")
+	}
+	rules := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		rules[i] = flattenNotificationRule(n)
+	}
+	mustSet(d, "rules", rules)
+	d.SetId(channel)
+
+	l.Debug().Int("count", len(rules)).Msg("Successfully read notification rules from API")
+	return nil
+}
+
+// flattenNotificationRule converts a notification rule from the API into a
+// value for the `rules` attribute.
+func flattenNotificationRule(n client.Notification) map[string]interface{} {
+	filters := make([]interface{}, 0, len(n.Filters))
+	for _, f := range n.Filters {
+		fm, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filter := map[string]interface{}{}
+		for _, key := range []string{"type", "operation", "value", "path"} {
+			switch v := fm[key].(type) {
+			case nil:
+			case string:
+				filter[key] = v
+			case float64:
+				filter[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				filter[key] = fmt.Sprint(v)
+			}
+		}
+		for _, key := range []string{"period", "count"} {
+			if v, ok := fm[key].(float64); ok {
+				filter[key] = v
+			}
+		}
+		filters = append(filters, filter)
+	}
+	config := map[string]interface{}{}
+	for _, key := range []string{"users", "teams", "message_template", "channel", "show_message_buttons"} {
+		if v, ok := n.Config[key]; ok {
+			config[key] = v
+		}
+	}
+	return map[string]interface{}{
+		"id":      n.ID,
+		"action":  n.Action,
+		"trigger": n.Trigger,
+		"filters": filters,
+		"config":  []interface{}{config},
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineNotificationRulesDataSource tests listing the notification rules
+// of a channel.
+func TestOfflineNotificationRulesDataSource(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	r := dataSourceNotificationRules()
+	ctx := context.Background()
+
+	_, err := c.CreateNotification("email",
+		[]interface{}{map[string]interface{}{"type": "level", "operation": "gte", "value": "error"}},
+		"new_item",
+		map[string]interface{}{"teams": []string{"Owners"}},
+	)
+	require.NoError(t, err)
+	_, err = c.CreateNotification("email",
+		[]interface{}{map[string]interface{}{"type": "rate", "period": 300, "count": 10}},
+		"occurrence_rate",
+		map[string]interface{}{},
+	)
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"channel": "email",
+	})
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, 2, d.Get("rules.#"))
+	assert.Equal(t, "send_email", d.Get("rules.0.action"))
+	assert.Equal(t, "new_item", d.Get("rules.0.trigger"))
+	assert.Equal(t, "error", d.Get("rules.0.filters.0.value"))
+	assert.Equal(t, []interface{}{"Owners"}, d.Get("rules.0.config.0.teams"))
+	assert.Equal(t, "occurrence_rate", d.Get("rules.1.trigger"))
+	assert.Equal(t, 300.0, d.Get("rules.1.filters.0.period"))
+
+	// Other channels are listed separately
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"channel": "slack",
+	})
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, 0, d.Get("rules.#"))
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rs/zerolog/log"
+)
+
+func dataSourcePeople() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePeopleRead,
+		Schema: map[string]*schema.Schema{
+			"people": {
+				Description: "Persons tracked by the project",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of person",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"username": {
+							Description: "Username of person",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"email": {
+							Description: "Email address of person",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"last_seen": {
+							Description: "Date the person was last seen",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePeopleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Debug().Msg("Reading people list from API")
+	var diags diag.Diagnostics
+	// People belong to a project, so are listed with the project access token
+	c, err := clientFor(ctx, m, "rollbar_people", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	people, err := c.ListPeople()
+	if err != nil {
+		return diagFromErr(err)
+	}
+	mustSet(d, "people", people)
+
+	// Set resource ID to current timestamp (every resource must have an ID or
+	// it will be destroyed).
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	log.Debug().Msg("Successfully read people list from API.")
+	return diags
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2020 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccPeopleDataSource tests listing of all persons with `rollbar_people`
+// data source.
+func (s *AccSuite) TestAccPeopleDataSource() {
+	rn := "data.rollbar_people.all"
+	// language=hcl
+	config := `
+		data "rollbar_people" "all" {}
+	`
+	resource.Test(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(rn, "people.#"),
+				),
+			},
+		},
+	})
+}
+
+// TestOfflinePeopleDataSource tests listing every page of the persons tracked
+// by a project.
+func TestOfflinePeopleDataSource(t *testing.T) {
+	f := newFakeAPI(t)
+	for id := 1; id <= 5; id++ {
+		f.people[id] = client.Person{
+			ID:       id,
+			Username: fmt.Sprintf("person%d", id),
+			Email:    fmt.Sprintf("person%d@example.com", id),
+			LastSeen: 1600000000 + id,
+		}
+	}
+	m := offlineMeta(f)
+	r := dataSourcePeople()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+
+	requests := f.requests
+	require.False(t, r.ReadContext(context.Background(), d, m).HasError())
+	assert.NotEmpty(t, d.Id())
+	// Three pages of people, then an empty one
+	assert.Equal(t, 4, f.requests-requests)
+	assert.Equal(t, 5, d.Get("people.#"))
+	for i := 0; i < 5; i++ {
+		p := d.Get(fmt.Sprintf("people.%d", i)).(map[string]interface{})
+		assert.Equal(t, i+1, p["id"])
+		assert.Equal(t, fmt.Sprintf("person%d", i+1), p["username"])
+		assert.Equal(t, fmt.Sprintf("person%d@example.com", i+1), p["email"])
+		assert.Equal(t, 1600000000+i+1, p["last_seen"])
+	}
+
+	// No people
+	f.people = map[int]client.Person{}
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	require.False(t, r.ReadContext(context.Background(), d, m).HasError())
+	assert.Equal(t, 0, d.Get("people.#"))
+}
@@ -23,14 +23,16 @@
 package rollbar
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 )
 
 func dataSourceProject() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceProjectRead,
+		ReadContext: dataSourceProjectRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -69,26 +71,20 @@ func dataSourceProject() *schema.Resource {
 	}
 }
 
-func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	pl, err := c.ListProjects()
+	c, err := clientFor(ctx, meta, "rollbar_project", "read")
 	if err != nil {
-		return err
+		return diagFromErr(err)
 	}
-
-	var project client.Project
-	var found bool
-	for _, p := range pl {
-		if p.Name == name {
-			found = true
-			project = p
-		}
-	}
-	if !found {
+	project, err := c.GetProjectByName(name)
+	if err == client.ErrNotFound {
 		d.SetId("")
-		return fmt.Errorf("no project with the name %s found", name)
+		return diag.Errorf("no project with the name %s found", name)
+	}
+	if err != nil {
+		return diagFromErr(err, "name")
 	}
 
 	id := fmt.Sprintf("%d", project.ID)
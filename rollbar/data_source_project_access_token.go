@@ -27,29 +27,48 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"strconv"
 	"time"
 )
 
-// dataSourceProjectAccessToken is a data source returning a named access token
-// belonging to a Rollbar project.
+// dataSourceProjectAccessToken is a data source returning an access token
+// belonging to a Rollbar project, selected by name and/or scope, or identified
+// from its value.
 func dataSourceProjectAccessToken() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceProjectAccessTokenRead,
 
 		Schema: map[string]*schema.Schema{
-			// Required fields
+			// Selection
 			"project_id": {
-				Description: "ID of a Rollbar project",
+				Description: "ID of a Rollbar project.  Required unless `token` is set",
 				Type:        schema.TypeInt,
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 			},
 			"name": {
-				Description: "Name of the token",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:  "Name of the token",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"name", "scope", "token"},
+			},
+			"scope": {
+				Description:  fmt.Sprintf("Select the enabled token granted this scope.  Possible values are %s.", quotedList(client.ScopeStrings(), "or")),
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"name", "scope", "token"},
+				ValidateFunc: validation.StringInSlice(client.ScopeStrings(), false),
+			},
+			"token": {
+				Description:   "Identify the token with this value, searching the tokens of `project_id` if set, or else of every project in the account, e.g. to find which project a token found elsewhere belongs to",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"name", "scope"},
 			},
 
 			// Computed fields
@@ -57,6 +76,7 @@ func dataSourceProjectAccessToken() *schema.Resource {
 				Description: "API token",
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 			"cur_rate_limit_window_count": {
 				Description: "Number of API hits that occurred in the current rate limit window",
@@ -89,7 +109,7 @@ func dataSourceProjectAccessToken() *schema.Resource {
 				Computed:    true,
 			},
 			"scopes": {
-				Description: `Project access scopes for the token.  Possible values are "read", "write", "post_server_item", or "post_client_item".`,
+				Description: fmt.Sprintf("Project access scopes for the token.  Possible values are %s.", quotedList(client.ScopeStrings(), "or")),
 				Type:        schema.TypeList,
 				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
@@ -109,31 +129,49 @@ func dataSourceProjectAccessTokenRead(ctx context.Context, d *schema.ResourceDat
 	projectID := d.Get("project_id").(int)
 	var name string
 	name, _ = d.Get("name").(string)
+	scope := client.Scope(d.Get("scope").(string))
 	l := log.With().
 		Int("project_id", projectID).
 		Str("name", name).
+		Str("scope", string(scope)).
 		Logger()
 	l.Debug().Msg("Reading project access token from Rollbar")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	tokens, err := c.ListProjectAccessTokens(projectID)
+	c, err := clientFor(ctx, m, "rollbar_project_access_token", "read")
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
-
-	// Look for a token with matching name
 	var found *client.ProjectAccessToken
-	for i, t := range tokens {
-		if t.Name == name {
-			found = &tokens[i]
+	if token := d.Get("token").(string); token != "" {
+		var projectIDs []int
+		if projectID != 0 {
+			projectIDs = []int{projectID}
+		}
+		pat, err := c.FindProjectAccessToken(token, projectIDs...)
+		switch {
+		case err == client.ErrNotFound && projectID != 0:
+			return diag.Errorf("could not find access token %q in project %d", client.RedactToken(token), projectID)
+		case err == client.ErrNotFound:
+			return diag.Errorf("could not find access token %q in any project", client.RedactToken(token))
+		}
+		if err != nil {
+			l.Err(err).Send()
+			return diagFromErr(err)
+		}
+		found = &pat
+	} else {
+		if projectID == 0 {
+			return diag.Errorf("project_id must be set unless token is set")
+		}
+		tokens, err := c.ListProjectAccessTokens(projectID)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		found, err = findProjectAccessToken(tokens, name, scope)
+		if err != nil {
+			l.Err(err).Send()
+			return diagFromErr(err)
 		}
-	}
-
-	// Error if no token matches.
-	if found == nil {
-		msg := fmt.Sprintf(`could not find access token with name matching "%s"`, name)
-		l.Error().Msg(msg)
-		return diag.FromErr(fmt.Errorf(msg))
 	}
 
 	// Write the values from API to Terraform state
@@ -149,3 +187,44 @@ func dataSourceProjectAccessTokenRead(ctx context.Context, d *schema.ResourceDat
 	// Success
 	return nil
 }
+
+// findProjectAccessToken finds the token named `name`, or if `scope` is not
+// blank, the enabled token granted `scope` (and named `name`, if not blank).
+// It is an error if no token, or more than one token selected by scope,
+// matches.
+func findProjectAccessToken(tokens []client.ProjectAccessToken, name string, scope client.Scope) (*client.ProjectAccessToken, error) {
+	if scope == "" {
+		// Look for a token with matching name
+		var found *client.ProjectAccessToken
+		for i, t := range tokens {
+			if t.Name == name {
+				found = &tokens[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf(`could not find access token with name matching "%s"`, name)
+		}
+		return found, nil
+	}
+
+	var matches []*client.ProjectAccessToken
+	for i, t := range tokens {
+		if t.Status != client.StatusEnabled || (name != "" && t.Name != name) {
+			continue
+		}
+		for _, s := range t.Scopes {
+			if s == scope {
+				matches = append(matches, &tokens[i])
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf(`could not find enabled access token with scope "%s"`, scope)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf(`found %d enabled access tokens with scope "%s"; specify a name to select one`, len(matches), scope)
+	}
+}
@@ -23,8 +23,14 @@
 package rollbar
 
 import (
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
 )
 
 // TestAccProjectAccessTokenDataSource tests reading a project access token with
@@ -51,9 +57,9 @@ func (s *AccSuite) TestAccProjectAccessTokenDataSource() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -69,3 +75,127 @@ func (s *AccSuite) TestAccProjectAccessTokenDataSource() {
 		},
 	})
 }
+
+// TestAccProjectAccessTokenDataSourceScope tests looking up a project access
+// token by scope with `rollbar_project_access_token` data source.
+func (s *AccSuite) TestAccProjectAccessTokenDataSourceScope() {
+	rn := "data.rollbar_project_access_token.test"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "test" {
+		  name         = "%s"
+		}
+
+		resource "rollbar_project_access_token" "test" {
+			name = "test-token"
+			project_id = rollbar_project.test.id
+			scopes = ["read"]
+		}
+
+		data "rollbar_project_access_token" "test" {
+			project_id = rollbar_project.test.id
+			scope = "read"
+			depends_on = [rollbar_project_access_token.test]
+		}
+	`
+	config := fmt.Sprintf(tmpl, s.randName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttrPair(rn, "access_token", "rollbar_project_access_token.test", "access_token"),
+					resource.TestCheckResourceAttr(rn, "name", "test-token"),
+				),
+			},
+		},
+	})
+}
+
+// TestFindProjectAccessToken tests selecting a project access token by name
+// and scope.
+func TestFindProjectAccessToken(t *testing.T) {
+	tokens := []client.ProjectAccessToken{
+		{Name: "read", AccessToken: "a", Scopes: []client.Scope{client.ScopeRead}, Status: client.StatusEnabled},
+		{Name: "server", AccessToken: "b", Scopes: []client.Scope{client.ScopePostServerItem}, Status: client.StatusEnabled},
+		{Name: "old-server", AccessToken: "c", Scopes: []client.Scope{client.ScopePostServerItem}, Status: client.StatusDisabled},
+		{Name: "client", AccessToken: "d", Scopes: []client.Scope{client.ScopePostClientItem}, Status: client.StatusEnabled},
+		{Name: "client-2", AccessToken: "e", Scopes: []client.Scope{client.ScopePostClientItem}, Status: client.StatusEnabled},
+	}
+
+	found, err := findProjectAccessToken(tokens, "read", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "a", found.AccessToken)
+	_, err = findProjectAccessToken(tokens, "nonexistent", "")
+	assert.NotNil(t, err)
+
+	// Disabled tokens are ignored
+	found, err = findProjectAccessToken(tokens, "", client.ScopePostServerItem)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", found.AccessToken)
+	_, err = findProjectAccessToken(tokens, "", client.ScopeWrite)
+	assert.NotNil(t, err)
+
+	// Ambiguous unless a name is given
+	_, err = findProjectAccessToken(tokens, "", client.ScopePostClientItem)
+	assert.NotNil(t, err)
+	found, err = findProjectAccessToken(tokens, "client-2", client.ScopePostClientItem)
+	assert.Nil(t, err)
+	assert.Equal(t, "e", found.AccessToken)
+}
+
+// TestOfflineProjectAccessTokenDataSourceByValue tests identifying a project
+// access token from its value.
+func TestOfflineProjectAccessTokenDataSourceByValue(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := dataSourceProjectAccessToken()
+	ctx := context.Background()
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+	pat, err := c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+		ProjectID: p.ID,
+		Name:      "found-elsewhere",
+		Scopes:    []client.Scope{client.ScopeRead},
+		Status:    client.StatusEnabled,
+	})
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"token": pat.AccessToken,
+	})
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, p.ID, d.Get("project_id"))
+	assert.Equal(t, "found-elsewhere", d.Get("name"))
+	assert.Equal(t, []interface{}{"read"}, d.Get("scopes"))
+	assert.Equal(t, "enabled", d.Get("status"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"token": "unknownToken",
+	})
+	diags := r.ReadContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "could not find access token")
+
+	// With project_id, only that project is searched
+	other, err := c.CreateProject("other-project")
+	require.NoError(t, err)
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"project_id": p.ID,
+		"token":      pat.AccessToken,
+	})
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, "found-elsewhere", d.Get("name"))
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"project_id": other.ID,
+		"token":      pat.AccessToken,
+	})
+	diags = r.ReadContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Equal(t, fmt.Sprintf("could not find access token %q in project %d", client.RedactToken(pat.AccessToken), other.ID), diags[0].Summary)
+}
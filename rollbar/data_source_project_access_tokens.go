@@ -63,6 +63,7 @@ func dataSourceProjectAccessTokens() *schema.Resource {
 							Description: "API token",
 							Type:        schema.TypeString,
 							Computed:    true,
+							Sensitive:   true,
 						},
 						"project_id": {
 							Description: "ID of the project that owns the token",
@@ -133,10 +134,13 @@ func dataSourceProjectAccessTokensRead(ctx context.Context, d *schema.ResourceDa
 		Logger()
 	l.Debug().Msg("Reading project access token data from Rollbar")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_project_access_tokens", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	tokens, err := c.ListProjectAccessTokens(projectID)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	var filtered []client.ProjectAccessToken
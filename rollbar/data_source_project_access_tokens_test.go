@@ -24,7 +24,7 @@ package rollbar
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 // TestAccProjectAccessTokensDataSourceNoTokensNoPrefix tests reading project
@@ -45,9 +45,9 @@ func (s *AccSuite) TestAccProjectAccessTokensDataSourceNoTokensNoPrefix() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -94,9 +94,9 @@ func (s *AccSuite) TestAccProjectAccessTokensDataSourceTwoTokensNoPrefix() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -129,9 +129,9 @@ func (s *AccSuite) TestAccProjectAccessTokensDataSourceNoTokensWithPrefix() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -179,9 +179,9 @@ func (s *AccSuite) TestAccProjectAccessTokensDataSourceWithTokensWithPrefix() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -24,7 +24,7 @@ package rollbar
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/rs/zerolog/log"
 	"regexp"
 )
@@ -35,9 +35,9 @@ func (s *AccSuite) TestAccProjectDataSource() {
 	rn := "data.rollbar_project.test"
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				PreConfig: func() {
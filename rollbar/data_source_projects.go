@@ -26,7 +26,6 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"strconv"
 	"time"
@@ -82,11 +81,14 @@ func dataSourceProjects() *schema.Resource {
 func dataSourceProjectsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Debug().Msg("Reading project list from API")
 	var diags diag.Diagnostics
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_projects", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 
 	projects, err := c.ListProjects()
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	mustSet(d, "projects", projects)
 
@@ -24,8 +24,8 @@ package rollbar
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"strconv"
 )
@@ -36,9 +36,9 @@ func (s *AccSuite) TestAccProjectsDataSource() {
 	rn := "data.rollbar_projects.all"
 
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: s.configDataSourceProjects(),
@@ -57,7 +57,7 @@ func dataSourceTeam() *schema.Resource {
 			},
 
 			"access_level": {
-				Description: "The team's access level",
+				Description: fmt.Sprintf(`The team's access level.  One of %s, or "everyone" or "owner" for system teams.`, quotedList(client.TeamAccessLevels, "or")),
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
@@ -69,7 +69,10 @@ func dataSourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface
 	var team client.Team
 	var l zerolog.Logger
 	teamID, ok := d.GetOk("team_id")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_team", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	if ok {
 		l = log.With().
 			Int("id", teamID.(int)).
@@ -92,12 +95,12 @@ func dataSourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface
 
 		teams, err := c.ListTeams()
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 
 		t, err := findTeamByName(teams, name.(string))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		team = t
 	}
@@ -24,7 +24,7 @@ package rollbar
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/rs/zerolog/log"
 	"regexp"
 )
@@ -34,9 +34,9 @@ func (s *AccSuite) TestAccTeamDataSource() {
 	rn_id := "data.rollbar_team.test_id"
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				PreConfig: func() {
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+)
+
+// diagFromErr converts an error from the Rollbar API client into diagnostics,
+// like diag.FromErr, but with a remediation hint.  If the message of a Rollbar
+// ErrorResult names one of `attrs`, e.g. "Invalid or missing project id" names
+// "project_id", the diagnostic's AttributePath points at that attribute so
+// Terraform can highlight the offending line of configuration.
+func diagFromErr(err error, attrs ...string) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	d := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  err.Error(),
+	}
+	var er *client.ErrorResult
+	var mt *missingTokenError
+	var ms *missingScopeError
+	var ue *url.Error
+	switch {
+	case errors.As(err, &ms):
+		d.Summary = fmt.Sprintf("Rollbar access token lacks %s scope", ms.scope)
+		d.Detail = ms.Error() + "."
+	case errors.As(err, &mt):
+		d.Summary = "Missing Rollbar access token"
+		d.Detail = mt.Error() + "."
+	case errors.Is(err, client.ErrUnauthorized):
+		d.Summary = "Rollbar API request was not authorized"
+		key := schemaKeyToken
+		var ua *client.UnauthorizedError
+		if errors.As(err, &ua) && ua.TokenName != "" {
+			key = ua.TokenName
+		}
+		kind := "an account"
+		if key == projectKeyToken {
+			kind = "a project"
+		}
+		d.Detail = fmt.Sprintf("Check that the provider argument %s (or environment variable %s) is %s access token with the required scopes.", key, tokenEnvVars[key], kind)
+	case errors.Is(err, client.ErrNotFound):
+		d.Summary = "Rollbar object not found"
+		d.Detail = "The object may have been deleted outside of Terraform, or the token may not have access to it."
+	case errors.Is(err, context.Canceled):
+		d.Summary = "Rollbar API request interrupted"
+		if errors.As(err, &ue) {
+			d.Detail = fmt.Sprintf("%s %s was abandoned when Terraform was interrupted. ", strings.ToUpper(ue.Op), client.RedactURLPath(ue.URL))
+		}
+		d.Detail += "The change it was making may or may not have been applied; run terraform apply again to reconcile."
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &ue) && ue.Timeout():
+		d.Summary = "Rollbar API request timed out"
+		if errors.As(err, &ue) {
+			d.Detail = fmt.Sprintf("%s %s did not complete in time. ", strings.ToUpper(ue.Op), client.RedactURLPath(ue.URL))
+		}
+		d.Detail += fmt.Sprintf("Increase the operation's limit in the resource's `timeouts` block, or provider argument %s.", schemaKeyRequestTimeout)
+	case errors.As(err, &er):
+		d.Summary = fmt.Sprintf("Rollbar API error: %s", er.Message)
+		var detail []string
+		if er.Method != "" {
+			detail = append(detail, fmt.Sprintf("%s %s returned HTTP status %d.", er.Method, er.URL, er.StatusCode))
+		}
+		if er.RequestID != "" {
+			detail = append(detail, fmt.Sprintf("Request ID %s.", er.RequestID))
+		}
+		if er.RateLimitRemaining != "" {
+			detail = append(detail, fmt.Sprintf("Rate limit remaining %s.", er.RateLimitRemaining))
+		}
+		switch {
+		case attrFromMessage(er.Message, attrs) != "":
+			attr := attrFromMessage(er.Message, attrs)
+			d.AttributePath = cty.GetAttrPath(attr)
+			detail = append(detail, fmt.Sprintf("The Rollbar API rejected the value of %q.", attr))
+		default:
+			if hint := remediationHint(er.StatusCode); hint != "" {
+				detail = append(detail, hint)
+			}
+		}
+		d.Detail = strings.Join(detail, " ")
+	}
+	return diag.Diagnostics{d}
+}
+
+// withPartialState returns `diags`, from a create or update that failed part
+// way, plus the diagnostics of reading the resource back with `read`.  The
+// resource's ID must already be set, so that the changes made by the steps that
+// succeeded are recorded in state, rather than orphaned or taken for the whole
+// planned change.
+func withPartialState(ctx context.Context, d *schema.ResourceData, m interface{}, read schema.ReadContextFunc, diags diag.Diagnostics) diag.Diagnostics {
+	return append(diags, read(ctx, d, m)...)
+}
+
+// remediationHint suggests how to fix a Rollbar API error with HTTP status
+// `status`, or returns "" if there is no useful suggestion.
+func remediationHint(status int) string {
+	switch {
+	case status == http.StatusForbidden:
+		return fmt.Sprintf("The token lacks a required scope. Managing projects, teams, users and access tokens needs an account access token with `write` scope, set by provider argument %s; notifications, people and items need a project access token with `write` scope, set by %s.", schemaKeyToken, projectKeyToken)
+	case status == http.StatusTooManyRequests:
+		return fmt.Sprintf("The Rollbar API rate limit was exceeded. Reduce Terraform's -parallelism, or set provider argument %s.", schemaKeyMaxConcurrentRequests)
+	case status >= http.StatusInternalServerError:
+		return "The Rollbar API may be temporarily unavailable. Try again later."
+	}
+	return ""
+}
+
+// attrFromMessage returns the first of `attrs` that is named by `msg`, or "" if
+// none are.  An attribute is also matched with its underscores replaced by
+// spaces, and in the singular, e.g. "team_ids" matches "team id".  Only whole
+// words match, so "name" is not named by "Invalid filename".
+func attrFromMessage(msg string, attrs []string) string {
+	words := strings.FieldsFunc(strings.ToLower(msg), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	padded := " " + strings.Join(words, " ") + " "
+	for _, attr := range attrs {
+		spaced := strings.ReplaceAll(attr, "_", " ")
+		for _, s := range []string{attr, spaced, strings.TrimSuffix(spaced, "s")} {
+			if strings.Contains(padded, " "+s+" ") {
+				return attr
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiagFromErr tests converting API client errors into diagnostics.
+func TestDiagFromErr(t *testing.T) {
+	assert.Nil(t, diagFromErr(nil))
+
+	diags := diagFromErr(&client.ErrorResult{Err: 1, Message: "Invalid or missing team id"}, "name", "team_ids")
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Rollbar API error: Invalid or missing team id", diags[0].Summary)
+	assert.Equal(t, cty.GetAttrPath("team_ids"), diags[0].AttributePath)
+
+	diags = diagFromErr(&client.ErrorResult{Err: 1, Message: "Something went wrong"}, "name")
+	assert.Len(t, diags, 1)
+	assert.Nil(t, diags[0].AttributePath)
+
+	// Attributes are matched by whole words only
+	diags = diagFromErr(&client.ErrorResult{Err: 1, Message: "Invalid filename; project renamed"}, "name")
+	assert.Len(t, diags, 1)
+	assert.Nil(t, diags[0].AttributePath)
+	diags = diagFromErr(&client.ErrorResult{Err: 1, Message: "Invalid name."}, "name")
+	assert.Equal(t, cty.GetAttrPath("name"), diags[0].AttributePath)
+
+	diags = diagFromErr(&client.ErrorResult{
+		Err:                1,
+		Message:            "access token has insufficient scope",
+		Method:             "POST",
+		URL:                "/api/1/projects",
+		StatusCode:         403,
+		RequestID:          "0123456789abcdef",
+		RateLimitRemaining: "42",
+	}, "name")
+	assert.Len(t, diags, 1)
+	assert.Nil(t, diags[0].AttributePath)
+	assert.Contains(t, diags[0].Detail, "POST /api/1/projects returned HTTP status 403.")
+	assert.Contains(t, diags[0].Detail, "Request ID 0123456789abcdef.")
+	assert.Contains(t, diags[0].Detail, "Rate limit remaining 42.")
+	assert.Contains(t, diags[0].Detail, "`write` scope")
+
+	diags = diagFromErr(&client.ErrorResult{Err: 1, Message: "Rate limit exceeded", StatusCode: 429})
+	assert.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail, schemaKeyMaxConcurrentRequests)
+
+	diags = diagFromErr(client.ErrUnauthorized)
+	assert.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail, schemaKeyToken)
+
+	diags = diagFromErr(&client.UnauthorizedError{TokenName: projectKeyToken})
+	assert.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail, "provider argument project_api_key (or environment variable ROLLBAR_PROJECT_API_KEY) is a project access token")
+
+	diags = diagFromErr(client.ErrNotFound)
+	assert.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "not found")
+
+	diags = diagFromErr(fmt.Errorf("name cannot be blank"), "name")
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "name cannot be blank", diags[0].Summary)
+}
+
+// TestWithPartialState tests reading back a resource after a failed create.
+func TestWithPartialState(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]interface{}{"name": "foo"})
+	d.SetId("123")
+	read := func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+		mustSet(d, "name", "bar")
+		return diag.Diagnostics{{Severity: diag.Warning, Summary: "read"}}
+	}
+	diags := withPartialState(context.Background(), d, nil, read, diagFromErr(fmt.Errorf("create failed")))
+	assert.Len(t, diags, 2)
+	assert.Equal(t, "create failed", diags[0].Summary)
+	assert.Equal(t, "read", diags[1].Summary)
+	assert.Equal(t, "bar", d.Get("name"))
+	assert.Equal(t, "123", d.Id())
+}
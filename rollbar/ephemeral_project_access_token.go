@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// projectAccessTokenEphemeralResource is an ephemeral resource that reads the
+// value of a Rollbar project access token at apply time, without it ever being
+// stored in plan or state.
+type projectAccessTokenEphemeralResource struct {
+	client *client.RollbarAPIClient
+}
+
+// projectAccessTokenEphemeralModel describes the ephemeral resource data.
+type projectAccessTokenEphemeralModel struct {
+	ProjectID   types.Int64  `tfsdk:"project_id"`
+	Name        types.String `tfsdk:"name"`
+	AccessToken types.String `tfsdk:"access_token"`
+	Scopes      []string     `tfsdk:"scopes"`
+	Status      types.String `tfsdk:"status"`
+}
+
+func newProjectAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &projectAccessTokenEphemeralResource{}
+}
+
+func (r *projectAccessTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_access_token"
+}
+
+func (r *projectAccessTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a Rollbar project access token without storing its value in state",
+		Attributes: map[string]schema.Attribute{
+			// Required fields
+			"project_id": schema.Int64Attribute{
+				Description: "ID of a Rollbar project",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the token",
+				Required:    true,
+			},
+
+			// Computed fields
+			"access_token": schema.StringAttribute{
+				Description: "API token",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "Project access scopes for the token",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the token",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure implements ephemeral.EphemeralResourceWithConfigure.
+func (r *projectAccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return // Provider not yet configured
+	}
+	clients, ok := req.ProviderData.(map[string]*client.RollbarAPIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("got %T", req.ProviderData))
+		return
+	}
+	c, err := clientFor(ctx, clients, "rollbar_project_access_token", "read")
+	if err != nil {
+		resp.Diagnostics.AddError("Missing Rollbar access token", err.Error())
+		return
+	}
+	r.client = c
+}
+
+func (r *projectAccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data projectAccessTokenEphemeralModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	projectID := int(data.ProjectID.ValueInt64())
+	name := data.Name.ValueString()
+	l := log.With().
+		Int("project_id", projectID).
+		Str("name", name).
+		Logger()
+	l.Debug().Msg("Opening ephemeral project access token")
+
+	pat, err := r.client.WithContext(ctx).ReadProjectAccessTokenByName(projectID, name)
+	if err == client.ErrNotFound {
+		resp.Diagnostics.AddError(
+			"Project access token not found",
+			fmt.Sprintf(`could not find access token with name matching "%s"`, name),
+		)
+		return
+	}
+	if err != nil {
+		l.Err(err).Send()
+		resp.Diagnostics.AddError("Error reading project access token", err.Error())
+		return
+	}
+
+	data.AccessToken = types.StringValue(pat.AccessToken)
+	data.Status = types.StringValue(string(pat.Status))
+	data.Scopes = make([]string, len(pat.Scopes))
+	for i, s := range pat.Scopes {
+		data.Scopes[i] = string(s)
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	l.Debug().Msg("Successfully opened ephemeral project access token")
+}
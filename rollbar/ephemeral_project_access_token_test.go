@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frameworkObject returns a value of object type `typ` holding `values`, with
+// every other attribute null.
+func frameworkObject(typ tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attrs := make(map[string]tftypes.Value)
+	for name, t := range typ.AttributeTypes {
+		attrs[name] = tftypes.NewValue(t, nil)
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		}
+	}
+	return tftypes.NewValue(typ, attrs)
+}
+
+// TestOfflineProjectAccessTokenEphemeralResource tests opening the ephemeral
+// project access token through the framework provider.
+func TestOfflineProjectAccessTokenEphemeralResource(t *testing.T) {
+	f := newFakeAPI(t)
+	ctx := context.Background()
+
+	// Configure the framework provider against the fake API
+	p := NewFrameworkProvider()
+	var ps provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &ps)
+	require.False(t, ps.Diagnostics.HasError(), "%v", ps.Diagnostics)
+	pt := ps.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	var pr provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{
+		Schema: ps.Schema,
+		Raw: frameworkObject(pt, map[string]tftypes.Value{
+			schemaKeyToken:   tftypes.NewValue(tftypes.String, "fakeTokenString"),
+			projectKeyToken:  tftypes.NewValue(tftypes.String, "fakeTokenString"),
+			schemaKeyBaseURL: tftypes.NewValue(tftypes.String, f.URL),
+		}),
+	}}, &pr)
+	require.False(t, pr.Diagnostics.HasError(), "%v", pr.Diagnostics)
+
+	clients := pr.EphemeralResourceData.(map[string]*client.RollbarAPIClient)
+	c := clients[schemaKeyToken]
+	proj, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+	pat, err := c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+		ProjectID: proj.ID,
+		Name:      "offline-token",
+		Scopes:    []client.Scope{client.ScopeRead},
+		Status:    client.StatusEnabled,
+	})
+	require.NoError(t, err)
+
+	r := newProjectAccessTokenEphemeralResource()
+	var cr ephemeral.ConfigureResponse
+	r.(ephemeral.EphemeralResourceWithConfigure).Configure(ctx, ephemeral.ConfigureRequest{ProviderData: pr.EphemeralResourceData}, &cr)
+	require.False(t, cr.Diagnostics.HasError(), "%v", cr.Diagnostics)
+	var rs ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &rs)
+	rt := rs.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	open := func(name string) (projectAccessTokenEphemeralModel, ephemeral.OpenResponse) {
+		req := ephemeral.OpenRequest{Config: tfsdk.Config{
+			Schema: rs.Schema,
+			Raw: frameworkObject(rt, map[string]tftypes.Value{
+				"project_id": tftypes.NewValue(tftypes.Number, proj.ID),
+				"name":       tftypes.NewValue(tftypes.String, name),
+			}),
+		}}
+		resp := ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{
+			Schema: rs.Schema,
+			Raw:    tftypes.NewValue(rt, nil),
+		}}
+		r.Open(ctx, req, &resp)
+		var data projectAccessTokenEphemeralModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.Result.Get(ctx, &data)...)
+		}
+		return data, resp
+	}
+
+	data, resp := open("offline-token")
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	assert.Equal(t, pat.AccessToken, data.AccessToken.ValueString())
+	assert.Equal(t, "enabled", data.Status.ValueString())
+	assert.Equal(t, []string{"read"}, data.Scopes)
+
+	// A token of another name is not found
+	_, resp = open("missing-token")
+	require.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "Project access token not found", resp.Diagnostics[0].Summary())
+	assert.Contains(t, resp.Diagnostics[0].Detail(), `"missing-token"`)
+}
@@ -0,0 +1,823 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rollbar/terraform-provider-rollbar/client"
+)
+
+/*
+ * Fake Rollbar API
+ *
+ * fakeAPI is an in-memory stand-in for the parts of the Rollbar API used to
+ * manage projects, project access tokens, teams, team members, notification
+ * rules, people and items.  Pointing the provider's
+ * api_url at it lets resource CRUD flows run end to end without credentials or
+ * network access.
+ */
+
+// fakeAccountID is the account to which every fake object belongs.
+const fakeAccountID = 317418
+
+// fakeAPI is an in-memory fake of the Rollbar API.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	nextID       int
+	projects     map[int]client.Project
+	tokens       map[int]map[string]client.ProjectAccessToken // By project ID, then token value
+	teams        map[int]client.Team
+	teamProjects map[int]map[int]bool // By team ID, then project ID
+	users        map[int]client.User
+	teamUsers    map[int]map[int]bool // By team ID, then user ID
+	invitations  map[int]client.Invitation
+	people       map[int]client.Person
+	items        map[int]client.Item
+	occurrences  map[int][]client.Occurrence      // By item ID, most recent first
+	rules        map[string][]client.Notification // By channel
+	requests     int                              // Number of requests served
+
+	// deletions counts, by person ID, the reads of each person still served
+	// before their data deletion completes.
+	deletions map[int]int
+
+	// fault, if set, returns the HTTP status with which to fail a request, or
+	// zero to serve it.
+	fault func(r *http.Request) int
+}
+
+// fakeRoute is a handler for requests matching a method and path pattern.
+type fakeRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(f *fakeAPI, r *http.Request, args []string) (int, interface{})
+}
+
+// fakeRoutes are the API endpoints served by fakeAPI.  Capture groups in each
+// pattern are passed to its handler as args.
+var fakeRoutes = []fakeRoute{
+	{"GET", regexp.MustCompile(`^/api/1/projects$`), (*fakeAPI).listProjects},
+	{"POST", regexp.MustCompile(`^/api/1/projects$`), (*fakeAPI).createProject},
+	{"GET", regexp.MustCompile(`^/api/1/project/(\d+)$`), (*fakeAPI).readProject},
+	{"PATCH", regexp.MustCompile(`^/api/1/project/(\d+)$`), (*fakeAPI).updateProject},
+	{"DELETE", regexp.MustCompile(`^/api/1/project/(\d+)$`), (*fakeAPI).deleteProject},
+	{"GET", regexp.MustCompile(`^/api/1/project/(\d+)/access_tokens$`), (*fakeAPI).listTokens},
+	{"POST", regexp.MustCompile(`^/api/1/project/(\d+)/access_tokens$`), (*fakeAPI).createToken},
+	{"GET", regexp.MustCompile(`^/api/1/project/(\d+)/access_token/(\w+)$`), (*fakeAPI).readToken},
+	{"PATCH", regexp.MustCompile(`^/api/1/project/(\d+)/access_token/(\w+)$`), (*fakeAPI).updateToken},
+	{"DELETE", regexp.MustCompile(`^/api/1/project/(\d+)/access_token/(\w+)$`), (*fakeAPI).deleteToken},
+	{"GET", regexp.MustCompile(`^/api/1/teams$`), (*fakeAPI).listTeams},
+	{"POST", regexp.MustCompile(`^/api/1/teams$`), (*fakeAPI).createTeam},
+	{"GET", regexp.MustCompile(`^/api/1/team/(\d+)$`), (*fakeAPI).readTeam},
+	{"PATCH", regexp.MustCompile(`^/api/1/team/(\d+)$`), (*fakeAPI).updateTeam},
+	{"DELETE", regexp.MustCompile(`^/api/1/team/(\d+)$`), (*fakeAPI).deleteTeam},
+	{"GET", regexp.MustCompile(`^/api/1/team/(\d+)/projects$`), (*fakeAPI).listTeamProjects},
+	{"PUT", regexp.MustCompile(`^/api/1/team/(\d+)/project/(\d+)$`), (*fakeAPI).assignTeamProject},
+	{"DELETE", regexp.MustCompile(`^/api/1/team/(\d+)/project/(\d+)$`), (*fakeAPI).removeTeamProject},
+	{"GET", regexp.MustCompile(`^/api/1/users$`), (*fakeAPI).listUsers},
+	{"GET", regexp.MustCompile(`^/api/1/team/(\d+)/users$`), (*fakeAPI).listTeamUsers},
+	{"GET", regexp.MustCompile(`^/api/1/team/(\d+)/user/(\d+)$`), (*fakeAPI).readTeamUser},
+	{"PUT", regexp.MustCompile(`^/api/1/team/(\d+)/user/(\d+)$`), (*fakeAPI).assignTeamUser},
+	{"DELETE", regexp.MustCompile(`^/api/1/team/(\d+)/user/(\d+)$`), (*fakeAPI).removeTeamUser},
+	{"GET", regexp.MustCompile(`^/api/1/user/(\d+)/teams$`), (*fakeAPI).listUserTeams},
+	{"GET", regexp.MustCompile(`^/api/1/team/(\d+)/invites$`), (*fakeAPI).listInvitations},
+	{"POST", regexp.MustCompile(`^/api/1/team/(\d+)/invites$`), (*fakeAPI).createInvitation},
+	{"DELETE", regexp.MustCompile(`^/api/1/invite/(\d+)$`), (*fakeAPI).cancelInvitation},
+	{"GET", regexp.MustCompile(`^/api/1/notifications/(\w+)/rules$`), (*fakeAPI).listRules},
+	{"POST", regexp.MustCompile(`^/api/1/notifications/(\w+)/rules$`), (*fakeAPI).createRules},
+	{"GET", regexp.MustCompile(`^/api/1/notifications/(\w+)/rule/(\d+)$`), (*fakeAPI).readRule},
+	{"PUT", regexp.MustCompile(`^/api/1/notifications/(\w+)/rule/(\d+)$`), (*fakeAPI).updateRule},
+	{"DELETE", regexp.MustCompile(`^/api/1/notifications/(\w+)/rule/(\d+)$`), (*fakeAPI).deleteRule},
+	{"GET", regexp.MustCompile(`^/api/1/item_by_counter/(\d+)$`), (*fakeAPI).readItemByCounter},
+	{"GET", regexp.MustCompile(`^/api/1/item/(\d+)/instances$`), (*fakeAPI).listOccurrences},
+	{"GET", regexp.MustCompile(`^/api/1/people$`), (*fakeAPI).listPeople},
+	{"GET", regexp.MustCompile(`^/api/1/person/(\d+)$`), (*fakeAPI).readPerson},
+	{"DELETE", regexp.MustCompile(`^/api/1/person/(\d+)$`), (*fakeAPI).deletePerson},
+}
+
+// Tokens the fake API rejects, or accepts only for reading.  It accepts any
+// other token.
+const (
+	fakeInvalidToken  = "invalidToken"
+	fakeNoScopeToken  = "noScopeToken"
+	fakeReadOnlyToken = "readOnlyToken"
+)
+
+// newFakeAPI starts a fake Rollbar API, which is shut down when the test
+// completes.  The account has only the system teams "Everyone" and "Owners",
+// and one registered user, "registered@example.com".
+func newFakeAPI(t *testing.T) *fakeAPI {
+	f := &fakeAPI{
+		nextID:   1000,
+		projects: make(map[int]client.Project),
+		tokens:   make(map[int]map[string]client.ProjectAccessToken),
+		teams: map[int]client.Team{
+			1: {ID: 1, AccountID: fakeAccountID, Name: "Everyone", AccessLevel: "everyone"},
+			2: {ID: 2, AccountID: fakeAccountID, Name: "Owners", AccessLevel: "owner"},
+		},
+		teamProjects: make(map[int]map[int]bool),
+		users: map[int]client.User{
+			3: {ID: 3, Username: "registered", Email: "registered@example.com"},
+		},
+		teamUsers:   make(map[int]map[int]bool),
+		invitations: make(map[int]client.Invitation),
+		rules:       make(map[string][]client.Notification),
+		people:      make(map[int]client.Person),
+		items:       make(map[int]client.Item),
+		occurrences: make(map[int][]client.Occurrence),
+		deletions:   make(map[int]int),
+	}
+	f.Server = httptest.NewServer(f)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// ServeHTTP implements http.Handler.
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, result := http.StatusNotFound, interface{}(nil)
+	switch r.Header.Get("X-Rollbar-Access-Token") {
+	case fakeInvalidToken:
+		status = http.StatusUnauthorized
+	case fakeNoScopeToken:
+		status, result = http.StatusForbidden, "Token does not have read scope"
+	case fakeReadOnlyToken:
+		if r.Method != http.MethodGet {
+			status, result = http.StatusForbidden, "Token does not have write scope"
+		}
+	}
+	routes := fakeRoutes
+	if f.fault != nil {
+		if s := f.fault(r); s != 0 {
+			status, routes = s, nil
+		}
+	}
+	for _, route := range routes {
+		if status != http.StatusNotFound {
+			break
+		}
+		m := route.pattern.FindStringSubmatch(r.URL.Path)
+		if m == nil || route.method != r.Method {
+			continue
+		}
+		f.mu.Lock()
+		f.requests++
+		status, result = route.handle(f, r, m[1:])
+		f.mu.Unlock()
+		break
+	}
+	body := map[string]interface{}{"err": 0}
+	if status >= http.StatusBadRequest {
+		msg, _ := result.(string)
+		if msg == "" {
+			msg = http.StatusText(status)
+		}
+		body = map[string]interface{}{"err": 1, "message": msg}
+	} else if result != nil {
+		body["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// id allocates a new object ID.
+func (f *fakeAPI) id() int {
+	f.nextID++
+	return f.nextID
+}
+
+// decode decodes the JSON body of r into v, returning false if it is invalid.
+func decode(r *http.Request, v interface{}) bool {
+	return json.NewDecoder(r.Body).Decode(v) == nil
+}
+
+// atoi converts a path argument, already matched as digits, to an integer.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// newTokenValue returns a random access token value.
+func newTokenValue() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+/*
+ * Projects
+ */
+
+func (f *fakeAPI) listProjects(_ *http.Request, _ []string) (int, interface{}) {
+	projects := []client.Project{}
+	for _, p := range f.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+	return http.StatusOK, projects
+}
+
+func (f *fakeAPI) createProject(r *http.Request, _ []string) (int, interface{}) {
+	var body struct{ Name string }
+	if !decode(r, &body) || body.Name == "" {
+		return http.StatusBadRequest, "Invalid or missing project name"
+	}
+	for _, p := range f.projects {
+		if p.Name == body.Name {
+			return http.StatusBadRequest, "Project with this name already exists"
+		}
+	}
+	now := int(time.Now().Unix())
+	p := client.Project{
+		ID:           f.id(),
+		Name:         body.Name,
+		AccountID:    fakeAccountID,
+		DateCreated:  now,
+		DateModified: now,
+		Status:       "enabled",
+	}
+	f.projects[p.ID] = p
+
+	// Like the real API, create the default access tokens
+	f.tokens[p.ID] = make(map[string]client.ProjectAccessToken)
+	for _, s := range client.Scopes {
+		t := client.ProjectAccessToken{
+			Name:         string(s),
+			ProjectID:    p.ID,
+			AccessToken:  newTokenValue(),
+			Scopes:       []client.Scope{s},
+			Status:       client.StatusEnabled,
+			DateCreated:  now,
+			DateModified: now,
+		}
+		f.tokens[p.ID][t.AccessToken] = t
+	}
+	return http.StatusOK, p
+}
+
+func (f *fakeAPI) readProject(_ *http.Request, args []string) (int, interface{}) {
+	p, ok := f.projects[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, p
+}
+
+func (f *fakeAPI) updateProject(r *http.Request, args []string) (int, interface{}) {
+	p, ok := f.projects[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	var body struct{ Name string }
+	if !decode(r, &body) || body.Name == "" {
+		return http.StatusBadRequest, "Invalid or missing project name"
+	}
+	p.Name = body.Name
+	p.DateModified = int(time.Now().Unix())
+	f.projects[p.ID] = p
+	return http.StatusOK, p
+}
+
+func (f *fakeAPI) deleteProject(_ *http.Request, args []string) (int, interface{}) {
+	id := atoi(args[0])
+	if _, ok := f.projects[id]; !ok {
+		return http.StatusNotFound, nil
+	}
+	delete(f.projects, id)
+	delete(f.tokens, id)
+	for _, projects := range f.teamProjects {
+		delete(projects, id)
+	}
+	return http.StatusOK, nil
+}
+
+/*
+ * Project access tokens
+ */
+
+func (f *fakeAPI) listTokens(_ *http.Request, args []string) (int, interface{}) {
+	tokens, ok := f.tokens[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	list := []client.ProjectAccessToken{}
+	for _, t := range tokens {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return http.StatusOK, list
+}
+
+func (f *fakeAPI) createToken(r *http.Request, args []string) (int, interface{}) {
+	projectID := atoi(args[0])
+	tokens, ok := f.tokens[projectID]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	var body client.ProjectAccessTokenCreateArgs
+	if !decode(r, &body) || body.Name == "" {
+		return http.StatusBadRequest, "Invalid or missing token name"
+	}
+	for _, s := range body.Scopes {
+		if !s.Valid() {
+			return http.StatusBadRequest, fmt.Sprintf("Invalid scope: %s", s)
+		}
+	}
+	now := int(time.Now().Unix())
+	t := client.ProjectAccessToken{
+		Name:                 body.Name,
+		ProjectID:            projectID,
+		AccessToken:          newTokenValue(),
+		Scopes:               body.Scopes,
+		Status:               body.Status,
+		RateLimitWindowSize:  body.RateLimitWindowSize,
+		RateLimitWindowCount: body.RateLimitWindowCount,
+		DateCreated:          now,
+		DateModified:         now,
+	}
+	tokens[t.AccessToken] = t
+	return http.StatusOK, t
+}
+
+func (f *fakeAPI) readToken(_ *http.Request, args []string) (int, interface{}) {
+	t, ok := f.tokens[atoi(args[0])][args[1]]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, t
+}
+
+func (f *fakeAPI) updateToken(r *http.Request, args []string) (int, interface{}) {
+	t, ok := f.tokens[atoi(args[0])][args[1]]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	var body client.ProjectAccessTokenUpdateArgs
+	if !decode(r, &body) {
+		return http.StatusBadRequest, nil
+	}
+	t.RateLimitWindowSize = body.RateLimitWindowSize
+	t.RateLimitWindowCount = body.RateLimitWindowCount
+	if body.Status != "" {
+		t.Status = body.Status
+	}
+	t.DateModified = int(time.Now().Unix())
+	f.tokens[t.ProjectID][t.AccessToken] = t
+	return http.StatusOK, nil
+}
+
+func (f *fakeAPI) deleteToken(_ *http.Request, args []string) (int, interface{}) {
+	projectID := atoi(args[0])
+	if _, ok := f.tokens[projectID][args[1]]; !ok {
+		return http.StatusNotFound, nil
+	}
+	delete(f.tokens[projectID], args[1])
+	return http.StatusOK, nil
+}
+
+/*
+ * Teams
+ */
+
+func (f *fakeAPI) listTeams(_ *http.Request, _ []string) (int, interface{}) {
+	teams := []client.Team{}
+	for _, t := range f.teams {
+		teams = append(teams, t)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].ID < teams[j].ID })
+	return http.StatusOK, teams
+}
+
+func (f *fakeAPI) createTeam(r *http.Request, _ []string) (int, interface{}) {
+	var body struct {
+		Name        string
+		AccessLevel string `json:"access_level"`
+	}
+	if !decode(r, &body) || body.Name == "" {
+		return http.StatusBadRequest, "Invalid or missing team name"
+	}
+	for _, t := range f.teams {
+		if t.Name == body.Name {
+			return http.StatusBadRequest, "Team with this name already exists"
+		}
+	}
+	t := client.Team{ID: f.id(), AccountID: fakeAccountID, Name: body.Name, AccessLevel: body.AccessLevel}
+	f.teams[t.ID] = t
+	return http.StatusOK, t
+}
+
+func (f *fakeAPI) readTeam(_ *http.Request, args []string) (int, interface{}) {
+	t, ok := f.teams[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, t
+}
+
+func (f *fakeAPI) updateTeam(r *http.Request, args []string) (int, interface{}) {
+	t, ok := f.teams[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	var body struct {
+		Name        string
+		AccessLevel string `json:"access_level"`
+	}
+	if !decode(r, &body) || body.Name == "" {
+		return http.StatusBadRequest, "Invalid or missing team name"
+	}
+	t.Name = body.Name
+	t.AccessLevel = body.AccessLevel
+	f.teams[t.ID] = t
+	return http.StatusOK, t
+}
+
+func (f *fakeAPI) deleteTeam(_ *http.Request, args []string) (int, interface{}) {
+	id := atoi(args[0])
+	if _, ok := f.teams[id]; !ok {
+		return http.StatusNotFound, nil
+	}
+	delete(f.teams, id)
+	delete(f.teamProjects, id)
+	delete(f.teamUsers, id)
+	return http.StatusOK, nil
+}
+
+func (f *fakeAPI) listTeamProjects(r *http.Request, args []string) (int, interface{}) {
+	teamID := atoi(args[0])
+	if _, ok := f.teams[teamID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	type teamProject struct {
+		TeamID    int `json:"team_id"`
+		ProjectID int `json:"project_id"`
+	}
+	list := []teamProject{}
+	if firstPage(r) {
+		for projectID := range f.teamProjects[teamID] {
+			list = append(list, teamProject{TeamID: teamID, ProjectID: projectID})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].ProjectID < list[j].ProjectID })
+	}
+	return http.StatusOK, list
+}
+
+func (f *fakeAPI) assignTeamProject(_ *http.Request, args []string) (int, interface{}) {
+	teamID, projectID := atoi(args[0]), atoi(args[1])
+	_, teamOK := f.teams[teamID]
+	_, projectOK := f.projects[projectID]
+	if !teamOK || !projectOK {
+		return http.StatusNotFound, nil
+	}
+	if f.teamProjects[teamID] == nil {
+		f.teamProjects[teamID] = make(map[int]bool)
+	}
+	f.teamProjects[teamID][projectID] = true
+	return http.StatusOK, map[string]int{"team_id": teamID, "project_id": projectID}
+}
+
+func (f *fakeAPI) removeTeamProject(_ *http.Request, args []string) (int, interface{}) {
+	teamID, projectID := atoi(args[0]), atoi(args[1])
+	if !f.teamProjects[teamID][projectID] {
+		return http.StatusNotFound, nil
+	}
+	delete(f.teamProjects[teamID], projectID)
+	return http.StatusOK, nil
+}
+
+/*
+ * Users and invitations
+ */
+
+// firstPage reports whether r requests the first page of a paged list.  The
+// fake returns every result on the first page, and later pages are empty.
+func firstPage(r *http.Request) bool {
+	page := r.URL.Query().Get("page")
+	return page == "" || page == "1"
+}
+
+func (f *fakeAPI) listUsers(_ *http.Request, _ []string) (int, interface{}) {
+	users := []client.User{}
+	for _, u := range f.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return http.StatusOK, map[string]interface{}{"users": users}
+}
+
+func (f *fakeAPI) listTeamUsers(r *http.Request, args []string) (int, interface{}) {
+	teamID := atoi(args[0])
+	if _, ok := f.teams[teamID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	type teamUser struct {
+		TeamID int `json:"team_id"`
+		UserID int `json:"user_id"`
+	}
+	list := []teamUser{}
+	if firstPage(r) {
+		for userID := range f.teamUsers[teamID] {
+			list = append(list, teamUser{TeamID: teamID, UserID: userID})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+	}
+	return http.StatusOK, list
+}
+
+func (f *fakeAPI) readTeamUser(_ *http.Request, args []string) (int, interface{}) {
+	teamID, userID := atoi(args[0]), atoi(args[1])
+	if !f.teamUsers[teamID][userID] {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, map[string]int{"team_id": teamID, "user_id": userID}
+}
+
+func (f *fakeAPI) listUserTeams(_ *http.Request, args []string) (int, interface{}) {
+	userID := atoi(args[0])
+	if _, ok := f.users[userID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	teams := []client.Team{}
+	for teamID, users := range f.teamUsers {
+		if users[userID] {
+			teams = append(teams, f.teams[teamID])
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].ID < teams[j].ID })
+	return http.StatusOK, map[string]interface{}{"teams": teams}
+}
+
+func (f *fakeAPI) assignTeamUser(_ *http.Request, args []string) (int, interface{}) {
+	teamID, userID := atoi(args[0]), atoi(args[1])
+	_, teamOK := f.teams[teamID]
+	_, userOK := f.users[userID]
+	if !teamOK || !userOK {
+		return http.StatusNotFound, nil
+	}
+	if f.teamUsers[teamID] == nil {
+		f.teamUsers[teamID] = make(map[int]bool)
+	}
+	f.teamUsers[teamID][userID] = true
+	return http.StatusOK, map[string]int{"team_id": teamID, "user_id": userID}
+}
+
+func (f *fakeAPI) removeTeamUser(_ *http.Request, args []string) (int, interface{}) {
+	teamID, userID := atoi(args[0]), atoi(args[1])
+	if !f.teamUsers[teamID][userID] {
+		return http.StatusUnprocessableEntity, "User is not a member of this team"
+	}
+	delete(f.teamUsers[teamID], userID)
+	return http.StatusOK, nil
+}
+
+func (f *fakeAPI) listInvitations(r *http.Request, args []string) (int, interface{}) {
+	teamID := atoi(args[0])
+	if _, ok := f.teams[teamID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	list := []client.Invitation{}
+	if firstPage(r) {
+		for _, inv := range f.invitations {
+			if inv.TeamID == teamID {
+				list = append(list, inv)
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return http.StatusOK, list
+}
+
+func (f *fakeAPI) createInvitation(r *http.Request, args []string) (int, interface{}) {
+	teamID := atoi(args[0])
+	if _, ok := f.teams[teamID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	var body struct{ Email string }
+	if !decode(r, &body) || body.Email == "" {
+		return http.StatusBadRequest, "Invalid or missing email"
+	}
+	inv := client.Invitation{
+		ID:          f.id(),
+		TeamID:      teamID,
+		ToEmail:     body.Email,
+		Status:      "pending",
+		DateCreated: int(time.Now().Unix()),
+	}
+	f.invitations[inv.ID] = inv
+	return http.StatusOK, inv
+}
+
+func (f *fakeAPI) cancelInvitation(_ *http.Request, args []string) (int, interface{}) {
+	inv, ok := f.invitations[atoi(args[0])]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	if inv.Status != "pending" {
+		return http.StatusUnprocessableEntity, "Invite has already been canceled"
+	}
+	inv.Status = "canceled"
+	f.invitations[inv.ID] = inv
+	return http.StatusOK, nil
+}
+
+func (f *fakeAPI) listRules(_ *http.Request, args []string) (int, interface{}) {
+	list := append([]client.Notification{}, f.rules[args[0]]...)
+	return http.StatusOK, list
+}
+
+func (f *fakeAPI) createRules(r *http.Request, args []string) (int, interface{}) {
+	var body []client.Notification
+	if !decode(r, &body) || len(body) == 0 {
+		return http.StatusBadRequest, "Invalid or missing rules"
+	}
+	for i := range body {
+		body[i].ID = f.id()
+		body[i].Action = "send_" + args[0]
+	}
+	f.rules[args[0]] = append(f.rules[args[0]], body...)
+	return http.StatusOK, body
+}
+
+func (f *fakeAPI) readRule(_ *http.Request, args []string) (int, interface{}) {
+	id, _ := strconv.Atoi(args[1])
+	for _, n := range f.rules[args[0]] {
+		if n.ID == id {
+			return http.StatusOK, n
+		}
+	}
+	return http.StatusNotFound, nil
+}
+
+func (f *fakeAPI) updateRule(r *http.Request, args []string) (int, interface{}) {
+	id, _ := strconv.Atoi(args[1])
+	var body client.Notification
+	if !decode(r, &body) {
+		return http.StatusBadRequest, "Invalid rule"
+	}
+	for i, n := range f.rules[args[0]] {
+		if n.ID == id {
+			body.ID, body.Action = n.ID, n.Action
+			f.rules[args[0]][i] = body
+			return http.StatusOK, body
+		}
+	}
+	return http.StatusNotFound, nil
+}
+
+func (f *fakeAPI) deleteRule(_ *http.Request, args []string) (int, interface{}) {
+	id, _ := strconv.Atoi(args[1])
+	for i, n := range f.rules[args[0]] {
+		if n.ID == id {
+			f.rules[args[0]] = append(f.rules[args[0]][:i:i], f.rules[args[0]][i+1:]...)
+			return http.StatusOK, nil
+		}
+	}
+	return http.StatusNotFound, nil
+}
+
+/*
+ * People
+ */
+
+// fakePersonDeletionReads is the number of times a person can still be read
+// after their data deletion is submitted, as the real API deletes the data
+// asynchronously.
+const fakePersonDeletionReads = 1
+
+// fakePeoplePerPage is the number of people on each page served by the fake
+// API, small so that tests can page through a few.
+const fakePeoplePerPage = 2
+
+func (f *fakeAPI) listPeople(r *http.Request, _ []string) (int, interface{}) {
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		page = atoi(p)
+	}
+	all := []client.Person{}
+	for _, p := range f.people {
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	list := []client.Person{}
+	if start := (page - 1) * fakePeoplePerPage; page >= 1 && start < len(all) {
+		list = all[start:min(start+fakePeoplePerPage, len(all))]
+	}
+	return http.StatusOK, map[string]interface{}{"people": list}
+}
+
+func (f *fakeAPI) readPerson(_ *http.Request, args []string) (int, interface{}) {
+	id := atoi(args[0])
+	p, ok := f.people[id]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	if n, deleting := f.deletions[id]; deleting {
+		if n == 0 {
+			delete(f.people, id)
+			delete(f.deletions, id)
+			return http.StatusNotFound, nil
+		}
+		f.deletions[id] = n - 1
+	}
+	return http.StatusOK, p
+}
+
+func (f *fakeAPI) deletePerson(_ *http.Request, args []string) (int, interface{}) {
+	id := atoi(args[0])
+	if _, ok := f.people[id]; !ok {
+		return http.StatusNotFound, nil
+	}
+	if _, deleting := f.deletions[id]; !deleting {
+		f.deletions[id] = fakePersonDeletionReads
+	}
+	return http.StatusOK, nil
+}
+
+/*
+ * Items
+ */
+
+// readItemByCounter serves the item with a counter.  The real API redirects to
+// the item's own URL; the fake answers directly.
+func (f *fakeAPI) readItemByCounter(_ *http.Request, args []string) (int, interface{}) {
+	counter := atoi(args[0])
+	for _, item := range f.items {
+		if item.Counter == counter {
+			return http.StatusOK, item
+		}
+	}
+	return http.StatusNotFound, nil
+}
+
+// fakeOccurrencesPerPage is the number of occurrences on each page served by
+// the fake API, small so that tests can page through a few.
+const fakeOccurrencesPerPage = 2
+
+func (f *fakeAPI) listOccurrences(r *http.Request, args []string) (int, interface{}) {
+	itemID := atoi(args[0])
+	if _, ok := f.items[itemID]; !ok {
+		return http.StatusNotFound, nil
+	}
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		page = atoi(p)
+	}
+	list := []client.Occurrence{}
+	all := f.occurrences[itemID]
+	if start := (page - 1) * fakeOccurrencesPerPage; page >= 1 && start < len(all) {
+		list = all[start:min(start+fakeOccurrencesPerPage, len(all))]
+	}
+	return http.StatusOK, map[string]interface{}{"instances": list}
+}
+
+// teamMemberEmails returns the emails of a team's registered members and
+// pending invitations.
+func (f *fakeAPI) teamMemberEmails(teamID int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	emails := []string{}
+	for userID := range f.teamUsers[teamID] {
+		emails = append(emails, f.users[userID].Email)
+	}
+	for _, inv := range f.invitations {
+		if inv.TeamID == teamID && inv.Status == "pending" {
+			emails = append(emails, inv.ToEmail)
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// itemURLBase is the base URL of Rollbar's web interface.
+const itemURLBase = "https://rollbar.com"
+
+// itemURLFunction is a provider-defined function building the URL of a Rollbar
+// item in the web interface.
+type itemURLFunction struct{}
+
+func newItemURLFunction() function.Function {
+	return &itemURLFunction{}
+}
+
+func (f *itemURLFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "item_url"
+}
+
+func (f *itemURLFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build the URL of a Rollbar item",
+		Description: "Returns the URL of the item with the given counter in the Rollbar web interface, e.g. https://rollbar.com/my-account/my-project/items/42/",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "account_slug",
+				Description: "Slug of the account, as in the account's Rollbar URLs",
+			},
+			function.StringParameter{
+				Name:        "project_slug",
+				Description: "Slug of the project, usually its name",
+			},
+			function.Int64Parameter{
+				Name:        "counter",
+				Description: "The item's counter, its number within the project",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *itemURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var accountSlug, projectSlug string
+	var counter int64
+	resp.Error = req.Arguments.Get(ctx, &accountSlug, &projectSlug, &counter)
+	if resp.Error != nil {
+		return
+	}
+	if resp.Error = validateSlug(0, accountSlug); resp.Error != nil {
+		return
+	}
+	if resp.Error = validateSlug(1, projectSlug); resp.Error != nil {
+		return
+	}
+	if counter <= 0 {
+		resp.Error = function.NewArgumentFuncError(2, "counter must be a positive number")
+		return
+	}
+	u := fmt.Sprintf("%s/%s/%s/items/%d/", itemURLBase, url.PathEscape(accountSlug), url.PathEscape(projectSlug), counter)
+	resp.Error = resp.Result.Set(ctx, u)
+}
+
+// validateSlug checks the slug passed as the function argument at position
+// `pos` is usable in a URL path.
+func validateSlug(pos int64, slug string) *function.FuncError {
+	switch {
+	case strings.TrimSpace(slug) == "":
+		return function.NewArgumentFuncError(pos, "slug must not be empty")
+	case strings.Contains(slug, "/"):
+		return function.NewArgumentFuncError(pos, fmt.Sprintf("slug %q must not contain a slash", slug))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestItemURLFunction tests building item URLs with provider function
+// item_url.
+func TestItemURLFunction(t *testing.T) {
+	run := func(account, project string, counter int64) (string, *function.FuncError) {
+		req := function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{
+				types.StringValue(account),
+				types.StringValue(project),
+				types.Int64Value(counter),
+			}),
+		}
+		resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+		newItemURLFunction().Run(context.Background(), req, &resp)
+		return resp.Result.Value().(types.String).ValueString(), resp.Error
+	}
+
+	u, err := run("my-account", "my-project", 42)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://rollbar.com/my-account/my-project/items/42/", u)
+
+	u, err = run("my account", "my-project", 42)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://rollbar.com/my%20account/my-project/items/42/", u)
+
+	_, err = run("", "my-project", 42)
+	assert.Equal(t, function.NewArgumentFuncError(0, "slug must not be empty"), err)
+	_, err = run("my-account", "my/project", 42)
+	assert.Equal(t, function.NewArgumentFuncError(1, `slug "my/project" must not contain a slash`), err)
+	_, err = run("my-account", "my-project", 0)
+	assert.Equal(t, function.NewArgumentFuncError(2, "counter must be a positive number"), err)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+)
+
+// normalizeScopesFunction is a provider-defined function validating a list of
+// project access token scopes and returning it deduplicated and sorted.
+type normalizeScopesFunction struct{}
+
+func newNormalizeScopesFunction() function.Function {
+	return &normalizeScopesFunction{}
+}
+
+func (f *normalizeScopesFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_scopes"
+}
+
+func (f *normalizeScopesFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Validate and normalize project access token scopes",
+		Description: fmt.Sprintf("Returns the scopes deduplicated and sorted, failing if any is not %s.", quotedList(client.ScopeStrings(), "or")),
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "scopes",
+				Description: "Project access token scopes",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *normalizeScopesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var scopes []string
+	resp.Error = req.Arguments.Get(ctx, &scopes)
+	if resp.Error != nil {
+		return
+	}
+	normalized, err := normalizeScopes(scopes)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, normalized)
+}
+
+// normalizeScopes returns scopes deduplicated and sorted, or an error naming
+// the first scope that is not valid.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, s := range scopes {
+		if !client.Scope(s).Valid() {
+			return nil, fmt.Errorf("unknown scope %q, expected %s", s, quotedList(client.ScopeStrings(), "or"))
+		}
+		if !seen[s] {
+			seen[s] = true
+			normalized = append(normalized, s)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeScopesFunction tests validating and normalizing scopes with
+// provider function normalize_scopes.
+func TestNormalizeScopesFunction(t *testing.T) {
+	run := func(scopes ...string) ([]string, *function.FuncError) {
+		values := make([]attr.Value, len(scopes))
+		for i, s := range scopes {
+			values[i] = types.StringValue(s)
+		}
+		req := function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{
+				types.ListValueMust(types.StringType, values),
+			}),
+		}
+		resp := function.RunResponse{Result: function.NewResultData(types.ListUnknown(types.StringType))}
+		newNormalizeScopesFunction().Run(context.Background(), req, &resp)
+		var result []string
+		if resp.Error == nil {
+			resp.Result.Value().(types.List).ElementsAs(context.Background(), &result, false)
+		}
+		return result, resp.Error
+	}
+
+	scopes, err := run("write", "read", "write")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"read", "write"}, scopes)
+
+	scopes, err = run()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, scopes)
+
+	_, err = run("read", "admin")
+	assert.Equal(t, function.NewArgumentFuncError(0, `unknown scope "admin", expected "read", "write", "post_server_item", or "post_client_item"`), err)
+}
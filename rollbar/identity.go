@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+ * Resource identity
+ *
+ * Each importable resource has a resource identity, so that import blocks can
+ * name the resource by its attributes rather than an opaque import ID:
+ *
+ *	import {
+ *	  to       = rollbar_team_user.alice
+ *	  identity = { team_id = 123, email = "alice@example.com" }
+ *	}
+ *
+ * An identity is declared as a resourceIdentity listing its attributes in the
+ * order they appear in the resource's import ID, and applied to the resource
+ * with withIdentity().  Importing by identity builds the import ID and hands it
+ * to the resource's own importer, so the two ways of importing cannot drift
+ * apart.
+ */
+
+// identityAttr is an attribute of a resource identity.
+type identityAttr struct {
+	name        string           // Name of the identity attribute
+	attr        string           // Resource attribute holding the value, or "" for the resource ID
+	typ         schema.ValueType // schema.TypeInt or schema.TypeString
+	description string
+}
+
+// resourceIdentity describes the identity of a resource.
+type resourceIdentity struct {
+	attrs     []identityAttr
+	separator string // Separates attribute values in the import ID
+}
+
+// withIdentity adds identity `ri` to resource r: its identity schema, import
+// by identity, and recording the identity whenever r is created, read or
+// updated.
+func withIdentity(r *schema.Resource, ri resourceIdentity) *schema.Resource {
+	r.Identity = &schema.ResourceIdentity{
+		Version:    0,
+		SchemaFunc: ri.schema,
+	}
+	importer := r.Importer.StateContext
+	r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		if d.Id() == "" {
+			id, err := ri.importID(r, d)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(id)
+		}
+		return importer(ctx, d, m)
+	}
+	r.CreateContext = ri.recorded(r.CreateContext)
+	r.ReadContext = ri.recorded(r.ReadContext)
+	r.UpdateContext = ri.recorded(r.UpdateContext)
+	return r
+}
+
+// schema returns the identity schema.
+func (ri resourceIdentity) schema() map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema, len(ri.attrs))
+	for _, a := range ri.attrs {
+		s[a.name] = &schema.Schema{
+			Type:              a.typ,
+			RequiredForImport: true,
+			Description:       a.description,
+		}
+	}
+	return s
+}
+
+// importID validates the identity given to import resource r and builds the
+// import ID it stands for.
+func (ri resourceIdentity) importID(r *schema.Resource, d *schema.ResourceData) (string, error) {
+	identity, err := d.Identity()
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, len(ri.attrs))
+	for i, a := range ri.attrs {
+		v := identity.Get(a.name)
+		switch a.typ {
+		case schema.TypeInt:
+			if n, _ := v.(int); n <= 0 {
+				return "", fmt.Errorf("identity attribute %s must be a positive number", a.name)
+			}
+			values[i] = strconv.Itoa(v.(int))
+		default:
+			if s, _ := v.(string); strings.TrimSpace(s) == "" {
+				return "", fmt.Errorf("identity attribute %s must not be empty", a.name)
+			}
+			values[i] = v.(string)
+		}
+		if a.attr == "" {
+			continue
+		}
+		// Identity attributes are validated as the resource attributes
+		// holding them would be.
+		s := r.Schema[a.attr]
+		if s.ValidateFunc != nil {
+			_, errs := s.ValidateFunc(v, a.name)
+			if len(errs) > 0 {
+				return "", errs[0]
+			}
+		}
+		if s.ValidateDiagFunc != nil {
+			diags := s.ValidateDiagFunc(v, cty.GetAttrPath(a.name))
+			if diags.HasError() {
+				return "", fmt.Errorf("identity attribute %s: %s", a.name, diags[0].Summary)
+			}
+		}
+	}
+	return strings.Join(values, ri.separator), nil
+}
+
+// crudFunc is the signature shared by resource create, read, update and delete
+// functions.
+type crudFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// recorded wraps a create, read or update function to record the identity of
+// the resource after it succeeds.
+func (ri resourceIdentity) recorded(f crudFunc) crudFunc {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		diags := f(ctx, d, m)
+		if diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		identity, err := d.Identity()
+		if err != nil {
+			// E.g. resource data constructed without the identity schema
+			log.Debug().Err(err).Msg("Not recording resource identity")
+			return diags
+		}
+		for _, a := range ri.attrs {
+			var v interface{}
+			if a.attr != "" {
+				v = d.Get(a.attr)
+			} else if a.typ == schema.TypeInt {
+				v, _ = strconv.Atoi(d.Id())
+			} else {
+				v = d.Id()
+			}
+			if err := identity.Set(a.name, v); err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+		}
+		return diags
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResourceIdentity tests importing resources by identity.
+func TestResourceIdentity(t *testing.T) {
+	// Every importable resource has an identity
+	for name, r := range Provider().ResourcesMap {
+		if r.Importer != nil {
+			assert.NotNil(t, r.Identity, name)
+		}
+	}
+
+	ctx := context.Background()
+	r := resourceTeamUser()
+	d := schema.TestResourceDataWithIdentityRaw(t, r.Schema, r.Identity.SchemaMap(), map[string]string{
+		"team_id": "689493",
+		"email":   "jsmith@example.com",
+	})
+	result, err := r.Importer.StateContext(ctx, d, nil)
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "689493,jsmith@example.com", d.Id())
+	assert.Equal(t, 689493, d.Get("team_id"))
+	assert.Equal(t, "jsmith@example.com", d.Get("email"))
+
+	// Identity attributes are validated
+	d = schema.TestResourceDataWithIdentityRaw(t, r.Schema, r.Identity.SchemaMap(), map[string]string{
+		"team_id": "0",
+		"email":   "jsmith@example.com",
+	})
+	_, err = r.Importer.StateContext(ctx, d, nil)
+	assert.EqualError(t, err, "identity attribute team_id must be a positive number")
+	r = resourceUser()
+	d = schema.TestResourceDataWithIdentityRaw(t, r.Schema, r.Identity.SchemaMap(), map[string]string{
+		"email": " ",
+	})
+	_, err = r.Importer.StateContext(ctx, d, nil)
+	assert.EqualError(t, err, "identity attribute email must not be empty")
+}
+
+// TestOfflineResourceIdentity tests recording the identity of a resource when
+// it is created.
+func TestOfflineResourceIdentity(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceTeam()
+
+	d := offlineApply(t, r, r.Data(nil), m, map[string]interface{}{
+		"name": "identified-team",
+	})
+	identity, err := d.Identity()
+	require.NoError(t, err)
+	assert.Equal(t, d.Id(), strconv.Itoa(identity.Get("id").(int)))
+}
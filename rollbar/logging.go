@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Accepted values of provider arguments log_level and log_format.
+var (
+	logLevels  = []string{"trace", "debug", "info", "warn", "error", "off"}
+	logFormats = []string{"json", "console"}
+)
+
+// defaultLogLevel is the log level used when neither log_level nor Terraform's
+// TF_LOG is set.
+const defaultLogLevel = "warn"
+
+// ConfigureLogging sets the level and output format of the provider's logs,
+// which are written to stderr and so captured in Terraform's own log.  A blank
+// level follows TF_LOG_PROVIDER or TF_LOG, defaulting to "warn"; a blank format
+// means "json".  When TERRAFORM_PROVIDER_ROLLBAR_DEBUG=1, debug logging to file
+// has been configured instead, and is left untouched.
+func ConfigureLogging(level, format string) error {
+	if os.Getenv("TERRAFORM_PROVIDER_ROLLBAR_DEBUG") == "1" {
+		return nil
+	}
+	if level == "" {
+		level = tfLogLevel()
+	}
+	var lvl zerolog.Level
+	switch level {
+	case "off":
+		lvl = zerolog.Disabled
+	case "trace", "debug", "info", "warn", "error":
+		lvl, _ = zerolog.ParseLevel(level)
+	default:
+		return fmt.Errorf("log level must be %s", quotedList(logLevels, "or"))
+	}
+	var out zerolog.Logger
+	switch format {
+	case "", "json":
+		out = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	case "console":
+		out = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}).With().Timestamp().Logger()
+	default:
+		return fmt.Errorf("log format must be %s", quotedList(logFormats, "or"))
+	}
+	zerolog.SetGlobalLevel(lvl)
+	log.Logger = out
+	return nil
+}
+
+// tfLogLevel returns the log level corresponding to TF_LOG_PROVIDER, or if that
+// is not set TF_LOG, defaulting to defaultLogLevel.
+func tfLogLevel() string {
+	v := os.Getenv("TF_LOG_PROVIDER")
+	if v == "" {
+		v = os.Getenv("TF_LOG")
+	}
+	switch strings.ToUpper(v) {
+	case "TRACE", "JSON":
+		return "trace"
+	case "DEBUG":
+		return "debug"
+	case "INFO":
+		return "info"
+	case "WARN":
+		return "warn"
+	case "ERROR":
+		return "error"
+	case "OFF":
+		return "off"
+	default:
+		return defaultLogLevel
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigureLogging tests setting log level and format from provider
+// configuration and Terraform's TF_LOG.
+func TestConfigureLogging(t *testing.T) {
+	logger, level := log.Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	})
+	t.Setenv("TERRAFORM_PROVIDER_ROLLBAR_DEBUG", "")
+	t.Setenv("TF_LOG_PROVIDER", "")
+
+	t.Setenv("TF_LOG", "")
+	assert.Nil(t, ConfigureLogging("", ""))
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	t.Setenv("TF_LOG", "DEBUG")
+	assert.Nil(t, ConfigureLogging("", "console"))
+	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+
+	t.Setenv("TF_LOG_PROVIDER", "json")
+	assert.Nil(t, ConfigureLogging("", ""))
+	assert.Equal(t, zerolog.TraceLevel, zerolog.GlobalLevel())
+
+	// Explicit level overrides TF_LOG
+	assert.Nil(t, ConfigureLogging("off", "json"))
+	assert.Equal(t, zerolog.Disabled, zerolog.GlobalLevel())
+
+	assert.NotNil(t, ConfigureLogging("verbose", ""))
+	assert.NotNil(t, ConfigureLogging("info", "xml"))
+	assert.Equal(t, zerolog.Disabled, zerolog.GlobalLevel())
+
+	// Debug logging to file is left untouched
+	t.Setenv("TERRAFORM_PROVIDER_ROLLBAR_DEBUG", "1")
+	assert.Nil(t, ConfigureLogging("error", ""))
+	assert.Equal(t, zerolog.Disabled, zerolog.GlobalLevel())
+}
@@ -0,0 +1,577 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * Offline CRUD tests
+ *
+ * These tests drive the real resource CRUD functions against fakeAPI, so they
+ * run without ROLLBAR_API_KEY as part of `go test ./...`.  TestOfflineConfig
+ * also runs a configuration through Terraform itself, with the provider's
+ * api_url pointed at fakeAPI, when a terraform binary is available.
+ */
+
+// offlineMeta returns provider meta whose clients talk to f.
+func offlineMeta(f *fakeAPI) interface{} {
+	return newClients(f.URL, "fakeTokenString", "fakeTokenString", clientOptions{})
+}
+
+// offlineApply plans and applies resource r from its current state in d to
+// config, as Terraform would, returning the resulting resource data.
+func offlineApply(t *testing.T, r *schema.Resource, d *schema.ResourceData, m interface{}, config map[string]interface{}) *schema.ResourceData {
+	ctx := context.Background()
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	state, diags := r.Apply(ctx, d.State(), diff, m)
+	require.False(t, diags.HasError(), "%v", diags)
+	return r.Data(state)
+}
+
+// TestOfflineTeamCRUD tests creating, renaming and deleting a team.
+func TestOfflineTeamCRUD(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceTeam()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "offline-team",
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	require.NotEmpty(t, d.Id())
+	assert.Equal(t, "standard", d.Get("access_level"))
+	assert.Equal(t, fakeAccountID, d.Get("account_id"))
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"name": "offline-team-renamed",
+	})
+	assert.Equal(t, "offline-team-renamed", f.teams[mustGetID(d)].Name)
+
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Empty(t, d.Id(), "deleted team should be removed from state")
+}
+
+// TestOfflineTeamUserEmails tests managing the members of a team inline.
+func TestOfflineTeamUserEmails(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceTeam()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "offline-team",
+		"user_emails": []interface{}{"Registered@example.com", "invited@example.com"},
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	teamID := mustGetID(d)
+	assert.Equal(t, []string{"invited@example.com", "registered@example.com"}, f.teamMemberEmails(teamID))
+	assert.ElementsMatch(t, []interface{}{"Registered@example.com", "invited@example.com"},
+		d.Get("user_emails").(*schema.Set).List(), "spelling in config should be kept")
+
+	// Members added outside Terraform show as drift
+	_, err := c.CreateInvitation(teamID, "extra@example.com")
+	require.NoError(t, err)
+	d = r.Data(d.State())
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Contains(t, d.Get("user_emails").(*schema.Set).List(), "extra@example.com")
+
+	// Converging removes members not listed
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"name":        "offline-team",
+		"user_emails": []interface{}{"invited@example.com"},
+	})
+	assert.Equal(t, []string{"invited@example.com"}, f.teamMemberEmails(teamID))
+
+	// Without user_emails, members are left alone
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"name": "offline-team",
+	})
+	assert.Equal(t, []string{"invited@example.com"}, f.teamMemberEmails(teamID))
+	assert.Equal(t, 0, d.Get("user_emails").(*schema.Set).Len())
+}
+
+// TestOfflineUserAccountRole tests making users owners of the account and
+// members again.
+func TestOfflineUserAccountRole(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceUser()
+	ctx := context.Background()
+	team, err := c.CreateTeam("offline-team", client.TeamAccessLevelStandard)
+	require.NoError(t, err)
+
+	// Registered user
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"email":        "registered@example.com",
+		"team_ids":     []interface{}{team.ID},
+		"account_role": "owner",
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	assert.True(t, f.teamUsers[2][3], "user should be an owner")
+	assert.Equal(t, []interface{}{team.ID}, d.Get("team_ids").(*schema.Set).List())
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"email":        "registered@example.com",
+		"team_ids":     []interface{}{team.ID},
+		"account_role": "member",
+	})
+	assert.False(t, f.teamUsers[2][3], "user should no longer be an owner")
+	assert.True(t, f.teamUsers[team.ID][3])
+
+	// Without account_role, the role is only read
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"email":    "registered@example.com",
+		"team_ids": []interface{}{team.ID},
+	})
+	assert.Equal(t, "member", d.Get("account_role"))
+
+	// Changing only what happens on destroy makes no API call
+	requests := f.requests
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"email":               "registered@example.com",
+		"team_ids":            []interface{}{team.ID},
+		"remove_from_account": true,
+	})
+	assert.Equal(t, requests, f.requests)
+	assert.Equal(t, true, d.Get("remove_from_account"))
+
+	// Invited email
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"email":        "invited@example.com",
+		"team_ids":     []interface{}{team.ID},
+		"account_role": "owner",
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	assert.Equal(t, []string{"invited@example.com"}, f.teamMemberEmails(2))
+	assert.Equal(t, "owner", d.Get("account_role"))
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"email":        "invited@example.com",
+		"team_ids":     []interface{}{team.ID},
+		"account_role": "member",
+	})
+	assert.Empty(t, f.teamMemberEmails(2))
+	assert.Contains(t, f.teamMemberEmails(team.ID), "invited@example.com")
+}
+
+// TestOfflineProjectCRUD tests creating a project assigned to a team, changing
+// its name and teams, and deleting it.
+func TestOfflineProjectCRUD(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProject()
+	ctx := context.Background()
+
+	team1, err := c.CreateTeam("offline-team-1", "standard")
+	require.NoError(t, err)
+	team2, err := c.CreateTeam("offline-team-2", "standard")
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":     "offline-project",
+		"team_ids": []interface{}{team1.ID},
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	projectID := mustGetID(d)
+	assert.Equal(t, []interface{}{team1.ID}, d.Get("team_ids").(*schema.Set).List())
+	assert.Equal(t, "enabled", d.Get("status"))
+	assert.Empty(t, f.tokens[projectID], "default tokens should be deleted")
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"name":     "offline-project-renamed",
+		"team_ids": []interface{}{team2.ID},
+	})
+	assert.Equal(t, "offline-project-renamed", d.Get("name"))
+	assert.Equal(t, []interface{}{team2.ID}, d.Get("team_ids").(*schema.Set).List())
+
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	assert.NotContains(t, f.projects, projectID)
+}
+
+// TestOfflineProjectTeamsPartialUpdate tests that when converging team_ids
+// fails part way, state records the teams actually assigned.
+func TestOfflineProjectTeamsPartialUpdate(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProject()
+	ctx := context.Background()
+
+	team, err := c.CreateTeam("offline-team", "standard")
+	require.NoError(t, err)
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "offline-project",
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+
+	// Team 999999 does not exist, so assigning it fails
+	config := sdkterraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "offline-project",
+		"team_ids": []interface{}{team.ID, 999999},
+	})
+	diff, err := r.SimpleDiff(ctx, d.State(), config, m)
+	require.NoError(t, err)
+	state, diags := r.Apply(ctx, d.State(), diff, m)
+	assert.True(t, diags.HasError())
+	teamIDs := r.Data(state).Get("team_ids").(*schema.Set).List()
+	assert.NotContains(t, teamIDs, 999999)
+}
+
+// TestOfflineProjectDefaultTeams tests that the provider's default teams are
+// assigned to new projects, without showing in their team_ids.
+func TestOfflineProjectDefaultTeams(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProject()
+	ctx := context.Background()
+
+	admins, err := c.CreateTeam("offline-admins", "standard")
+	require.NoError(t, err)
+	team, err := c.CreateTeam("offline-team", "standard")
+	require.NoError(t, err)
+	c.DefaultTeamIDs = []int{admins.ID}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":     "offline-project",
+		"team_ids": []interface{}{team.ID},
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	projectID := mustGetID(d)
+	assert.True(t, f.teamProjects[admins.ID][projectID])
+	assert.Equal(t, []interface{}{team.ID}, d.Get("team_ids").(*schema.Set).List())
+
+	// Removing the project's own team keeps the default team
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"name": "offline-project",
+	})
+	assert.True(t, f.teamProjects[admins.ID][projectID])
+	assert.False(t, f.teamProjects[team.ID][projectID])
+	assert.Equal(t, 0, d.Get("team_ids").(*schema.Set).Len())
+}
+
+// TestOfflineProjectKeepDefaultTokens tests that the default post tokens are
+// kept and exposed when keep_default_tokens is set.
+func TestOfflineProjectKeepDefaultTokens(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceProject()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":                "offline-project",
+		"keep_default_tokens": true,
+	})
+	require.False(t, r.CreateContext(context.Background(), d, m).HasError())
+	assert.Len(t, f.tokens[mustGetID(d)], 2)
+	assert.NotEmpty(t, d.Get("post_server_item_access_token"))
+	assert.NotEmpty(t, d.Get("post_client_item_access_token"))
+}
+
+// TestOfflineProjectDuplicateName tests that creating a project with a name
+// already in use fails unless allow_existing is set.
+func TestOfflineProjectDuplicateName(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProject()
+	ctx := context.Background()
+
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "offline-project",
+	})
+	assert.True(t, r.CreateContext(ctx, d, m).HasError())
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":           "offline-project",
+		"allow_existing": true,
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	assert.Equal(t, strconv.Itoa(p.ID), d.Id())
+}
+
+// TestOfflineProjectAccessTokenCRUD tests creating, updating and deleting a
+// project access token.
+func TestOfflineProjectAccessTokenCRUD(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProjectAccessToken()
+	ctx := context.Background()
+
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"project_id": p.ID,
+		"name":       "offline-token",
+		"scopes":     []interface{}{"read", "write"},
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	value := d.Get("access_token").(string)
+	require.NotEmpty(t, value)
+	assert.Contains(t, f.tokens[p.ID], value)
+	assert.Equal(t, "enabled", d.Get("status"))
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"project_id":              p.ID,
+		"name":                    "offline-token",
+		"scopes":                  []interface{}{"read", "write"},
+		"rate_limit_window_count": 100,
+		"rate_limit_window_size":  60,
+	})
+	assert.Equal(t, 100, f.tokens[p.ID][value].RateLimitWindowCount)
+	assert.Equal(t, 60, d.Get("rate_limit_window_size"))
+
+	// Changing only when the token is rotated makes no API call
+	requests := f.requests
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"project_id":              p.ID,
+		"name":                    "offline-token",
+		"scopes":                  []interface{}{"read", "write"},
+		"rate_limit_window_count": 100,
+		"rate_limit_window_size":  60,
+		"rotation_days":           30,
+	})
+	assert.Equal(t, requests, f.requests)
+	assert.Equal(t, 30, d.Get("rotation_days"))
+	_, expiresAt := tokenTimestamps(d.Get("date_created").(int), 30)
+	assert.NotEmpty(t, expiresAt)
+	assert.Equal(t, expiresAt, d.Get("expires_at"))
+	assert.Equal(t, false, d.Get("ready_for_rotation"))
+
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	assert.NotContains(t, f.tokens[p.ID], value)
+}
+
+// TestOfflineProjectAccessTokenCreateFailure tests that a token is not leaked
+// when reading it back after creation fails.
+func TestOfflineProjectAccessTokenCreateFailure(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProjectAccessToken()
+	ctx := context.Background()
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+	readStatus := 0
+	f.fault = func(r *http.Request) int {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/access_token") {
+			return readStatus
+		}
+		return 0
+	}
+	config := map[string]interface{}{
+		"project_id": p.ID,
+		"name":       "offline-token",
+		"scopes":     []interface{}{"read"},
+	}
+
+	// An error reading the token leaves it recorded in state, to be replaced
+	readStatus = http.StatusInternalServerError
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	assert.True(t, r.CreateContext(ctx, d, m).HasError())
+	assert.NotEmpty(t, d.Id())
+	assert.Contains(t, f.tokens[p.ID], d.Id())
+
+	// A token not found after creation is deleted
+	readStatus = http.StatusNotFound
+	n := len(f.tokens[p.ID])
+	d = schema.TestResourceDataRaw(t, r.Schema, config)
+	diags := r.CreateContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Equal(t, `Project access token "offline-token" not found after creation`, diags[0].Summary)
+	assert.Empty(t, d.Id())
+	assert.Len(t, f.tokens[p.ID], n)
+	assert.Contains(t, diags[0].Detail, "was deleted")
+
+	// A failure deleting it is reported, naming the leaked token
+	f.fault = func(r *http.Request) int {
+		switch {
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/access_token"):
+			return http.StatusNotFound
+		case r.Method == http.MethodDelete:
+			return http.StatusInternalServerError
+		}
+		return 0
+	}
+	d = schema.TestResourceDataRaw(t, r.Schema, config)
+	diags = r.CreateContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Empty(t, d.Id())
+	assert.Len(t, f.tokens[p.ID], n+1)
+	assert.NotContains(t, diags[0].Detail, "was deleted")
+	for value := range f.tokens[p.ID] {
+		assert.NotContains(t, diags[0].Detail, value)
+	}
+	assert.Contains(t, diags[0].Detail, "Deleting the token failed")
+}
+
+// TestOfflineWireLog tests tracing API traffic to the provider's wire log file.
+func TestOfflineWireLog(t *testing.T) {
+	f := newFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "wire.log")
+	wireLog, err := openWireLog(path)
+	require.NoError(t, err)
+	m := newClients(f.URL, "fakeTokenString", "fakeTokenString", clientOptions{wireLog: wireLog})
+	r := resourceTeam()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "offline-team",
+	})
+	require.False(t, r.CreateContext(context.Background(), d, m).HasError())
+
+	trace, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(trace), "POST /api/1/teams")
+	assert.Contains(t, string(trace), `"name":"offline-team"`)
+	assert.NotContains(t, string(trace), "fakeTokenString")
+
+	wireLog, err = openWireLog("")
+	assert.NoError(t, err)
+	assert.Nil(t, wireLog)
+}
+
+// TestOfflineValidateCredentials tests checking the account access token when
+// the provider is configured.
+func TestOfflineValidateCredentials(t *testing.T) {
+	f := newFakeAPI(t)
+	configure := func(raw map[string]interface{}) diag.Diagnostics {
+		raw[schemaKeyBaseURL] = f.URL
+		d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+		_, diags := providerConfigure(context.Background(), d)
+		return diags
+	}
+
+	assert.False(t, configure(map[string]interface{}{schemaKeyToken: "fakeTokenString"}).HasError())
+	assert.False(t, configure(map[string]interface{}{schemaKeyToken: fakeReadOnlyToken}).HasError())
+
+	diags := configure(map[string]interface{}{schemaKeyToken: fakeInvalidToken})
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Invalid Rollbar API token", diags[0].Summary)
+
+	diags = configure(map[string]interface{}{schemaKeyToken: fakeNoScopeToken})
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Rollbar API token lacks read scope", diags[0].Summary)
+
+	diags = configure(map[string]interface{}{
+		schemaKeyToken:                     fakeInvalidToken,
+		schemaKeySkipCredentialsValidation: true,
+	})
+	assert.False(t, diags.HasError())
+
+	// Missing tokens are reported by the resources needing them
+	assert.False(t, configure(map[string]interface{}{}).HasError())
+
+	f.Close()
+	diags = configure(map[string]interface{}{schemaKeyToken: "fakeTokenString"})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "Could not validate Rollbar API token")
+	assert.Contains(t, diags[0].Detail, schemaKeySkipCredentialsValidation)
+}
+
+// TestOfflineConfig applies, updates and destroys a configuration through the
+// Terraform CLI against fakeAPI.
+func TestOfflineConfig(t *testing.T) {
+	if _, err := exec.LookPath("terraform"); err != nil && os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		t.Skip("terraform binary not found; set TF_ACC_TERRAFORM_PATH to run this test")
+	}
+	f := newFakeAPI(t)
+	config := func(projectName string) string {
+		return fmt.Sprintf(`
+			provider "rollbar" {
+				api_key         = "fakeTokenString"
+				project_api_key = "fakeTokenString"
+				api_url         = "%s"
+			}
+
+			resource "rollbar_team" "test" {
+				name = "offline-team"
+			}
+
+			resource "rollbar_project" "test" {
+				name     = "%s"
+				team_ids = [rollbar_team.test.id]
+			}
+
+			resource "rollbar_project_access_token" "test" {
+				project_id = rollbar_project.test.id
+				name       = "offline-token"
+				scopes     = ["read"]
+			}
+		`, f.URL, projectName)
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(Provider()),
+		CheckDestroy: func(*terraform.State) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if len(f.projects) > 0 || len(f.teams) > 2 {
+				return fmt.Errorf("resources remain after destroy")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("offline-project"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("rollbar_project.test", "name", "offline-project"),
+					resource.TestCheckResourceAttr("rollbar_project.test", "team_ids.#", "1"),
+					resource.TestCheckResourceAttrSet("rollbar_project_access_token.test", "access_token"),
+				),
+			},
+			{
+				Config: config("offline-project-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("rollbar_project.test", "name", "offline-project-renamed"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctymsgpack "github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rs/zerolog/log"
+)
+
+// replacementImpacts explains, by resource type, what is lost when a resource
+// is replaced because a ForceNew attribute changed.
+var replacementImpacts = map[string]string{
+	"rollbar_project": "The project is deleted and a new project created. Its items, " +
+		"occurrences, access tokens and team assignments are lost, and the new " +
+		"project has a different ID.",
+	"rollbar_project_access_token": "The token is deleted and a new token created. " +
+		"Applications using the old token's value are rejected by Rollbar until " +
+		"they are given the new value.",
+	"rollbar_user": "The old email is removed from its teams, and its pending " +
+		"invitations are cancelled, before the new email is invited.",
+	"rollbar_team_user": "The old user is removed from the team, or their invitation " +
+		"cancelled, before the new user is assigned or invited.",
+	"rollbar_team_membership": "All members managed by this resource are removed " +
+		"from the old team before members are added to the new team.",
+	"rollbar_person_data_deletion": "A new data deletion job is submitted. Data " +
+		"deleted by the previous job cannot be restored.",
+}
+
+// ruleDiffResources constructs, by resource type, the resources whose plans
+// carry a field-by-field summary of changes to their notification rules.
+// Rules are nested blocks, often in sets, which Terraform shows as a whole
+// block being replaced even when a single field changed.
+var ruleDiffResources = map[string]func() *schema.Resource{
+	"rollbar_notification":  resourceNotification,
+	"rollbar_notifications": resourceNotifications,
+}
+
+// ruleDiffAttrs are the attributes summarized in rule diffs.
+var ruleDiffAttrs = []string{"rule", "config", "rules_json"}
+
+// ruleFilterKeyAttrs are the filter attributes identifying a rule in a set,
+// in the order shown.
+var ruleFilterKeyAttrs = []string{"type", "operation", "value", "path", "period", "count"}
+
+// WithReplacementNotes wraps a provider server so that resource plans which
+// require replacement carry a warning explaining the impact, and plans
+// changing notification rules a summary of the fields changed.  The SDK's
+// CustomizeDiff can only return errors, not warnings, so the notes are added
+// to the plan response instead.
+func WithReplacementNotes(server func() tfprotov5.ProviderServer) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer {
+		return replacementNotesServer{server()}
+	}
+}
+
+// replacementNotesServer is a tfprotov5.ProviderServer adding replacement notes
+// to the plans of the server it wraps.
+type replacementNotesServer struct {
+	tfprotov5.ProviderServer
+}
+
+// PlanResourceChange implements tfprotov5.ResourceServer.
+func (s replacementNotesServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if d := replacementNote(req.TypeName, resp.RequiresReplace); d != nil {
+		resp.Diagnostics = append(resp.Diagnostics, d)
+	}
+	if d := ruleDiffNote(req.TypeName, req.PriorState, resp.PlannedState); d != nil {
+		resp.Diagnostics = append(resp.Diagnostics, d)
+	}
+	return resp, nil
+}
+
+// replacementNote returns a warning explaining the impact of replacing a
+// resource of type `typeName` because of changes to attributes `paths`, or nil
+// if the resource is not being replaced or there is nothing to explain.
+func replacementNote(typeName string, paths []*tftypes.AttributePath) *tfprotov5.Diagnostic {
+	impact, ok := replacementImpacts[typeName]
+	if !ok || len(paths) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var attrs []string
+	for _, p := range paths {
+		steps := p.Steps()
+		if len(steps) == 0 {
+			continue
+		}
+		name, ok := steps[0].(tftypes.AttributeName)
+		if !ok || seen[string(name)] {
+			continue
+		}
+		seen[string(name)] = true
+		attrs = append(attrs, string(name))
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	sort.Strings(attrs)
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  fmt.Sprintf("Changing %s replaces this %s", quotedList(attrs, "and"), typeName),
+		Detail:   impact,
+	}
+}
+
+// ruleDiffNote returns a warning listing the fields of the notification rules
+// of a resource of type `typeName` changed between states `prior` and
+// `planned`, e.g. to revert changes made outside Terraform, or nil if there
+// are none or the resource is being created or destroyed.
+func ruleDiffNote(typeName string, prior, planned *tfprotov5.DynamicValue) *tfprotov5.Diagnostic {
+	resource, ok := ruleDiffResources[typeName]
+	if !ok || prior == nil || planned == nil {
+		return nil
+	}
+	ty := resource().CoreConfigSchema().ImpliedType()
+	before, err := ctymsgpack.Unmarshal(prior.MsgPack, ty)
+	if err != nil {
+		log.Debug().Err(err).Msg("Not summarizing rule changes")
+		return nil
+	}
+	after, err := ctymsgpack.Unmarshal(planned.MsgPack, ty)
+	if err != nil {
+		log.Debug().Err(err).Msg("Not summarizing rule changes")
+		return nil
+	}
+	if before.IsNull() || after.IsNull() {
+		return nil
+	}
+	beforeFields := make(map[string]string)
+	afterFields := make(map[string]string)
+	for _, attr := range ruleDiffAttrs {
+		if !ty.HasAttribute(attr) {
+			continue
+		}
+		flattenRuleFields(attr, before.GetAttr(attr), beforeFields)
+		flattenRuleFields(attr, after.GetAttr(attr), afterFields)
+	}
+	keys := make(map[string]bool)
+	for k, v := range beforeFields {
+		if afterFields[k] != v {
+			keys[k] = true
+		}
+	}
+	for k, v := range afterFields {
+		if beforeFields[k] != v {
+			keys[k] = true
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	changes := make([]string, 0, len(keys))
+	for k := range keys {
+		changes = append(changes, k)
+	}
+	sort.Strings(changes)
+	for i, k := range changes {
+		changes[i] = fmt.Sprintf("  %s: %s -> %s", k, ruleFieldOrNone(beforeFields, k), ruleFieldOrNone(afterFields, k))
+	}
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  fmt.Sprintf("Notification rule fields of this %s change", typeName),
+		Detail:   "Fields changed, including any changed outside Terraform:\n" + strings.Join(changes, "\n"),
+	}
+}
+
+// sensitiveRuleField prefixes flattened sensitive values, which are compared
+// but never shown.
+const sensitiveRuleField = "\x00"
+
+// ruleFieldOrNone returns field `key` of flattened fields for display, or
+// "(none)".
+func ruleFieldOrNone(fields map[string]string, key string) string {
+	v, ok := fields[key]
+	switch {
+	case !ok:
+		return "(none)"
+	case strings.HasPrefix(v, sensitiveRuleField):
+		return "(sensitive value)"
+	}
+	return v
+}
+
+// flattenRuleFields adds the primitive values nested in value `v` at path
+// `prefix` to `fields`, keyed by their paths, e.g. `rule[0].trigger`.  Null
+// and empty values are left out.  Rules in a set have no stable index, so they
+// are keyed by their trigger and filters instead, e.g.
+// `rule[new_item: level gte "error"]`, and only their presence is compared.
+// The rules in `rules_json` are flattened from their JSON.
+func flattenRuleFields(prefix string, v cty.Value, fields map[string]string) {
+	switch {
+	case !v.IsKnown():
+		fields[prefix] = "(known after apply)"
+		return
+	case v.IsNull():
+		return
+	case strings.HasSuffix(prefix, "service_key"):
+		if v.AsString() != "" {
+			fields[prefix] = sensitiveRuleField + v.AsString()
+		}
+		return
+	case prefix == "rules_json" && v.Type() == cty.String:
+		var rules []interface{}
+		if err := json.Unmarshal([]byte(v.AsString()), &rules); err != nil {
+			fields[prefix] = strconv.Quote(v.AsString())
+			return
+		}
+		flattenRuleJSONFields(prefix, rules, fields)
+		return
+	}
+	ty := v.Type()
+	switch {
+	case ty == cty.String:
+		if s := v.AsString(); s != "" {
+			fields[prefix] = strconv.Quote(s)
+		}
+	case ty == cty.Number:
+		fields[prefix] = v.AsBigFloat().Text('f', -1)
+	case ty == cty.Bool:
+		fields[prefix] = strconv.FormatBool(v.True())
+	case ty.IsObjectType():
+		for name := range ty.AttributeTypes() {
+			flattenRuleFields(prefix+"."+name, v.GetAttr(name), fields)
+		}
+	case ty.IsSetType() && ty.ElementType().IsObjectType() && ty.ElementType().HasAttribute("trigger"):
+		i := 0
+		for it := v.ElementIterator(); it.Next(); i++ {
+			_, e := it.Element()
+			key, ok := ruleSetElementKey(e)
+			if !ok {
+				flattenRuleFields(fmt.Sprintf("%s[%d]", prefix, i), e, fields)
+				continue
+			}
+			fields[fmt.Sprintf("%s[%s]", prefix, key)] = "present"
+		}
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		i := 0
+		for it := v.ElementIterator(); it.Next(); i++ {
+			_, e := it.Element()
+			flattenRuleFields(fmt.Sprintf("%s[%d]", prefix, i), e, fields)
+		}
+	case ty.IsMapType():
+		for it := v.ElementIterator(); it.Next(); {
+			k, e := it.Element()
+			flattenRuleFields(prefix+"."+k.AsString(), e, fields)
+		}
+	}
+}
+
+// ruleSetElementKey returns a key identifying rule `e` of a set by its trigger
+// and filters, e.g. `new_item: level gte "error"`, or false if they are not
+// known yet.
+func ruleSetElementKey(e cty.Value) (string, bool) {
+	if !e.IsWhollyKnown() || e.IsNull() {
+		return "", false
+	}
+	trigger := e.GetAttr("trigger")
+	if trigger.IsNull() {
+		return "", false
+	}
+	key := trigger.AsString()
+	if !e.Type().HasAttribute("filters") || e.GetAttr("filters").IsNull() {
+		return key, true
+	}
+	filters := []string{}
+	for it := e.GetAttr("filters").ElementIterator(); it.Next(); {
+		_, f := it.Element()
+		values := []string{}
+		for _, attr := range ruleFilterKeyAttrs {
+			if !f.Type().HasAttribute(attr) || f.GetAttr(attr).IsNull() {
+				continue
+			}
+			switch v := f.GetAttr(attr); {
+			case v.Type() == cty.Number && v.AsBigFloat().Sign() != 0:
+				values = append(values, attr+"="+v.AsBigFloat().Text('f', -1))
+			case v.Type() != cty.String || v.AsString() == "":
+			case attr == "type" || attr == "operation":
+				values = append(values, v.AsString())
+			default:
+				values = append(values, strconv.Quote(v.AsString()))
+			}
+		}
+		filters = append(filters, strings.Join(values, " "))
+	}
+	if len(filters) == 0 {
+		return key, true
+	}
+	return key + ": " + strings.Join(filters, ", "), true
+}
+
+// flattenRuleJSONFields adds the primitive values nested in decoded JSON value
+// `v` at path `prefix` to `fields`, as flattenRuleFields does for cty values.
+func flattenRuleJSONFields(prefix string, v interface{}, fields map[string]string) {
+	switch v := v.(type) {
+	case nil:
+	case string:
+		switch {
+		case strings.HasSuffix(prefix, "service_key"):
+			if v != "" {
+				fields[prefix] = sensitiveRuleField + v
+			}
+		case v != "":
+			fields[prefix] = strconv.Quote(v)
+		}
+	case float64:
+		fields[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		fields[prefix] = strconv.FormatBool(v)
+	case map[string]interface{}:
+		for k, e := range v {
+			flattenRuleJSONFields(prefix+"."+k, e, fields)
+		}
+	case []interface{}:
+		for i, e := range v {
+			flattenRuleJSONFields(fmt.Sprintf("%s[%d]", prefix, i), e, fields)
+		}
+	}
+}
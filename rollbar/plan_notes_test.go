@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	ctymsgpack "github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// planServer is a tfprotov5.ProviderServer whose plans require replacement
+// because of changes to `requiresReplace`.
+type planServer struct {
+	tfprotov5.ProviderServer
+	requiresReplace []*tftypes.AttributePath
+}
+
+func (s planServer) PlanResourceChange(_ context.Context, _ *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	return &tfprotov5.PlanResourceChangeResponse{RequiresReplace: s.requiresReplace}, nil
+}
+
+// TestReplacementNotes tests warning of the impact of replacing resources.
+func TestReplacementNotes(t *testing.T) {
+	ctx := context.Background()
+	paths := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("scopes").WithElementKeyInt(0),
+		tftypes.NewAttributePath().WithAttributeName("name"),
+		tftypes.NewAttributePath().WithAttributeName("scopes").WithElementKeyInt(1),
+	}
+	server := WithReplacementNotes(func() tfprotov5.ProviderServer {
+		return planServer{requiresReplace: paths}
+	})()
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{TypeName: "rollbar_project_access_token"})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Diagnostics, 1)
+	d := resp.Diagnostics[0]
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, d.Severity)
+	assert.Equal(t, `Changing "name" and "scopes" replaces this rollbar_project_access_token`, d.Summary)
+	assert.Contains(t, d.Detail, "Applications using the old token")
+
+	// Resources without replacement impact
+	resp, err = server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{TypeName: "rollbar_notification"})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Diagnostics)
+
+	// Plans not requiring replacement
+	assert.Nil(t, replacementNote("rollbar_project", nil))
+}
+
+// notificationState returns the msgpack encoded state of a resource `r`, e.g.
+// a rollbar_notification, with attributes `raw`.
+func notificationState(t *testing.T, r *schema.Resource, raw map[string]interface{}) *tfprotov5.DynamicValue {
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId("1")
+	ty := r.CoreConfigSchema().ImpliedType()
+	v, err := d.State().AttrsAsObjectValue(ty)
+	require.NoError(t, err)
+	b, err := ctymsgpack.Marshal(v, ty)
+	require.NoError(t, err)
+	return &tfprotov5.DynamicValue{MsgPack: b}
+}
+
+// TestRuleDiffNote tests summarizing the fields changed in notification rules.
+func TestRuleDiffNote(t *testing.T) {
+	rule := func(trigger, level string) map[string]interface{} {
+		return map[string]interface{}{
+			"channel": "email",
+			"rule": []interface{}{map[string]interface{}{
+				"trigger": trigger,
+				"filters": []interface{}{map[string]interface{}{"type": "level", "operation": "gte", "value": level}},
+			}},
+			"config": []interface{}{map[string]interface{}{
+				"teams":       []interface{}{"Owners"},
+				"service_key": level,
+			}},
+		}
+	}
+	prior := notificationState(t, resourceNotification(), rule("new_item", "critical"))
+	planned := notificationState(t, resourceNotification(), rule("reactivated_item", "error"))
+
+	d := ruleDiffNote("rollbar_notification", prior, planned)
+	require.NotNil(t, d)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, d.Severity)
+	assert.Equal(t, "Notification rule fields of this rollbar_notification change", d.Summary)
+	assert.Equal(t, "Fields changed, including any changed outside Terraform:\n"+
+		"  config[0].service_key: (sensitive value) -> (sensitive value)\n"+
+		`  rule[new_item: level gte "critical"]: present -> (none)`+"\n"+
+		`  rule[reactivated_item: level gte "error"]: (none) -> present`, d.Detail)
+
+	// Rules are a set, so adding one only shows the rule added
+	raw := rule("new_item", "critical")
+	raw["rule"] = append(raw["rule"].([]interface{}), map[string]interface{}{
+		"trigger": "occurrence_rate",
+		"filters": []interface{}{map[string]interface{}{"type": "rate", "period": 300, "count": 10}},
+	})
+	d = ruleDiffNote("rollbar_notification", prior, notificationState(t, resourceNotification(), raw))
+	require.NotNil(t, d)
+	assert.Equal(t, "Fields changed, including any changed outside Terraform:\n"+
+		"  rule[occurrence_rate: rate period=300 count=10]: (none) -> present", d.Detail)
+
+	// The rules in rules_json are summarized from their JSON
+	rulesJSON := func(level string) map[string]interface{} {
+		return map[string]interface{}{
+			"channel":    "email",
+			"rules_json": `[{"trigger": "new_item", "filters": [{"type": "level", "operation": "gte", "value": "` + level + `"}]}]`,
+		}
+	}
+	d = ruleDiffNote("rollbar_notifications",
+		notificationState(t, resourceNotifications(), rulesJSON("critical")),
+		notificationState(t, resourceNotifications(), rulesJSON("error")))
+	require.NotNil(t, d)
+	assert.Equal(t, "Fields changed, including any changed outside Terraform:\n"+
+		`  rules_json[0].filters[0].value: "critical" -> "error"`, d.Detail)
+
+	// Unchanged rules, other resources, and creation or destruction
+	assert.Nil(t, ruleDiffNote("rollbar_notification", prior, prior))
+	assert.Nil(t, ruleDiffNote("rollbar_project", prior, planned))
+	assert.Nil(t, ruleDiffNote("rollbar_notification", nil, planned))
+}
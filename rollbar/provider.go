@@ -25,47 +25,204 @@ package rollbar
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/mapstructure"
 	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 const schemaKeyToken = "api_key"
 const projectKeyToken = "project_api_key"
 const schemaKeyBaseURL = "api_url"
+const schemaKeyMaxConcurrentRequests = "max_concurrent_requests"
+const schemaKeyCacheTTL = "cache_ttl_seconds"
+const schemaKeyConditionalCacheSize = "conditional_request_cache_size"
+const schemaKeyMaxIdleConns = "max_idle_connections"
+const schemaKeyMaxIdleConnsPerHost = "max_idle_connections_per_host"
+const schemaKeyIdleConnTimeout = "idle_connection_timeout_seconds"
+const schemaKeyKeepAlive = "keep_alive_seconds"
+const schemaKeyRequestTimeout = "request_timeout_seconds"
+const schemaKeyCompressionThreshold = "request_compression_threshold"
+const schemaKeyLogLevel = "log_level"
+const schemaKeyLogFormat = "log_format"
+const schemaKeyDefaultTeamIDs = "default_team_ids"
+const schemaKeyWireLogFile = "wire_log_file"
+const schemaKeySkipCredentialsValidation = "skip_credentials_validation"
+const schemaKeyCompatibilityMode = "compatibility_mode"
+
+// Provider argument descriptions, shared with the framework provider whose
+// schema must be identical.
+const (
+	descToken               = "Rollbar API authentication token. Value will be sourced from environment variable `ROLLBAR_API_KEY` if set."
+	descProjectToken        = "Rollbar API authentication token (project level). Value will be sourced from environment variable `ROLLBAR_PROJECT_API_KEY` if set."
+	descBaseURL             = "Base URL for the Rollbar API, which may include a path prefix for a self-hosted server.  Defaults to https://api.rollbar.com.  Value will be sourced from environment variable `ROLLBAR_API_URL` if set."
+	descMaxRequests         = "Maximum number of concurrent requests to the Rollbar API made with this provider configuration, regardless of Terraform parallelism.  Defaults to 0, meaning unlimited.  Value will be sourced from environment variable `ROLLBAR_MAX_CONCURRENT_REQUESTS` if set."
+	descCacheTTL            = "Number of seconds for which lists of projects and teams are cached, sharing one API call between data sources and name based lookups.  Defaults to 0, meaning no caching.  Value will be sourced from environment variable `ROLLBAR_CACHE_TTL_SECONDS` if set."
+	descConditionalCache    = "Number of API responses remembered to revalidate repeated reads with conditional requests, so that unchanged responses are not downloaded again.  Access tokens are never remembered.  Defaults to 0, meaning conditional requests are not sent.  Value will be sourced from environment variable `ROLLBAR_CONDITIONAL_REQUEST_CACHE_SIZE` if set."
+	descMaxIdleConns        = "Maximum number of idle HTTP connections kept open for reuse.  Defaults to 0, meaning 100.  Value will be sourced from environment variable `ROLLBAR_MAX_IDLE_CONNECTIONS` if set."
+	descMaxIdleConnsPerHost = "Maximum number of idle HTTP connections to the Rollbar API kept open for reuse.  Defaults to 0, meaning 2.  Value will be sourced from environment variable `ROLLBAR_MAX_IDLE_CONNECTIONS_PER_HOST` if set."
+	descIdleConnTimeout     = "Number of seconds an idle HTTP connection is kept open.  Defaults to 0, meaning 90.  Value will be sourced from environment variable `ROLLBAR_IDLE_CONNECTION_TIMEOUT_SECONDS` if set."
+	descKeepAlive           = "Number of seconds between TCP keep-alive probes on open connections.  Defaults to 0, meaning 30.  Value will be sourced from environment variable `ROLLBAR_KEEP_ALIVE_SECONDS` if set."
+	descRequestTimeout      = "Number of seconds after which a request to the Rollbar API is abandoned.  Defaults to 0, meaning no timeout.  Value will be sourced from environment variable `ROLLBAR_REQUEST_TIMEOUT_SECONDS` if set."
+	descCompression         = "Size in bytes from which request bodies are gzip compressed.  Rollbar does not document accepting compressed request bodies, so only enable this if your server is known to.  Defaults to 0, meaning request bodies are never compressed.  Value will be sourced from environment variable `ROLLBAR_REQUEST_COMPRESSION_THRESHOLD` if set."
+	descLogLevel            = "Level of the provider's logs, which Terraform includes in its own log: one of `trace`, `debug`, `info`, `warn`, `error` or `off`.  Defaults to the level set by `TF_LOG_PROVIDER` or `TF_LOG`, or `warn`.  Value will be sourced from environment variable `ROLLBAR_LOG_LEVEL` if set."
+	descLogFormat           = "Format of the provider's logs: `json` or `console`.  Defaults to `json`.  Value will be sourced from environment variable `ROLLBAR_LOG_FORMAT` if set."
+	descDefaultTeamIDs      = "IDs of teams assigned to every project the provider creates or adopts, in addition to the project's own `team_ids`, e.g. so an admin team always has access."
+	descSkipCredentials     = "If true, the account access token is not checked when the provider is configured, saving an API call.  Value will be sourced from environment variable `ROLLBAR_SKIP_CREDENTIALS_VALIDATION` if set."
+	descCompatibilityMode   = "If true, any successful HTTP status is accepted from the Rollbar API, not only 200 or 201, for older self-hosted servers.  Value will be sourced from environment variable `ROLLBAR_COMPATIBILITY_MODE` if set."
+	descWireLogFile         = "Path of a file to which full traces of API requests and responses are appended, with access tokens redacted, for debugging.  Value will be sourced from environment variable `ROLLBAR_WIRE_LOG_FILE` if set."
+)
 
 // Provider is a Terraform provider for Rollbar.
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			schemaKeyToken: {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_API_KEY", nil),
-				Description: "Rollbar API authentication token. Value will be sourced from environment variable `ROLLBAR_API_KEY` if set.",
+				Description: descToken,
 			},
 			projectKeyToken: {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_PROJECT_API_KEY", nil),
-				Description: "Rollbar API authentication token (project level). Value will be sourced from environment variable `ROLLBAR_PROJECT_API_KEY` if set.",
+				Description: descProjectToken,
 			},
 			schemaKeyBaseURL: {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_API_URL", client.DefaultBaseURL),
-				Description: "Base URL for the Rollbar API.  Defaults to https://api.rollbar.com.  Value will be sourced from environment variable `ROLLBAR_API_URL` if set.",
+				Description: descBaseURL,
+			},
+			schemaKeyMaxConcurrentRequests: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descMaxRequests,
+			},
+			schemaKeyCacheTTL: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_CACHE_TTL_SECONDS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descCacheTTL,
+			},
+			schemaKeyConditionalCacheSize: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_CONDITIONAL_REQUEST_CACHE_SIZE", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descConditionalCache,
+			},
+			schemaKeyMaxIdleConns: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_MAX_IDLE_CONNECTIONS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descMaxIdleConns,
+			},
+			schemaKeyMaxIdleConnsPerHost: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_MAX_IDLE_CONNECTIONS_PER_HOST", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descMaxIdleConnsPerHost,
+			},
+			schemaKeyIdleConnTimeout: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_IDLE_CONNECTION_TIMEOUT_SECONDS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descIdleConnTimeout,
+			},
+			schemaKeyKeepAlive: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_KEEP_ALIVE_SECONDS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descKeepAlive,
+			},
+			schemaKeyRequestTimeout: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_REQUEST_TIMEOUT_SECONDS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descRequestTimeout,
+			},
+			schemaKeyCompressionThreshold: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_REQUEST_COMPRESSION_THRESHOLD", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descCompression,
+			},
+			schemaKeyLogLevel: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_LOG_LEVEL", nil),
+				ValidateFunc: validation.StringInSlice(logLevels, false),
+				Description:  descLogLevel,
+			},
+			schemaKeyLogFormat: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ROLLBAR_LOG_FORMAT", nil),
+				ValidateFunc: validation.StringInSlice(logFormats, false),
+				Description:  descLogFormat,
+			},
+			schemaKeyDefaultTeamIDs: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: descDefaultTeamIDs,
+			},
+			schemaKeyWireLogFile: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_WIRE_LOG_FILE", nil),
+				Description: descWireLogFile,
+			},
+			schemaKeySkipCredentialsValidation: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_SKIP_CREDENTIALS_VALIDATION", false),
+				Description: descSkipCredentials,
+			},
+			schemaKeyCompatibilityMode: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ROLLBAR_COMPATIBILITY_MODE", false),
+				Description: descCompatibilityMode,
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"rollbar_project":              resourceProject(),
-			"rollbar_project_access_token": resourceProjectAccessToken(),
-			"rollbar_team":                 resourceTeam(),
-			"rollbar_user":                 resourceUser(),
-			"rollbar_team_user":            resourceTeamUser(),
-			"rollbar_notification":         resourceNotification(),
+			"rollbar_project":               resourceProject(),
+			"rollbar_project_access_token":  resourceProjectAccessToken(),
+			"rollbar_project_access_tokens": resourceProjectAccessTokens(),
+			"rollbar_team":                  resourceTeam(),
+			"rollbar_user":                  resourceUser(),
+			"rollbar_team_user":             resourceTeamUser(),
+			"rollbar_team_membership":       resourceTeamMembership(),
+			"rollbar_person_data_deletion":  resourcePersonDataDeletion(),
+			"rollbar_notification":          resourceNotification(),
+			"rollbar_notifications":         resourceNotifications(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"rollbar_project":               dataSourceProject(),
@@ -73,20 +230,181 @@ func Provider() *schema.Provider {
 			"rollbar_project_access_token":  dataSourceProjectAccessToken(),
 			"rollbar_project_access_tokens": dataSourceProjectAccessTokens(),
 			"rollbar_team":                  dataSourceTeam(),
+			"rollbar_people":                dataSourcePeople(),
+			"rollbar_item":                  dataSourceItem(),
+			"rollbar_item_occurrences":      dataSourceItemOccurrences(),
+			"rollbar_notification_rules":    dataSourceNotificationRules(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+	for _, r := range p.ResourcesMap {
+		withDefaultTimeouts(r)
+	}
+	return p
 }
 
 // providerConfigure sets up authentication in a Resty HTTP client.
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
+	err := ConfigureLogging(d.Get(schemaKeyLogLevel).(string), d.Get(schemaKeyLogFormat).(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
 	token := d.Get(schemaKeyToken).(string)
 	projectToken := d.Get(projectKeyToken).(string)
 	baseURL := d.Get(schemaKeyBaseURL).(string)
-	c := client.NewClient(baseURL, token)
-	pc := client.NewClient(baseURL, projectToken)
-	return map[string]*client.RollbarAPIClient{schemaKeyToken: c, projectKeyToken: pc}, diags
+	wireLog, err := openWireLog(d.Get(schemaKeyWireLogFile).(string))
+	if err != nil {
+		return nil, diagFromErr(err, schemaKeyWireLogFile)
+	}
+	seconds := func(key string) time.Duration {
+		return time.Duration(d.Get(key).(int)) * time.Second
+	}
+	o := clientOptions{
+		maxRequests: d.Get(schemaKeyMaxConcurrentRequests).(int),
+		cacheTTL:    seconds(schemaKeyCacheTTL),
+		conditional: d.Get(schemaKeyConditionalCacheSize).(int),
+		compression: d.Get(schemaKeyCompressionThreshold).(int),
+		teamIDs:     intsFromSet(d.Get(schemaKeyDefaultTeamIDs).(*schema.Set)),
+		wireLog:     wireLog,
+		compat:      d.Get(schemaKeyCompatibilityMode).(bool),
+		transport: client.TransportOptions{
+			MaxIdleConns:        d.Get(schemaKeyMaxIdleConns).(int),
+			MaxIdleConnsPerHost: d.Get(schemaKeyMaxIdleConnsPerHost).(int),
+			IdleConnTimeout:     seconds(schemaKeyIdleConnTimeout),
+			KeepAlive:           seconds(schemaKeyKeepAlive),
+			RequestTimeout:      seconds(schemaKeyRequestTimeout),
+		},
+	}
+	clients := newClients(baseURL, token, projectToken, o)
+	// Abandon requests in flight when Terraform is interrupted, e.g. by Ctrl-C,
+	// which cancels the stop context rather than that of each operation
+	if stop, ok := schema.StopContext(ctx); ok {
+		for key, c := range clients {
+			clients[key] = c.WithStopContext(stop)
+		}
+	}
+	if !d.Get(schemaKeySkipCredentialsValidation).(bool) {
+		diags = append(diags, validateCredentials(clients[schemaKeyToken].WithContext(ctx))...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+	return clients, diags
+}
+
+// validateCredentials checks the account access token with a single cheap API
+// call, so that an invalid token fails the run before any resource is changed,
+// rather than part way through an apply.  Only the SDK provider does this; the
+// muxed framework provider is configured with the same token.  A missing token
+// is reported by each resource needing it, so is not checked here.
+func validateCredentials(c *client.RollbarAPIClient) diag.Diagnostics {
+	if !c.HasToken() {
+		return nil
+	}
+	log.Debug().Msg("Validating Rollbar API token")
+	_, err := c.ListProjects()
+	if err == nil {
+		return nil
+	}
+	skip := fmt.Sprintf("To skip this check, set provider argument %s.", schemaKeySkipCredentialsValidation)
+	var er *client.ErrorResult
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid Rollbar API token",
+			Detail:        fmt.Sprintf("Rollbar rejected the token set by provider argument %s (or environment variable ROLLBAR_API_KEY). Check that it is an account access token, not a project access token, and that it has not been deleted.", schemaKeyToken),
+			AttributePath: cty.GetAttrPath(schemaKeyToken),
+		}}
+	case errors.As(err, &er) && er.StatusCode == http.StatusForbidden:
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Rollbar API token lacks read scope",
+			Detail:        fmt.Sprintf("The token set by provider argument %s could not list projects. Managing projects, teams and users needs an account access token with `read` and `write` scopes. %s", schemaKeyToken, skip),
+			AttributePath: cty.GetAttrPath(schemaKeyToken),
+		}}
+	}
+	diags := diagFromErr(err)
+	diags[0].Summary = "Could not validate Rollbar API token: " + diags[0].Summary
+	diags[0].Detail = strings.TrimSpace(diags[0].Detail + " " + skip)
+	return diags
+}
+
+// clientOptions configures the Rollbar API clients set up by newClients.
+type clientOptions struct {
+	maxRequests int                     // Limit on concurrent requests of the provider configuration, or unlimited if zero
+	cacheTTL    time.Duration           // How long to cache list results, or not at all if zero
+	conditional int                     // Responses remembered for conditional requests, or none if zero
+	transport   client.TransportOptions // HTTP connection tuning
+	compression int                     // Size from which request bodies are compressed, or never if zero
+	teamIDs     []int                   // Teams assigned to every project created
+	wireLog     io.Writer               // Trace of requests and responses, or none if nil
+	compat      bool                    // Relax assumptions about API responses
+}
+
+// limiterKey identifies a provider configuration by its API URL, tokens and
+// limit on concurrent requests.
+type limiterKey struct {
+	baseURL      string
+	token        string
+	projectToken string
+	limit        int
+}
+
+// limiters holds the Limiter of each provider configuration, so that the SDK
+// and framework halves of the muxed provider, which are configured separately in
+// the same process, share a single limit.
+var limiters = struct {
+	sync.Mutex
+	byConfig map[limiterKey]*client.Limiter
+}{byConfig: make(map[limiterKey]*client.Limiter)}
+
+// sharedLimiter returns the Limiter allowing at most n concurrent requests for
+// the provider configuration with API URL baseURL and the given tokens, creating
+// it on first use.  It is nil, imposing no limit, if n is zero or less.
+func sharedLimiter(baseURL, token, projectToken string, n int) *client.Limiter {
+	if n <= 0 {
+		return nil
+	}
+	key := limiterKey{baseURL: baseURL, token: token, projectToken: projectToken, limit: n}
+	limiters.Lock()
+	defer limiters.Unlock()
+	l, ok := limiters.byConfig[key]
+	if !ok {
+		l = client.NewLimiter(n)
+		limiters.byConfig[key] = l
+	}
+	return l
+}
+
+// newClients sets up the account and project level Rollbar API clients, keyed
+// by the name of the provider argument holding their token.  The clients are
+// configured according to o.  All clients of the same provider configuration
+// share one limit.
+func newClients(baseURL, token, projectToken string, o clientOptions) map[string]*client.RollbarAPIClient {
+	opts := []client.Option{
+		client.WithBaseURL(baseURL),
+		client.WithTransportOptions(o.transport),
+		client.WithRateLimit(sharedLimiter(baseURL, token, projectToken, o.maxRequests)),
+	}
+	if o.compat {
+		opts = append(opts, client.WithCompatibilityMode())
+	}
+	clients := make(map[string]*client.RollbarAPIClient)
+	for key, t := range map[string]string{schemaKeyToken: token, projectKeyToken: projectToken} {
+		clients[key] = client.NewClient(t, append(slices.Clip(opts), client.WithTokenName(key))...)
+	}
+	for _, c := range clients {
+		c.EnableCompression(o.compression)
+		c.EnableConditionalRequests(o.conditional)
+		c.SetCacheTTL(o.cacheTTL)
+		if o.wireLog != nil {
+			c.EnableWireLog(o.wireLog)
+		}
+	}
+	clients[schemaKeyToken].DefaultTeamIDs = o.teamIDs
+	return clients
 }
 
 /*
@@ -108,6 +426,21 @@ func (es *errSetter) Set(key string, value interface{}) {
 
 */
 
+// openWireLog opens the file at path for appending traces of API requests and
+// responses, or returns nil if path is blank.  The file stays open for the life
+// of the provider process.
+func openWireLog(path string) (io.Writer, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open wire log file: %w", err)
+	}
+	log.Warn().Str("path", path).Msg("Tracing API requests and responses to wire log file")
+	return f, nil
+}
+
 // mustSet sets a value for a key in a schema, or panics on error.
 func mustSet(d *schema.ResourceData, key string, value interface{}) {
 	err := d.Set(key, value)
@@ -116,6 +449,15 @@ func mustSet(d *schema.ResourceData, key string, value interface{}) {
 	}
 }
 
+// intsFromSet returns the elements of a set of integers.
+func intsFromSet(set *schema.Set) []int {
+	ints := make([]int, set.Len())
+	for i, v := range set.List() {
+		ints[i] = v.(int)
+	}
+	return ints
+}
+
 // mustGetID gets the ID of the resource as an integer, or panics if string ID
 // value cannot be cast to int.
 func mustGetID(d *schema.ResourceData) int {
@@ -126,6 +468,48 @@ func mustGetID(d *schema.ResourceData) int {
 	return id
 }
 
+// getWriteOnlyString gets the value of a write-only string attribute from the
+// raw Terraform configuration, returning an empty string if it is not set.
+func getWriteOnlyString(d *schema.ResourceData, key string) (string, diag.Diagnostics) {
+	v, diags := d.GetRawConfigAt(cty.GetAttrPath(key))
+	if diags.HasError() {
+		return "", diags
+	}
+	if v.IsNull() || !v.IsKnown() || !v.Type().Equals(cty.String) {
+		return "", nil
+	}
+	return v.AsString(), nil
+}
+
+// importNumericID is an importer for resources identified by a single integer
+// Rollbar ID.  It rejects malformed IDs up front, rather than letting
+// mustGetID panic during the subsequent read.
+func importNumericID(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	_, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("unexpected format of ID (%q), expected a numeric ID", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// quotedList formats values as a quoted, comma separated list for use in
+// descriptions and diagnostics, e.g. `"a", "b", or "c"`.
+func quotedList(values []string, conjunction string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	switch len(quoted) {
+	case 0:
+		return ""
+	case 1:
+		return quoted[0]
+	case 2:
+		return quoted[0] + " " + conjunction + " " + quoted[1]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + ", " + conjunction + " " + quoted[len(quoted)-1]
+}
+
 // Decode takes an input structure and uses reflection to translate it to the
 // output structure, panicking on error. Output must be a pointer to a map or
 // struct.
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// frameworkProvider implements the parts of the Rollbar provider that require
+// terraform-plugin-framework, such as ephemeral resources and functions.  It is muxed with the
+// SDK based Provider(), so its provider schema must be identical.
+type frameworkProvider struct{}
+
+// frameworkProviderModel describes the provider configuration.
+type frameworkProviderModel struct {
+	Token        types.String `tfsdk:"api_key"`
+	ProjectToken types.String `tfsdk:"project_api_key"`
+	BaseURL      types.String `tfsdk:"api_url"`
+	MaxRequests  types.Int64  `tfsdk:"max_concurrent_requests"`
+	CacheTTL     types.Int64  `tfsdk:"cache_ttl_seconds"`
+
+	ConditionalCacheSize types.Int64 `tfsdk:"conditional_request_cache_size"`
+
+	MaxIdleConns        types.Int64 `tfsdk:"max_idle_connections"`
+	MaxIdleConnsPerHost types.Int64 `tfsdk:"max_idle_connections_per_host"`
+	IdleConnTimeout     types.Int64 `tfsdk:"idle_connection_timeout_seconds"`
+	KeepAlive           types.Int64 `tfsdk:"keep_alive_seconds"`
+	RequestTimeout      types.Int64 `tfsdk:"request_timeout_seconds"`
+
+	CompressionThreshold types.Int64 `tfsdk:"request_compression_threshold"`
+
+	LogLevel  types.String `tfsdk:"log_level"`
+	LogFormat types.String `tfsdk:"log_format"`
+
+	DefaultTeamIDs types.Set    `tfsdk:"default_team_ids"`
+	WireLogFile    types.String `tfsdk:"wire_log_file"`
+
+	// Credentials are validated by the SDK provider only
+	SkipCredentialsValidation types.Bool `tfsdk:"skip_credentials_validation"`
+
+	CompatibilityMode types.Bool `tfsdk:"compatibility_mode"`
+}
+
+// NewFrameworkProvider constructs the terraform-plugin-framework half of the
+// Rollbar provider.
+func NewFrameworkProvider() provider.Provider {
+	return &frameworkProvider{}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "rollbar"
+}
+
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			schemaKeyToken: schema.StringAttribute{
+				Description: descToken,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			projectKeyToken: schema.StringAttribute{
+				Description: descProjectToken,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			schemaKeyBaseURL: schema.StringAttribute{
+				Description: descBaseURL,
+				Optional:    true,
+			},
+			schemaKeyMaxConcurrentRequests: schema.Int64Attribute{
+				Description: descMaxRequests,
+				Optional:    true,
+			},
+			schemaKeyCacheTTL: schema.Int64Attribute{
+				Description: descCacheTTL,
+				Optional:    true,
+			},
+			schemaKeyConditionalCacheSize: schema.Int64Attribute{
+				Description: descConditionalCache,
+				Optional:    true,
+			},
+			schemaKeyMaxIdleConns: schema.Int64Attribute{
+				Description: descMaxIdleConns,
+				Optional:    true,
+			},
+			schemaKeyMaxIdleConnsPerHost: schema.Int64Attribute{
+				Description: descMaxIdleConnsPerHost,
+				Optional:    true,
+			},
+			schemaKeyIdleConnTimeout: schema.Int64Attribute{
+				Description: descIdleConnTimeout,
+				Optional:    true,
+			},
+			schemaKeyKeepAlive: schema.Int64Attribute{
+				Description: descKeepAlive,
+				Optional:    true,
+			},
+			schemaKeyRequestTimeout: schema.Int64Attribute{
+				Description: descRequestTimeout,
+				Optional:    true,
+			},
+			schemaKeyCompressionThreshold: schema.Int64Attribute{
+				Description: descCompression,
+				Optional:    true,
+			},
+			schemaKeyLogLevel: schema.StringAttribute{
+				Description: descLogLevel,
+				Optional:    true,
+			},
+			schemaKeyLogFormat: schema.StringAttribute{
+				Description: descLogFormat,
+				Optional:    true,
+			},
+			schemaKeyDefaultTeamIDs: schema.SetAttribute{
+				Description: descDefaultTeamIDs,
+				ElementType: types.Int64Type,
+				Optional:    true,
+			},
+			schemaKeyWireLogFile: schema.StringAttribute{
+				Description: descWireLogFile,
+				Optional:    true,
+			},
+			schemaKeySkipCredentialsValidation: schema.BoolAttribute{
+				Description: descSkipCredentials,
+				Optional:    true,
+			},
+			schemaKeyCompatibilityMode: schema.BoolAttribute{
+				Description: descCompatibilityMode,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure sets up the Rollbar API clients.  Unlike the SDK, the framework has
+// no DefaultFunc, so environment variables are consulted here.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	logLevel := stringValueOrEnv(config.LogLevel, "ROLLBAR_LOG_LEVEL", "")
+	logFormat := stringValueOrEnv(config.LogFormat, "ROLLBAR_LOG_FORMAT", "")
+	err := ConfigureLogging(logLevel, logFormat)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid logging configuration", err.Error())
+		return
+	}
+	token := stringValueOrEnv(config.Token, "ROLLBAR_API_KEY", "")
+	projectToken := stringValueOrEnv(config.ProjectToken, "ROLLBAR_PROJECT_API_KEY", "")
+	baseURL := stringValueOrEnv(config.BaseURL, "ROLLBAR_API_URL", client.DefaultBaseURL)
+	setting := func(v types.Int64, key, env string) int64 {
+		n, err := int64ValueOrEnv(v, env)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(key), "Invalid "+key, err.Error())
+			return 0
+		}
+		if n < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(key), "Invalid "+key, "Must be zero or greater")
+			return 0
+		}
+		return n
+	}
+	seconds := func(v types.Int64, key, env string) time.Duration {
+		return time.Duration(setting(v, key, env)) * time.Second
+	}
+	o := clientOptions{
+		maxRequests: int(setting(config.MaxRequests, schemaKeyMaxConcurrentRequests, "ROLLBAR_MAX_CONCURRENT_REQUESTS")),
+		cacheTTL:    seconds(config.CacheTTL, schemaKeyCacheTTL, "ROLLBAR_CACHE_TTL_SECONDS"),
+		conditional: int(setting(config.ConditionalCacheSize, schemaKeyConditionalCacheSize, "ROLLBAR_CONDITIONAL_REQUEST_CACHE_SIZE")),
+		compression: int(setting(config.CompressionThreshold, schemaKeyCompressionThreshold, "ROLLBAR_REQUEST_COMPRESSION_THRESHOLD")),
+		transport: client.TransportOptions{
+			MaxIdleConns:        int(setting(config.MaxIdleConns, schemaKeyMaxIdleConns, "ROLLBAR_MAX_IDLE_CONNECTIONS")),
+			MaxIdleConnsPerHost: int(setting(config.MaxIdleConnsPerHost, schemaKeyMaxIdleConnsPerHost, "ROLLBAR_MAX_IDLE_CONNECTIONS_PER_HOST")),
+			IdleConnTimeout:     seconds(config.IdleConnTimeout, schemaKeyIdleConnTimeout, "ROLLBAR_IDLE_CONNECTION_TIMEOUT_SECONDS"),
+			KeepAlive:           seconds(config.KeepAlive, schemaKeyKeepAlive, "ROLLBAR_KEEP_ALIVE_SECONDS"),
+			RequestTimeout:      seconds(config.RequestTimeout, schemaKeyRequestTimeout, "ROLLBAR_REQUEST_TIMEOUT_SECONDS"),
+		},
+	}
+	if !config.DefaultTeamIDs.IsNull() && !config.DefaultTeamIDs.IsUnknown() {
+		var teamIDs []int64
+		resp.Diagnostics.Append(config.DefaultTeamIDs.ElementsAs(ctx, &teamIDs, false)...)
+		for _, id := range teamIDs {
+			o.teamIDs = append(o.teamIDs, int(id))
+		}
+	}
+	wireLog, err := openWireLog(stringValueOrEnv(config.WireLogFile, "ROLLBAR_WIRE_LOG_FILE", ""))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root(schemaKeyWireLogFile), "Invalid "+schemaKeyWireLogFile, err.Error())
+	}
+	o.wireLog = wireLog
+	o.compat, err = boolValueOrEnv(config.CompatibilityMode, "ROLLBAR_COMPATIBILITY_MODE")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root(schemaKeyCompatibilityMode), "Invalid "+schemaKeyCompatibilityMode, err.Error())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	log.Debug().Msg("Configuring framework provider")
+	clients := newClients(baseURL, token, projectToken, o)
+	resp.DataSourceData = clients
+	resp.ResourceData = clients
+	resp.EphemeralResourceData = clients
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// EphemeralResources implements provider.ProviderWithEphemeralResources.
+func (p *frameworkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newProjectAccessTokenEphemeralResource,
+	}
+}
+
+// Functions implements provider.ProviderWithFunctions.
+func (p *frameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		newItemURLFunction,
+		newNormalizeScopesFunction,
+	}
+}
+
+// stringValueOrEnv returns the configured value if set, otherwise the value of
+// environment variable `env`, otherwise `fallback`.
+func stringValueOrEnv(v types.String, env, fallback string) string {
+	if !v.IsNull() && !v.IsUnknown() {
+		return v.ValueString()
+	}
+	if s, ok := os.LookupEnv(env); ok {
+		return s
+	}
+	return fallback
+}
+
+// int64ValueOrEnv returns the configured value if set, otherwise the value of
+// environment variable `env` parsed as an integer, otherwise zero.
+func int64ValueOrEnv(v types.Int64, env string) (int64, error) {
+	if !v.IsNull() && !v.IsUnknown() {
+		return v.ValueInt64(), nil
+	}
+	s, ok := os.LookupEnv(env)
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s (%q) must be an integer", env, s)
+	}
+	return n, nil
+}
+
+// boolValueOrEnv returns the configured value if set, otherwise the value of
+// environment variable `env` parsed as a boolean, otherwise false.
+func boolValueOrEnv(v types.Bool, env string) (bool, error) {
+	if !v.IsNull() && !v.IsUnknown() {
+		return v.ValueBool(), nil
+	}
+	s, ok := os.LookupEnv(env)
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("environment variable %s (%q) must be a boolean", env, s)
+	}
+	return b, nil
+}
@@ -23,14 +23,19 @@
 package rollbar
 
 import (
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"os"
 	"runtime"
@@ -58,8 +63,8 @@ func TestMain(m *testing.M) {
 // AccSuite is the acceptance testing suite.
 type AccSuite struct {
 	suite.Suite
-	provider  *schema.Provider
-	providers map[string]*schema.Provider
+	provider          *schema.Provider
+	providerFactories map[string]func() (tfprotov5.ProviderServer, error)
 
 	// The following variables are populated before each test by SetupTest():
 	randName string // Name of a Rollbar project
@@ -74,8 +79,24 @@ func (s *AccSuite) SetupSuite() {
 
 	// Setup testing
 	s.provider = Provider()
-	s.providers = map[string]*schema.Provider{
-		"rollbar": s.provider,
+	s.providerFactories = protoV5ProviderFactories(s.provider)
+}
+
+// protoV5ProviderFactories returns provider factories serving p muxed with the
+// framework provider, as main() does.  Reusing p lets tests reach its
+// configured API clients through p.Meta().
+func protoV5ProviderFactories(p *schema.Provider) map[string]func() (tfprotov5.ProviderServer, error) {
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"rollbar": func() (tfprotov5.ProviderServer, error) {
+			muxServer, err := tf5muxserver.NewMuxServer(context.Background(),
+				WithReplacementNotes(p.GRPCProvider),
+				providerserver.NewProtocol5(NewFrameworkProvider()),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return muxServer.ProviderServer(), nil
+		},
 	}
 }
 
@@ -194,3 +215,67 @@ func (s *AccSuite) getResourceAttrIntSlice(ts *terraform.State, resourceName str
 	}
 	return value, nil
 }
+
+// TestProvider checks the provider and all its resources pass the Terraform SDK's
+// internal validation.
+func TestProvider(t *testing.T) {
+	err := Provider().InternalValidate()
+	assert.Nil(t, err)
+}
+
+// TestMuxProviderSchema checks the SDK and framework providers can be muxed,
+// which requires their provider schemas to be identical.
+func TestMuxProviderSchema(t *testing.T) {
+	ctx := context.Background()
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		Provider().GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	)
+	assert.Nil(t, err)
+	resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Diagnostics)
+	assert.Contains(t, resp.EphemeralResourceSchemas, "rollbar_project_access_token")
+	assert.Contains(t, resp.Functions, "item_url")
+	assert.Contains(t, resp.Functions, "normalize_scopes")
+
+	identities, err := muxServer.ProviderServer().GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	assert.Nil(t, err)
+	assert.Empty(t, identities.Diagnostics)
+	assert.Contains(t, identities.IdentitySchemas, "rollbar_team_user")
+}
+
+// TestSharedLimiter checks that clients of the same provider configuration, such
+// as those of the SDK and framework providers, share one Limiter.
+func TestSharedLimiter(t *testing.T) {
+	const u = "https://api.rollbar.com"
+	assert.Nil(t, sharedLimiter(u, "token", "", 0))
+	l := sharedLimiter(u, "token", "", 3)
+	assert.NotNil(t, l)
+	assert.Same(t, l, sharedLimiter(u, "token", "", 3))
+	assert.NotSame(t, l, sharedLimiter(u, "token", "", 4))
+	assert.NotSame(t, l, sharedLimiter(u, "other-token", "", 3))
+	assert.NotSame(t, l, sharedLimiter(u, "token", "project-token", 3))
+	assert.NotSame(t, l, sharedLimiter("https://rollbar.example.com", "token", "", 3))
+}
+
+// TestImportNumericID tests import of resources identified by a numeric ID.
+func TestImportNumericID(t *testing.T) {
+	d := resourceTeam().TestResourceData()
+	d.SetId("689493")
+	result, err := importNumericID(context.Background(), d, nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	d.SetId("not-a-number")
+	_, err = importNumericID(context.Background(), d, nil)
+	assert.NotNil(t, err)
+}
+
+// TestQuotedList tests formatting lists of values for descriptions.
+func TestQuotedList(t *testing.T) {
+	assert.Equal(t, "", quotedList(nil, "or"))
+	assert.Equal(t, `"a"`, quotedList([]string{"a"}, "or"))
+	assert.Equal(t, `"a" or "b"`, quotedList([]string{"a", "b"}, "or"))
+	assert.Equal(t, `"a", "b", and "c"`, quotedList([]string{"a", "b", "c"}, "and"))
+}
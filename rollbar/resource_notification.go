@@ -25,39 +25,104 @@ package rollbar
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-var configMap = map[string][]string{"email": {"users", "teams"},
+var configMap = map[string][]string{"email": {"users", "teams", "team_ids"},
 	"slack":     {"message_template", "channel", "show_message_buttons"},
 	"pagerduty": {"service_key"}}
 
+// notificationTriggerOccurrenceRate is the trigger evaluated against a `rate`
+// filter.
+const notificationTriggerOccurrenceRate = "occurrence_rate"
+
+// notificationFilterOperations maps each notification rule filter type to the
+// operations it is known to accept.  The `rate` filter takes a period and count
+// rather than an operation.  This vocabulary, like the levels and rate periods
+// below, is not taken from a published API contract, so
+// validateNotificationFilters only warns about values outside it.
+var notificationFilterOperations = map[string][]string{
+	"environment": {"eq", "neq"},
+	"level":       {"eq", "gte"},
+	"title":       {"within", "nwithin", "regex", "nregex"},
+	"filename":    {"within", "nwithin", "regex", "nregex"},
+	"context":     {"startswith", "eq", "neq"},
+	"method":      {"within", "nwithin", "regex", "nregex"},
+	"framework":   {"eq"},
+	"path":        {"eq", "neq", "within", "nwithin", "regex", "nregex", "exists", "nexists"},
+	"rate":        {},
+}
+
+// notificationItemFilters lists the filter types accepted by triggers that
+// evaluate individual items.
+var notificationItemFilters = []string{"environment", "level", "title", "filename", "context", "method", "framework", "path"}
+
+// notificationTriggerFilters maps each known notification rule trigger to
+// the filter types it is known to accept.
+var notificationTriggerFilters = map[string][]string{
+	"new_item":                        notificationItemFilters,
+	"reactivated_item":                notificationItemFilters,
+	"resolved_item":                   notificationItemFilters,
+	"exp_repeat_item":                 notificationItemFilters,
+	notificationTriggerOccurrenceRate: append([]string{"rate"}, notificationItemFilters...),
+	"deploy":                          {"environment"},
+}
+
+// notificationLevels lists the values known to be accepted by a `level` filter.
+var notificationLevels = []string{"debug", "info", "warning", "error", "critical"}
+
+// notificationRatePeriods lists the periods, in seconds, known to be accepted by
+// a `rate` filter.
+var notificationRatePeriods = []int{60, 300, 1800, 3600, 86400}
+
+// CustomNotificationImport imports a rollbar_notification resource from an ID
+// of the form CHANNEL,NOTIFICATION-ID.
 func CustomNotificationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	splitID := strings.Split(d.Id(), ComplexImportSeparator)
-	if len(splitID) > 1 {
-		mustSet(d, "channel", splitID[0])
-		d.SetId(splitID[1])
+	if len(splitID) != 2 || splitID[0] == "" || splitID[1] == "" {
+		return nil, fmt.Errorf("unexpected format of ID (%q), expected CHANNEL%sNOTIFICATION-ID", d.Id(), ComplexImportSeparator)
+	}
+	if _, err := strconv.Atoi(splitID[1]); err != nil {
+		return nil, fmt.Errorf("unexpected format of ID (%q), notification ID must be numeric", d.Id())
 	}
+	mustSet(d, "channel", splitID[0])
+	d.SetId(splitID[1])
 	return []*schema.ResourceData{d}, nil
 }
 
+// notificationIdentity identifies a rollbar_notification in import blocks.
+var notificationIdentity = resourceIdentity{separator: ComplexImportSeparator, attrs: []identityAttr{
+	{name: "channel", attr: "channel", typ: schema.TypeString, description: "The notification's channel"},
+	{name: "id", typ: schema.TypeInt, description: "The notification rule's numeric ID"},
+}}
+
 // resourceNotification constructs a resource representing a Rollbar notification.
 func resourceNotification() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceNotificationCreate,
 		UpdateContext: resourceNotificationUpdate,
 		ReadContext:   resourceNotificationRead,
 		DeleteContext: resourceNotificationDelete,
 
+		CustomizeDiff:                  resourceNotificationCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{validateNotificationRules},
+
 		Importer: &schema.ResourceImporter{
 			StateContext: CustomNotificationImport,
 		},
 
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
 		Schema: map[string]*schema.Schema{
 			// Required
 			"channel": {
@@ -93,7 +158,13 @@ func resourceNotification() *schema.Resource {
 										Optional:    true,
 									},
 									"value": {
-										Description: "Value",
+										Description:      "Value",
+										Type:             schema.TypeString,
+										Optional:         true,
+										DiffSuppressFunc: notificationFilterValueDiffSuppress,
+									},
+									"path": {
+										Description: "Path of the occurrence field to compare (path filters only)",
 										Type:        schema.TypeString,
 										Optional:    true,
 									},
@@ -115,6 +186,18 @@ func resourceNotification() *schema.Resource {
 					},
 				},
 			},
+			"service_key_wo": {
+				Description: "Service key (pagerduty).  Write-only: sent to the API but never stored in state.  Requires Terraform 1.11 or later.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+			},
+			"service_key_wo_version": {
+				Description: "Version of `service_key_wo`.  Must be set when `service_key_wo` is used; change it to send an updated service key to the API.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
 			"config": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -132,6 +215,12 @@ func resourceNotification() *schema.Resource {
 							Description: "Teams (email)",
 							Elem:        &schema.Schema{Type: schema.TypeString},
 						},
+						"team_ids": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "IDs of teams to notify (email).  Resolved to team names with the account access token.",
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+						},
 						"message_template": {
 							Description: "Message template (slack)",
 							Type:        schema.TypeString,
@@ -157,7 +246,7 @@ func resourceNotification() *schema.Resource {
 				},
 			},
 		},
-	}
+	}, notificationIdentity)
 }
 
 func find(slice []string, val string) bool {
@@ -191,12 +280,347 @@ func parseRule(d *schema.ResourceData) (trigger string, filters interface{}) {
 		if key == "trigger" {
 			trigger = value.(string)
 		} else {
-			filters = value
+			filters = cleanFilters(value)
 		}
 	}
 	return trigger, filters
 }
 
+// cleanFilters removes the `path` attribute from filters that do not use it.
+func cleanFilters(filters interface{}) interface{} {
+	list, ok := filters.([]interface{})
+	if !ok {
+		return filters
+	}
+	for _, f := range list {
+		filter, ok := f.(map[string]interface{})
+		if ok && filter["path"] == "" {
+			delete(filter, "path")
+		}
+	}
+	return list
+}
+
+// notificationCaseInsensitiveFilters lists the filter types whose values the
+// API lowercases.
+var notificationCaseInsensitiveFilters = []string{"environment"}
+
+// notificationFilterValueDiffSuppress suppresses differences in case between
+// values of filters the API lowercases.
+func notificationFilterValueDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	typ, _ := d.Get(strings.TrimSuffix(k, "value") + "type").(string)
+	return find(notificationCaseInsensitiveFilters, typ) && strings.EqualFold(old, new)
+}
+
+// notificationFilterKey returns a key identifying a filter up to the API's
+// normalization of it.
+func notificationFilterKey(filter map[string]interface{}) string {
+	str := func(key string) string {
+		if v, ok := filter[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	num := func(key string) float64 {
+		switch v := filter[key].(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		}
+		return 0
+	}
+	typ := str("type")
+	value := str("value")
+	if find(notificationCaseInsensitiveFilters, typ) {
+		value = strings.ToLower(value)
+	}
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%v\x00%v", typ, str("operation"), value, str("path"), num("period"), num("count"))
+}
+
+// alignNotificationFilters returns the filters of a rule read from the API in
+// the order of the rule's prior filters, keeping their prior values where the
+// API only normalized them, so the normalization is not reported as a change
+// made outside Terraform.  Filters not matching a prior filter come last.
+func alignNotificationFilters(prior, remote []interface{}) []interface{} {
+	used := make([]bool, len(remote))
+	aligned := make([]interface{}, 0, len(remote))
+	for _, p := range prior {
+		pf, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for i, r := range remote {
+			rf, ok := r.(map[string]interface{})
+			if used[i] || !ok || notificationFilterKey(pf) != notificationFilterKey(rf) {
+				continue
+			}
+			used[i] = true
+			if v, ok := pf["value"]; ok && rf["value"] != nil {
+				rf["value"] = v
+			}
+			aligned = append(aligned, rf)
+			break
+		}
+	}
+	for i, r := range remote {
+		if !used[i] {
+			aligned = append(aligned, r)
+		}
+	}
+	return aligned
+}
+
+// resourceNotificationCustomizeDiff validates the filters of each notification
+// rule against its trigger, and checks that no team is notified both by name
+// and by ID.  Warnings are reported by validateNotificationRules
+// instead, as CustomizeDiff can only fail.
+func resourceNotificationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("config") {
+		for _, c := range d.Get("config").(*schema.Set).List() {
+			if config, ok := c.(map[string]interface{}); ok {
+				if err := notificationCheckTeamOverlap(ctx, m, config); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if !d.NewValueKnown("rule") {
+		return nil
+	}
+	for _, r := range d.Get("rule").(*schema.Set).List() {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trigger, _ := rule["trigger"].(string)
+		filters, _ := rule["filters"].([]interface{})
+		_, err := validateNotificationFilters(trigger, filters)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notificationFilterError is an error in filter `index` of a notification
+// rule.
+type notificationFilterError struct {
+	index int
+	msg   string
+}
+
+func (e notificationFilterError) Error() string {
+	return fmt.Sprintf("filter %d: %s", e.index, e.msg)
+}
+
+// notificationFilterErrorf returns a notificationFilterError for filter
+// `index`.
+func notificationFilterErrorf(index int, format string, a ...interface{}) error {
+	return notificationFilterError{index: index, msg: fmt.Sprintf(format, a...)}
+}
+
+// validateNotificationRules is a schema.ValidateRawResourceConfigFunc checking
+// the filters of each rule, and of any `rules_json`, against its trigger, with
+// diagnostics pointing at the invalid rule or filter.  Rules with values unknown
+// until apply are left to CustomizeDiff.  Rules with a trigger outside the
+// vocabulary above are not checked.
+func validateNotificationRules(_ context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if req.RawConfig.Type().HasAttribute("rules_json") {
+		if v := req.RawConfig.GetAttr("rules_json"); v.IsKnown() && !v.IsNull() {
+			// Invalid JSON is reported by the attribute's ValidateFunc
+			rules, _ := parseNotificationsRulesJSON(v.AsString())
+			for i, rule := range rules {
+				if _, ok := notificationTriggerFilters[rule.Trigger]; !ok {
+					continue
+				}
+				warnings, err := validateNotificationFilters(rule.Trigger, rule.Filters)
+				resp.Diagnostics = append(resp.Diagnostics, notificationRuleDiagnostics(warnings, err, func(err error) (cty.Path, string) {
+					return cty.GetAttrPath("rules_json"), fmt.Sprintf("rule %d: %v", i, err)
+				})...)
+			}
+		}
+	}
+	rules := req.RawConfig.GetAttr("rule")
+	if rules.IsNull() || !rules.IsKnown() {
+		return
+	}
+	for it := rules.ElementIterator(); it.Next(); {
+		key, rule := it.Element()
+		if rule.IsNull() || !rule.IsKnown() {
+			continue
+		}
+		rulePath := cty.GetAttrPath("rule").Index(key)
+		trigger := rule.GetAttr("trigger")
+		filters, known := ctyNotificationFilters(rule.GetAttr("filters"))
+		if !known || !trigger.IsKnown() || trigger.IsNull() {
+			continue
+		}
+		if _, ok := notificationTriggerFilters[trigger.AsString()]; !ok {
+			continue
+		}
+		warnings, err := validateNotificationFilters(trigger.AsString(), filters)
+		resp.Diagnostics = append(resp.Diagnostics, notificationRuleDiagnostics(warnings, err, func(err error) (cty.Path, string) {
+			var filterErr notificationFilterError
+			if errors.As(err, &filterErr) {
+				return rulePath.GetAttr("filters").IndexInt(filterErr.index), err.Error()
+			}
+			return rulePath, err.Error()
+		})...)
+	}
+}
+
+// notificationRuleDiagnostics converts the warnings and error returned by
+// validateNotificationFilters into diagnostics, with the attribute path and
+// message for each given by `describe`.
+func notificationRuleDiagnostics(warnings []error, err error, describe func(error) (cty.Path, string)) diag.Diagnostics {
+	var diags diag.Diagnostics
+	add := func(severity diag.Severity, summary string, err error, suffix string) {
+		path, msg := describe(err)
+		diags = append(diags, diag.Diagnostic{
+			Severity:      severity,
+			Summary:       summary,
+			Detail:        strings.ToUpper(msg[:1]) + msg[1:] + suffix,
+			AttributePath: path,
+		})
+	}
+	for _, w := range warnings {
+		add(diag.Warning, "Unrecognized notification rule filter", w, ". The filter is sent to the Rollbar API unchanged, which may accept it.")
+	}
+	if err != nil {
+		add(diag.Error, "Invalid notification rule", err, "")
+	}
+	return diags
+}
+
+// ctyNotificationFilters converts the `filters` of a rule in raw configuration
+// to the form validated by validateNotificationFilters, or returns false if
+// any of them is unknown.
+func ctyNotificationFilters(filters cty.Value) ([]interface{}, bool) {
+	if filters.IsNull() {
+		return nil, true
+	}
+	if !filters.IsWhollyKnown() {
+		return nil, false
+	}
+	list := []interface{}{}
+	for it := filters.ElementIterator(); it.Next(); {
+		_, f := it.Element()
+		filter := map[string]interface{}{}
+		if f.IsNull() {
+			continue
+		}
+		for _, key := range []string{"type", "operation", "value", "path"} {
+			if v := f.GetAttr(key); !v.IsNull() {
+				filter[key] = v.AsString()
+			}
+		}
+		for _, key := range []string{"period", "count"} {
+			if v := f.GetAttr(key); !v.IsNull() {
+				filter[key], _ = v.AsBigFloat().Float64()
+			}
+		}
+		list = append(list, filter)
+	}
+	return list, true
+}
+
+// validateNotificationFilters checks that the filters of a notification rule
+// are well formed, returning an error if not.  It also returns warnings, as
+// notificationFilterErrors or plain errors, for types, operations, levels and
+// rate periods outside the vocabulary above, or not known to be supported by
+// the rule's trigger.  Rollbar publishes no API contract listing them, so the
+// vocabulary may be incomplete, and such filters are still sent to the API,
+// which has the final say.  Filters of a trigger outside the vocabulary are not
+// checked at all.
+func validateNotificationFilters(trigger string, filters []interface{}) (warnings []error, err error) {
+	triggerFilters, knownTrigger := notificationTriggerFilters[trigger]
+	if trigger != "" && !knownTrigger {
+		return nil, nil
+	}
+	warn := func(index int, format string, a ...interface{}) {
+		warnings = append(warnings, notificationFilterErrorf(index, format, a...))
+	}
+	rateFilters := 0
+	for i, f := range filters {
+		filter, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typ, _ := filter["type"].(string)
+		operation, _ := filter["operation"].(string)
+		value, _ := filter["value"].(string)
+		operations, known := notificationFilterOperations[typ]
+		switch {
+		case typ == "":
+			// Unknown until apply
+			continue
+		case !known:
+			types := make([]string, 0, len(notificationFilterOperations))
+			for t := range notificationFilterOperations {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			warn(i, "unrecognized type %q, expected %s", typ, quotedList(types, "or"))
+			continue
+		case knownTrigger && typ != "rate" && !find(triggerFilters, typ):
+			warn(i, "%s filters are not known to be supported by the %q trigger, expected %s", typ, trigger, quotedList(triggerFilters, "or"))
+		}
+		if typ == "rate" {
+			rateFilters++
+			if trigger != notificationTriggerOccurrenceRate {
+				warn(i, "rate filters are only known to be supported by the %q trigger", notificationTriggerOccurrenceRate)
+			}
+			period, _ := filter["period"].(float64)
+			if !validNotificationRatePeriod(period) {
+				warn(i, "unrecognized rate period %v, expected one of %v seconds (%s minutes)", period, notificationRatePeriods, notificationRatePeriodMinutes())
+			}
+			count, _ := filter["count"].(float64)
+			if count < 1 {
+				return warnings, notificationFilterErrorf(i, "rate count must be at least 1")
+			}
+			if count != math.Trunc(count) {
+				return warnings, notificationFilterErrorf(i, "rate count must be a whole number, got %v", count)
+			}
+			continue
+		}
+		if operation != "" && !find(operations, operation) {
+			warn(i, "unrecognized operation %q for %s filter, expected %s", operation, typ, quotedList(operations, "or"))
+		}
+		if typ == "level" && !find(notificationLevels, value) {
+			warn(i, "unrecognized level %q, expected %s", value, quotedList(notificationLevels, "or"))
+		}
+		if path, _ := filter["path"].(string); typ == "path" && path == "" {
+			return warnings, notificationFilterErrorf(i, "path filters require a path")
+		}
+	}
+	if trigger == notificationTriggerOccurrenceRate && rateFilters != 1 {
+		warnings = append(warnings, fmt.Errorf("the %q trigger is expected to have exactly one rate filter", notificationTriggerOccurrenceRate))
+	}
+	return warnings, nil
+}
+
+// validNotificationRatePeriod returns true if period is accepted by a `rate`
+// filter.
+func validNotificationRatePeriod(period float64) bool {
+	for _, p := range notificationRatePeriods {
+		if period == float64(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationRatePeriodMinutes lists the periods accepted by a `rate` filter
+// in minutes, for error messages.
+func notificationRatePeriodMinutes() string {
+	minutes := make([]string, len(notificationRatePeriods))
+	for i, p := range notificationRatePeriods {
+		minutes[i] = strconv.Itoa(p / 60)
+	}
+	return strings.Join(minutes, ", ")
+}
+
 func cleanConfig(channel string, config map[string]interface{}) map[string]interface{} {
 	returnSetMap := map[string]interface{}{}
 	for key, v := range config {
@@ -207,22 +631,158 @@ func cleanConfig(channel string, config map[string]interface{}) map[string]inter
 	return returnSetMap
 }
 
+// notificationTeamNames resolves team IDs in an email notification config to
+// the team names the API expects.  With skipDeleted, teams that no longer exist
+// are left out, and the IDs of the teams found are returned along with their
+// names; otherwise a missing team is an error.
+func notificationTeamNames(ctx context.Context, m interface{}, teamIDs []interface{}, skipDeleted bool) ([]interface{}, []string, error) {
+	if len(teamIDs) == 0 {
+		return nil, nil, nil
+	}
+	c, err := clientFor(ctx, m, "rollbar_team", "read")
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]interface{}, 0, len(teamIDs))
+	names := make([]string, 0, len(teamIDs))
+	for _, id := range teamIDs {
+		t, err := c.ReadTeam(id.(int))
+		if skipDeleted && errors.Is(err, client.ErrNotFound) {
+			log.Warn().Int("team_id", id.(int)).Msg("Team notified by ID no longer exists")
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading team %d: %w", id, err)
+		}
+		ids = append(ids, id)
+		names = append(names, t.Name)
+	}
+	return ids, names, nil
+}
+
+// notificationResolveTeamIDs replaces the `team_ids` of a notification config
+// with the teams' names, merged into `teams`.
+func notificationResolveTeamIDs(ctx context.Context, m interface{}, config map[string]interface{}) diag.Diagnostics {
+	teamIDs, _ := config["team_ids"].([]interface{})
+	delete(config, "team_ids")
+	_, names, err := notificationTeamNames(ctx, m, teamIDs, false)
+	if err != nil {
+		return diagFromErr(err, "config")
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	teams, _ := config["teams"].([]interface{})
+	for _, name := range names {
+		if !findInterface(teams, name) {
+			teams = append(teams, name)
+		}
+	}
+	config["teams"] = teams
+	return nil
+}
+
+// notificationUnresolveTeamIDs moves the teams of a notification config read
+// from the API that were configured by ID back from `teams` to `team_ids`.
+// Teams that have since been deleted are dropped from `team_ids`, so the plan
+// shows them as drift rather than Read failing.
+func notificationUnresolveTeamIDs(ctx context.Context, m interface{}, teamIDs []interface{}, config map[string]interface{}) error {
+	ids, names, err := notificationTeamNames(ctx, m, teamIDs, true)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	teams, _ := config["teams"].([]interface{})
+	remaining := []interface{}{}
+	for _, t := range teams {
+		name, _ := t.(string)
+		if !find(names, name) {
+			remaining = append(remaining, t)
+		}
+	}
+	config["teams"] = remaining
+	config["team_ids"] = ids
+	return nil
+}
+
+// notificationCheckTeamOverlap returns an error if a team in the `team_ids` of
+// a notification config is also listed by name in its `teams`.  Both resolve to
+// the same recipient, which is read back only once, so the configuration would
+// never converge.  Values unknown until apply are not checked.
+func notificationCheckTeamOverlap(ctx context.Context, m interface{}, config map[string]interface{}) error {
+	teams, _ := config["teams"].([]interface{})
+	teamIDs, _ := config["team_ids"].([]interface{})
+	if len(teams) == 0 || len(teamIDs) == 0 || findInterface(teamIDs, 0) || findInterface(teams, "") {
+		return nil
+	}
+	// Deleted teams are reported when creating or updating the rule
+	ids, names, err := notificationTeamNames(ctx, m, teamIDs, true)
+	if err != nil {
+		return err
+	}
+	for i, name := range names {
+		if findInterface(teams, name) {
+			return fmt.Errorf("team %q is listed in both teams and team_ids (as %d), list it in only one of them", name, ids[i])
+		}
+	}
+	return nil
+}
+
+// findInterface returns true if slice contains val.
+func findInterface(slice []interface{}, val interface{}) bool {
+	for _, item := range slice {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationWriteOnlyConfig merges write-only secrets from the raw Terraform
+// configuration into a notification config.  Write-only values are only
+// available during create and update, never from state.
+func notificationWriteOnlyConfig(d *schema.ResourceData, channel string, config map[string]interface{}) diag.Diagnostics {
+	serviceKey, diags := getWriteOnlyString(d, "service_key_wo")
+	if diags.HasError() || serviceKey == "" {
+		return diags
+	}
+	if !find(configMap[channel], "service_key") {
+		return diag.Errorf("service_key_wo is not supported for channel %q", channel)
+	}
+	if d.Get("service_key_wo_version").(int) == 0 {
+		return diag.Errorf("service_key_wo_version must be set when service_key_wo is used")
+	}
+	if v, _ := config["service_key"].(string); v != "" {
+		return diag.Errorf("only one of service_key_wo and config.service_key may be set")
+	}
+	config["service_key"] = serviceKey
+	return nil
+}
+
 func resourceNotificationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	trigger, filters := parseRule(d)
 	channel := d.Get("channel").(string)
 	config := parseSet("config", d)
 	config = cleanConfig(channel, config)
+	if diags := notificationWriteOnlyConfig(d, channel, config); diags.HasError() {
+		return diags
+	}
+	if diags := notificationResolveTeamIDs(ctx, m, config); diags.HasError() {
+		return diags
+	}
 	l := log.With().Str("channel", channel).Logger()
 
 	l.Info().Msg("Creating rollbar_notification resource")
 
-	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_notification", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	n, err := c.CreateNotification(channel, filters, trigger, config)
 	if err != nil {
 		l.Err(err).Send()
 		d.SetId("") // removing from the state
-		return diag.FromErr(err)
+		return diagFromErr(err, "channel", "rule", "config")
 	}
 	l = l.With().Int("id", n.ID).Logger()
 
@@ -239,24 +799,32 @@ func resourceNotificationUpdate(ctx context.Context, d *schema.ResourceData, m i
 	channel := d.Get("channel").(string)
 	config := parseSet("config", d)
 	config = cleanConfig(channel, config)
+	if diags := notificationWriteOnlyConfig(d, channel, config); diags.HasError() {
+		return diags
+	}
+	if diags := notificationResolveTeamIDs(ctx, m, config); diags.HasError() {
+		return diags
+	}
 	l := log.With().Str("channel", channel).Logger()
 
-	l.Info().Msg("Creating rollbar_notification resource")
-	l.Print(config)
+	l.Info().Msg("Updating rollbar_notification resource")
 
-	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_notification", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	n, err := c.UpdateNotification(id, channel, filters, trigger, config)
 
 	if err != nil {
 		l.Err(err).Send()
 		d.SetId("") // removing from the state
-		return diag.FromErr(err)
+		return diagFromErr(err, "channel", "rule", "config")
 	}
 	if n.ID != id {
 		err = errors.New("IDs are not equal")
 		l.Err(err).Send()
 		d.SetId("") // removing from the state
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	l = l.With().Int("id", n.ID).Logger()
 
@@ -315,7 +883,10 @@ func resourceNotificationRead(ctx context.Context, d *schema.ResourceData, m int
 		Int("id", id).
 		Logger()
 	l.Info().Msg("Reading rollbar_notification resource")
-	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_notification", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	n, err := c.ReadNotification(id, channel)
 	if err == client.ErrNotFound {
 		d.SetId("")
@@ -324,11 +895,24 @@ func resourceNotificationRead(ctx context.Context, d *schema.ResourceData, m int
 	}
 	if err != nil {
 		l.Err(err).Msg("error reading rollbar_notification resource")
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
+	// A service key supplied through service_key_wo must never be written
+	// back to state.
+	if d.Get("service_key_wo_version").(int) != 0 {
+		delete(n.Config, "service_key")
+	}
+	teamIDs, _ := parseSet("config", d)["team_ids"].([]interface{})
+	err = notificationUnresolveTeamIDs(ctx, m, teamIDs, n.Config)
+	if err != nil {
+		l.Err(err).Msg("error reading rollbar_notification resource")
+		return diagFromErr(err)
+	}
+	_, priorFilters := parseRule(d)
+	prior, _ := priorFilters.([]interface{})
 	mustSet(d, "config", flattenConfig(n.Config))
-	mustSet(d, "rule", flattenRule(n.Filters, n.Trigger))
+	mustSet(d, "rule", flattenRule(alignNotificationFilters(prior, n.Filters), n.Trigger))
 	l.Debug().Msg("Successfully read rollbar_notification resource")
 	return nil
 }
@@ -338,11 +922,14 @@ func resourceNotificationDelete(ctx context.Context, d *schema.ResourceData, m i
 	channel := d.Get("channel").(string)
 	l := log.With().Int("id", id).Logger()
 	l.Info().Msg("Deleting rollbar_notification resource")
-	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
-	err := c.DeleteNotification(id, channel)
+	c, err := clientFor(ctx, m, "rollbar_notification", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = c.DeleteNotification(id, channel)
 	if err != nil {
 		l.Err(err).Msg("Error deleting rollbar_notification resource")
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	l.Debug().Msg("Successfully deleted rollbar_notification resource")
 	return nil
@@ -0,0 +1,362 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccNotificationEmail tests creating, updating and importing an email
+// `rollbar_notification` rule.  Notification rules belong to the project of
+// ROLLBAR_PROJECT_API_KEY, so the test is skipped unless it is set.
+func (s *AccSuite) TestAccNotificationEmail() {
+	if os.Getenv("ROLLBAR_PROJECT_API_KEY") == "" {
+		s.T().Skip("ROLLBAR_PROJECT_API_KEY must be set to test notification rules")
+	}
+	rn := "rollbar_notification.test"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_notification" "test" {
+			channel = "email"
+			rule {
+				trigger = "occurrence_rate"
+				filters {
+					type   = "rate"
+					period = %d
+					count  = 10
+				}
+			}
+			config {
+				users = ["terraform-provider-test@rollbar.com"]
+			}
+		}
+	`
+	resource.Test(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tmpl, 300),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("channel"), knownvalue.StringExact("email")),
+				},
+			},
+			{
+				Config: fmt.Sprintf(tmpl, 3600),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				ResourceName:        rn,
+				ImportState:         true,
+				ImportStateIdPrefix: "email" + ComplexImportSeparator,
+				ImportStateVerify:   true,
+			},
+		},
+	})
+}
+
+// TestValidateNotificationFilters tests validation of the filters of a
+// `rollbar_notification` rule against its trigger.
+func TestValidateNotificationFilters(t *testing.T) {
+	rate := map[string]interface{}{"type": "rate", "period": float64(300), "count": float64(10)}
+	env := map[string]interface{}{"type": "environment", "operation": "eq", "value": "production"}
+	level := map[string]interface{}{"type": "level", "operation": "gte", "value": "error"}
+	path := map[string]interface{}{"type": "path", "operation": "eq", "path": "body.foo", "value": "bar"}
+
+	valid := []struct {
+		trigger string
+		filters []interface{}
+	}{
+		{"occurrence_rate", []interface{}{rate}},
+		{"occurrence_rate", []interface{}{rate, env, level}},
+		{"new_item", []interface{}{env, level, path}},
+		{"new_item", nil},
+		{"reactivated_item", []interface{}{env, path}},
+		{"exp_repeat_item", []interface{}{level}},
+		{"resolved_item", []interface{}{env, level}},
+		{"deploy", []interface{}{env}},
+		{"deploy", []interface{}{map[string]interface{}{"type": "environment", "operation": "neq", "value": "staging"}}},
+		{"deploy", nil},
+		{"", []interface{}{env}},
+	}
+	for _, tc := range valid {
+		warnings, err := validateNotificationFilters(tc.trigger, tc.filters)
+		assert.Nil(t, err, tc)
+		assert.Empty(t, warnings, tc)
+	}
+
+	// Values outside the known vocabulary are only warned about
+	unrecognized := []struct {
+		trigger string
+		filters []interface{}
+	}{
+		{"occurrence_rate", nil},
+		{"occurrence_rate", []interface{}{rate, rate}},
+		{"new_item", []interface{}{rate}},
+		{"occurrence_rate", []interface{}{map[string]interface{}{"type": "rate", "period": float64(42), "count": float64(10)}}},
+		{"new_item", []interface{}{map[string]interface{}{"type": "avocado"}}},
+		{"new_item", []interface{}{map[string]interface{}{"type": "environment", "operation": "gte"}}},
+		{"new_item", []interface{}{map[string]interface{}{"type": "level", "operation": "eq", "value": "loud"}}},
+		{"deploy", []interface{}{level}},
+		{"deploy", []interface{}{rate}},
+		{"resolved_item", []interface{}{rate}},
+		{"deploy", []interface{}{path}},
+	}
+	for _, tc := range unrecognized {
+		warnings, err := validateNotificationFilters(tc.trigger, tc.filters)
+		assert.Nil(t, err, tc)
+		assert.NotEmpty(t, warnings, tc)
+	}
+
+	invalid := []struct {
+		trigger string
+		filters []interface{}
+	}{
+		{"occurrence_rate", []interface{}{map[string]interface{}{"type": "rate", "period": float64(300), "count": float64(0)}}},
+		{"new_item", []interface{}{map[string]interface{}{"type": "path", "operation": "eq", "path": ""}}},
+		{"occurrence_rate", []interface{}{map[string]interface{}{"type": "rate", "period": float64(300), "count": float64(2.5)}}},
+	}
+	for _, tc := range invalid {
+		_, err := validateNotificationFilters(tc.trigger, tc.filters)
+		assert.NotNil(t, err, tc)
+	}
+
+	// Triggers outside the vocabulary are not checked
+	warnings, err := validateNotificationFilters("new_items", []interface{}{map[string]interface{}{"type": "rate", "count": float64(0)}})
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+
+	warnings, err = validateNotificationFilters("occurrence_rate", []interface{}{map[string]interface{}{"type": "rate", "period": float64(120), "count": float64(10)}})
+	assert.Nil(t, err)
+	require.Len(t, warnings, 1)
+	assert.EqualError(t, warnings[0], "filter 0: unrecognized rate period 120, expected one of [60 300 1800 3600 86400] seconds (1, 5, 30, 60, 1440 minutes)")
+}
+
+func TestCleanFilters(t *testing.T) {
+	filters := []interface{}{
+		map[string]interface{}{"type": "environment", "path": ""},
+		map[string]interface{}{"type": "path", "path": "body.foo"},
+	}
+	expected := []interface{}{
+		map[string]interface{}{"type": "environment"},
+		map[string]interface{}{"type": "path", "path": "body.foo"},
+	}
+	assert.Equal(t, expected, cleanFilters(filters))
+}
+
+// TestOfflineNotificationTeamIDs tests notifying teams by ID, which the API
+// only accepts by name.
+func TestOfflineNotificationTeamIDs(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+
+	config := map[string]interface{}{
+		"teams":    []interface{}{"Everyone"},
+		"team_ids": []interface{}{2},
+	}
+	require.False(t, notificationResolveTeamIDs(context.Background(), m, config).HasError())
+	assert.Equal(t, map[string]interface{}{"teams": []interface{}{"Everyone", "Owners"}}, config)
+
+	// Reading back, teams configured by ID stay in team_ids
+	require.NoError(t, notificationUnresolveTeamIDs(context.Background(), m, []interface{}{2}, config))
+	assert.Equal(t, map[string]interface{}{
+		"teams":    []interface{}{"Everyone"},
+		"team_ids": []interface{}{2},
+	}, config)
+
+	config = map[string]interface{}{"team_ids": []interface{}{404}}
+	assert.True(t, notificationResolveTeamIDs(context.Background(), m, config).HasError())
+
+	// A deleted team is dropped from team_ids, so the plan shows the drift
+	config = map[string]interface{}{"teams": []interface{}{"Everyone", "Owners"}}
+	require.NoError(t, notificationUnresolveTeamIDs(context.Background(), m, []interface{}{2, 404}, config))
+	assert.Equal(t, map[string]interface{}{
+		"teams":    []interface{}{"Everyone"},
+		"team_ids": []interface{}{2},
+	}, config)
+
+	// A team listed both by name and by ID never converges
+	err := notificationCheckTeamOverlap(context.Background(), m, map[string]interface{}{
+		"teams":    []interface{}{"Owners"},
+		"team_ids": []interface{}{2},
+	})
+	assert.EqualError(t, err, `team "Owners" is listed in both teams and team_ids (as 2), list it in only one of them`)
+	assert.NoError(t, notificationCheckTeamOverlap(context.Background(), m, map[string]interface{}{
+		"teams":    []interface{}{"Everyone"},
+		"team_ids": []interface{}{2, 404},
+	}))
+}
+
+// TestOfflineNotificationImport tests adopting an existing notification rule
+// into state by its channel and ID.
+func TestOfflineNotificationImport(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	r := resourceNotification()
+	ctx := context.Background()
+
+	n, err := c.CreateNotification("email",
+		[]interface{}{map[string]interface{}{"type": "level", "operation": "gte", "value": "error"}},
+		"new_item",
+		map[string]interface{}{"teams": []string{"Owners"}, "users": []string{}},
+	)
+	require.NoError(t, err)
+
+	d := r.TestResourceData()
+	d.SetId("email" + ComplexImportSeparator + strconv.Itoa(n.ID))
+	result, err := r.Importer.StateContext(ctx, d, m)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	d = result[0]
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, strconv.Itoa(n.ID), d.Id())
+	assert.Equal(t, "email", d.Get("channel"))
+	rule := d.Get("rule").(*schema.Set).List()[0].(map[string]interface{})
+	assert.Equal(t, "new_item", rule["trigger"])
+	filter := rule["filters"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "level", filter["type"])
+	assert.Equal(t, "gte", filter["operation"])
+	assert.Equal(t, "error", filter["value"])
+	config := d.Get("config").(*schema.Set).List()[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"Owners"}, config["teams"])
+
+	// Rules deleted outside Terraform are dropped from state
+	f.rules["email"] = nil
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Empty(t, d.Id())
+}
+
+// TestAlignNotificationFilters tests that filters normalized by the API are
+// read back as configured.
+func TestAlignNotificationFilters(t *testing.T) {
+	prior := []interface{}{
+		map[string]interface{}{"type": "environment", "operation": "eq", "value": "Production", "period": 0.0, "count": 0.0},
+		map[string]interface{}{"type": "level", "operation": "gte", "value": "error", "path": "", "period": 0.0, "count": 0.0},
+	}
+	remote := []interface{}{
+		map[string]interface{}{"type": "title", "operation": "within", "value": "timeout"},
+		map[string]interface{}{"type": "level", "operation": "gte", "value": "error"},
+		map[string]interface{}{"type": "environment", "operation": "eq", "value": "production"},
+	}
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "environment", "operation": "eq", "value": "Production"},
+		map[string]interface{}{"type": "level", "operation": "gte", "value": "error"},
+		map[string]interface{}{"type": "title", "operation": "within", "value": "timeout"},
+	}, alignNotificationFilters(prior, remote))
+
+	// Values of other filter types are case sensitive
+	prior = []interface{}{map[string]interface{}{"type": "title", "operation": "within", "value": "Timeout"}}
+	remote = []interface{}{map[string]interface{}{"type": "title", "operation": "within", "value": "timeout"}}
+	assert.Equal(t, remote, alignNotificationFilters(prior, remote))
+}
+
+// TestValidateNotificationRules tests that invalid filters are reported with
+// the path of the filter in the raw configuration.
+func TestValidateNotificationRules(t *testing.T) {
+	filter := func(typ, operation, value string, period, count float64) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"type":      cty.StringVal(typ),
+			"operation": cty.StringVal(operation),
+			"value":     cty.StringVal(value),
+			"path":      cty.NullVal(cty.String),
+			"period":    cty.NumberFloatVal(period),
+			"count":     cty.NumberFloatVal(count),
+		})
+	}
+	rule := func(trigger cty.Value, filters ...cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"trigger": trigger,
+			"filters": cty.ListVal(filters),
+		})
+	}
+	validate := func(rules cty.Value) diag.Diagnostics {
+		var resp schema.ValidateResourceConfigFuncResponse
+		validateNotificationRules(context.Background(), schema.ValidateResourceConfigFuncRequest{
+			RawConfig: cty.ObjectVal(map[string]cty.Value{"rule": rules}),
+		}, &resp)
+		return resp.Diagnostics
+	}
+	env := filter("environment", "eq", "production", 0, 0)
+	rate := filter("rate", "", "", 300, 10)
+
+	// Rules as a list, as in rollbar_notifications
+	diags := validate(cty.ListVal([]cty.Value{
+		rule(cty.StringVal("new_item"), env),
+		rule(cty.StringVal("deploy"), env, filter("level", "gte", "error", 0, 0)),
+		rule(cty.StringVal("occurrence_rate"), env),
+	}))
+	require.Len(t, diags, 2)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "Unrecognized notification rule filter", diags[0].Summary)
+	assert.Equal(t, `Filter 1: level filters are not known to be supported by the "deploy" trigger, expected "environment". The filter is sent to the Rollbar API unchanged, which may accept it.`, diags[0].Detail)
+	assert.Equal(t, cty.GetAttrPath("rule").IndexInt(1).GetAttr("filters").IndexInt(1), diags[0].AttributePath)
+	assert.Equal(t, diag.Warning, diags[1].Severity)
+	assert.Contains(t, diags[1].Detail, `The "occurrence_rate" trigger is expected to have exactly one rate filter`)
+	assert.Equal(t, cty.GetAttrPath("rule").IndexInt(2), diags[1].AttributePath)
+
+	// Rules as a set, as in rollbar_notification
+	invalid := rule(cty.StringVal("occurrence_rate"), filter("rate", "", "", 300, 0))
+	diags = validate(cty.SetVal([]cty.Value{invalid}))
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Error, diags[0].Severity)
+	assert.Equal(t, "Invalid notification rule", diags[0].Summary)
+	assert.Equal(t, "Filter 0: rate count must be at least 1", diags[0].Detail)
+	assert.Equal(t, cty.GetAttrPath("rule").Index(invalid).GetAttr("filters").IndexInt(0), diags[0].AttributePath)
+
+	// Unknown values are validated at plan time instead
+	assert.Empty(t, validate(cty.ListVal([]cty.Value{
+		rule(cty.UnknownVal(cty.String), rate),
+		rule(cty.StringVal("new_item"), filter("rate", "", "", 300, 10), cty.UnknownVal(env.Type())),
+		rule(cty.StringVal("not_a_trigger"), rate),
+	})))
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// notificationsIdentity identifies a rollbar_notifications in import blocks.
+var notificationsIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "channel", attr: "channel", typ: schema.TypeString, description: "The notification channel"},
+}}
+
+// resourceNotifications constructs a resource authoritatively managing all the
+// notification rules of a channel.
+func resourceNotifications() *schema.Resource {
+	single := resourceNotification().Schema
+	return withIdentity(&schema.Resource{
+		CreateContext: resourceNotificationsCreateOrUpdate,
+		UpdateContext: resourceNotificationsCreateOrUpdate,
+		ReadContext:   resourceNotificationsRead,
+		DeleteContext: resourceNotificationsDelete,
+
+		CustomizeDiff:                  resourceNotificationsCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{validateNotificationRules},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"channel": {
+				Description: "Notification channel, e.g. `email`, `slack` or `pagerduty`",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"rule": {
+				Description:   "Notification rules of the channel.  Rules not listed are deleted.",
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"rules_json"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of the rule",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"trigger": {
+							Description: "Trigger",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"filters": single["rule"].Elem.(*schema.Resource).Schema["filters"],
+						"config": {
+							Description: "Channel configuration",
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Elem:        single["config"].Elem,
+						},
+					},
+				},
+			},
+			"rules_json": {
+				Description:      "Notification rules of the channel as a JSON array of objects with `trigger`, `filters` and `config`, sent to the API as is.  Compared ignoring whitespace and order.  Alternative to `rule` for settings it does not support",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"rule"},
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: notificationsRulesJSONDiffSuppress,
+			},
+		},
+	}, notificationsIdentity)
+}
+
+// notificationsRule is a notification rule to be sent to the API.
+type notificationsRule struct {
+	Trigger string                 `json:"trigger"`
+	Filters []interface{}          `json:"filters"`
+	Config  map[string]interface{} `json:"config"`
+}
+
+// parseNotificationsRulesJSON parses the `rules_json` attribute.
+func parseNotificationsRulesJSON(s string) ([]notificationsRule, error) {
+	var rules []notificationsRule
+	err := json.Unmarshal([]byte(s), &rules)
+	if err != nil {
+		return nil, fmt.Errorf("rules_json must be a JSON array of rules: %w", err)
+	}
+	for i := range rules {
+		if rules[i].Filters == nil {
+			rules[i].Filters = []interface{}{}
+		}
+		if rules[i].Config == nil {
+			rules[i].Config = map[string]interface{}{}
+		}
+	}
+	return rules, nil
+}
+
+// canonicalJSON returns a JSON value re-encoded with object keys and array
+// elements sorted, so that equivalent values compare equal.
+func canonicalJSON(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]json.RawMessage, len(v))
+		for k, e := range v {
+			m[k] = json.RawMessage(canonicalJSON(e))
+		}
+		b, _ := json.Marshal(m)
+		return string(b)
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, e := range v {
+			elems[i] = canonicalJSON(e)
+		}
+		sort.Strings(elems)
+		raw := make([]json.RawMessage, len(elems))
+		for i, e := range elems {
+			raw[i] = json.RawMessage(e)
+		}
+		b, _ := json.Marshal(raw)
+		return string(b)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// notificationsRulesJSONDiffSuppress suppresses differences in `rules_json`
+// of whitespace, key order and array order.
+func notificationsRulesJSONDiffSuppress(_, old, new string, _ *schema.ResourceData) bool {
+	var o, n interface{}
+	if json.Unmarshal([]byte(old), &o) != nil || json.Unmarshal([]byte(new), &n) != nil {
+		return false
+	}
+	return canonicalJSON(o) == canonicalJSON(n)
+}
+
+// expandNotificationsRules returns the configured rules of a channel, from
+// either `rules_json` or the `rule` blocks.
+func expandNotificationsRules(ctx context.Context, d *schema.ResourceData, m interface{}) ([]notificationsRule, diag.Diagnostics) {
+	channel := d.Get("channel").(string)
+	if s := d.Get("rules_json").(string); s != "" {
+		rules, err := parseNotificationsRulesJSON(s)
+		if err != nil {
+			return nil, diagFromErr(err, "rules_json")
+		}
+		return rules, nil
+	}
+	var rules []notificationsRule
+	for _, r := range d.Get("rule").([]interface{}) {
+		rule := r.(map[string]interface{})
+		config := notificationsRuleConfig(channel, rule)
+		if diags := notificationResolveTeamIDs(ctx, m, config); diags.HasError() {
+			return nil, diags
+		}
+		filters, _ := cleanFilters(rule["filters"]).([]interface{})
+		rules = append(rules, notificationsRule{
+			Trigger: rule["trigger"].(string),
+			Filters: filters,
+			Config:  config,
+		})
+	}
+	return rules, nil
+}
+
+// resourceNotificationsCustomizeDiff validates the filters of each rule
+// against its trigger, and checks that no team is notified both by name and by
+// ID.  Warnings are reported by validateNotificationRules.
+func resourceNotificationsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if s, _ := d.Get("rules_json").(string); s != "" && d.NewValueKnown("rules_json") {
+		rules, err := parseNotificationsRulesJSON(s)
+		if err != nil {
+			return err
+		}
+		for i, rule := range rules {
+			_, err = validateNotificationFilters(rule.Trigger, rule.Filters)
+			if err != nil {
+				return fmt.Errorf("rules_json rule %d: %w", i, err)
+			}
+		}
+	}
+	if !d.NewValueKnown("rule") {
+		return nil
+	}
+	for i, r := range d.Get("rule").([]interface{}) {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trigger, _ := rule["trigger"].(string)
+		filters, _ := rule["filters"].([]interface{})
+		_, err := validateNotificationFilters(trigger, filters)
+		if err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+		if channel, _ := d.Get("channel").(string); d.NewValueKnown("channel") {
+			err = notificationCheckTeamOverlap(ctx, m, notificationsRuleConfig(channel, rule))
+			if err != nil {
+				return fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// notificationsRuleConfig returns the config of a `rule` element, cleaned for
+// the API.
+func notificationsRuleConfig(channel string, rule map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{}
+	if list, _ := rule["config"].([]interface{}); len(list) > 0 && list[0] != nil {
+		config = list[0].(map[string]interface{})
+	}
+	return cleanConfig(channel, config)
+}
+
+// resourceNotificationsCreateOrUpdate makes the rules of a channel match the
+// configured rules, updating existing rules in order, creating any more that
+// are configured and deleting the rest.
+func resourceNotificationsCreateOrUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	channel := d.Get("channel").(string)
+	l := log.With().Str("channel", channel).Logger()
+	l.Info().Msg("Applying rollbar_notifications resource")
+
+	c, err := clientFor(ctx, m, "rollbar_notifications", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	existing, err := c.ListNotifications(channel)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err, "channel")
+	}
+	rules, diags := expandNotificationsRules(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	for i, rule := range rules {
+		if i < len(existing) {
+			_, err = c.UpdateNotification(existing[i].ID, channel, rule.Filters, rule.Trigger, rule.Config)
+		} else {
+			_, err = c.CreateNotification(channel, rule.Filters, rule.Trigger, rule.Config)
+		}
+		if err != nil {
+			l.Err(err).Int("rule", i).Send()
+			return diagFromErr(err, "rule")
+		}
+	}
+	for i := len(rules); i < len(existing); i++ {
+		err = c.DeleteNotification(existing[i].ID, channel)
+		if err != nil {
+			l.Err(err).Int("id", existing[i].ID).Send()
+			return diagFromErr(err)
+		}
+	}
+	d.SetId(channel)
+	l.Debug().Msg("Successfully applied rollbar_notifications resource")
+	return resourceNotificationsRead(ctx, d, m)
+}
+
+func resourceNotificationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	channel := d.Id()
+	l := log.With().Str("channel", channel).Logger()
+	l.Info().Msg("Reading rollbar_notifications resource")
+	c, err := clientFor(ctx, m, "rollbar_notifications", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	notifications, err := c.ListNotifications(channel)
+	if err != nil {
+		l.Err(err).Msg("Error reading rollbar_notifications resource")
+		return diagFromErr(err)
+	}
+	if d.Get("rules_json").(string) != "" {
+		rules := make([]notificationsRule, len(notifications))
+		for i, n := range notifications {
+			rules[i] = notificationsRule{Trigger: n.Trigger, Filters: n.Filters, Config: n.Config}
+		}
+		b, err := json.Marshal(rules)
+		if err == nil {
+			// Normalize missing filters and config as parsing does
+			rules, err = parseNotificationsRulesJSON(string(b))
+		}
+		if err == nil {
+			b, err = json.Marshal(rules)
+		}
+		if err != nil {
+			return diagFromErr(err)
+		}
+		mustSet(d, "channel", channel)
+		mustSet(d, "rule", []interface{}{})
+		mustSet(d, "rules_json", string(b))
+		l.Debug().Int("count", len(rules)).Msg("Successfully read rollbar_notifications resource")
+		return nil
+	}
+	prior := d.Get("rule").([]interface{})
+	rules := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		if i < len(prior) && prior[i] != nil {
+			priorFilters, _ := prior[i].(map[string]interface{})["filters"].([]interface{})
+			n.Filters = alignNotificationFilters(priorFilters, n.Filters)
+		}
+		rule := flattenNotificationRule(n)
+		delete(rule, "action")
+		config := rule["config"].([]interface{})[0].(map[string]interface{})
+		if v, ok := n.Config["service_key"]; ok {
+			config["service_key"] = v
+		}
+		if emptyConfig(config) {
+			rule["config"] = []interface{}{}
+		}
+		// Teams configured by ID are matched up with the prior rule in the
+		// same position.
+		if i < len(prior) && prior[i] != nil {
+			priorConfig := notificationsRuleConfig(channel, prior[i].(map[string]interface{}))
+			teamIDs, _ := priorConfig["team_ids"].([]interface{})
+			err = notificationUnresolveTeamIDs(ctx, m, teamIDs, config)
+			if err != nil {
+				l.Err(err).Msg("Error reading rollbar_notifications resource")
+				return diagFromErr(err)
+			}
+		}
+		rules[i] = rule
+	}
+	mustSet(d, "channel", channel)
+	mustSet(d, "rule", rules)
+	l.Debug().Int("count", len(rules)).Msg("Successfully read rollbar_notifications resource")
+	return nil
+}
+
+// emptyConfig returns true if a notification config read from the API sets
+// nothing, as when no config block was given.
+func emptyConfig(config map[string]interface{}) bool {
+	for _, v := range config {
+		switch v := v.(type) {
+		case nil:
+		case []interface{}:
+			if len(v) > 0 {
+				return false
+			}
+		case string:
+			if v != "" {
+				return false
+			}
+		case bool:
+			if v {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func resourceNotificationsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	channel := d.Id()
+	l := log.With().Str("channel", channel).Logger()
+	l.Info().Msg("Deleting rollbar_notifications resource")
+	c, err := clientFor(ctx, m, "rollbar_notifications", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	notifications, err := c.ListNotifications(channel)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err)
+	}
+	for _, n := range notifications {
+		err = c.DeleteNotification(n.ID, channel)
+		if err != nil {
+			l.Err(err).Int("id", n.ID).Msg("Error deleting rollbar_notifications resource")
+			return diagFromErr(err)
+		}
+	}
+	l.Debug().Int("count", len(notifications)).Msg("Successfully deleted rollbar_notifications resource")
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineNotificationsCRUD tests authoritatively managing the rules of a
+// channel, including rules created outside Terraform.
+func TestOfflineNotificationsCRUD(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[projectKeyToken]
+	r := resourceNotifications()
+	ctx := context.Background()
+
+	for _, trigger := range []string{"new_item", "reactivated_item"} {
+		_, err := c.CreateNotification("email", []interface{}{}, trigger, map[string]interface{}{"teams": []string{"Everyone"}})
+		require.NoError(t, err)
+	}
+	_, err := c.CreateNotification("slack", []interface{}{}, "deploy", map[string]interface{}{"channel": "#releases"})
+	require.NoError(t, err)
+	levelRule := map[string]interface{}{
+		"trigger": "new_item",
+		"filters": []interface{}{map[string]interface{}{"type": "level", "operation": "gte", "value": "error"}},
+		"config":  []interface{}{map[string]interface{}{"teams": []interface{}{"Owners"}}},
+	}
+
+	// Creating adopts the channel's rules, deleting those not configured
+	d := offlineApply(t, r, r.TestResourceData(), m, map[string]interface{}{
+		"channel": "email",
+		"rule":    []interface{}{levelRule},
+	})
+	assert.Equal(t, "email", d.Id())
+	require.Len(t, f.rules["email"], 1)
+	assert.Equal(t, "new_item", f.rules["email"][0].Trigger)
+	assert.Equal(t, []interface{}{"Owners"}, f.rules["email"][0].Config["teams"])
+	assert.Equal(t, f.rules["email"][0].ID, d.Get("rule.0.id"))
+	assert.Equal(t, "error", d.Get("rule.0.filters.0.value"))
+	assert.Len(t, f.rules["slack"], 1, "other channels are left alone")
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(map[string]interface{}{
+		"channel": "email",
+		"rule":    []interface{}{levelRule},
+	}), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes, "no changes after apply")
+
+	// Rules added outside Terraform show up as drift
+	_, err = c.CreateNotification("email", []interface{}{}, "deploy", map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, 2, d.Get("rule.#"))
+	assert.Equal(t, "deploy", d.Get("rule.1.trigger"))
+	assert.Equal(t, 0, d.Get("rule.1.config.#"))
+
+	d = offlineApply(t, r, d, m, map[string]interface{}{
+		"channel": "email",
+		"rule": []interface{}{levelRule, map[string]interface{}{
+			"trigger": "occurrence_rate",
+			"filters": []interface{}{map[string]interface{}{"type": "rate", "period": 300, "count": 10}},
+		}},
+	})
+	require.Len(t, f.rules["email"], 2)
+	assert.Equal(t, "occurrence_rate", f.rules["email"][1].Trigger)
+	assert.Equal(t, "occurrence_rate", d.Get("rule.1.trigger"))
+
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	assert.Empty(t, f.rules["email"])
+	assert.Len(t, f.rules["slack"], 1)
+}
+
+// TestOfflineNotificationsNormalized tests that filters the API reorders or
+// lowercases are not reported as changes.
+func TestOfflineNotificationsNormalized(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceNotifications()
+	ctx := context.Background()
+	config := map[string]interface{}{
+		"channel": "email",
+		"rule": []interface{}{map[string]interface{}{
+			"trigger": "new_item",
+			"filters": []interface{}{
+				map[string]interface{}{"type": "environment", "operation": "eq", "value": "Production"},
+				map[string]interface{}{"type": "level", "operation": "gte", "value": "error"},
+			},
+		}},
+	}
+	d := offlineApply(t, r, r.TestResourceData(), m, config)
+
+	// Normalize the rule as the API does
+	require.Len(t, f.rules["email"], 1)
+	filters := f.rules["email"][0].Filters
+	filters[0], filters[1] = filters[1], filters[0]
+	filters[1].(map[string]interface{})["value"] = "production"
+
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, "Production", d.Get("rule.0.filters.0.value"))
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes)
+
+	// Changing the case in configuration alone is not a change either
+	config["rule"].([]interface{})[0].(map[string]interface{})["filters"].([]interface{})[0].(map[string]interface{})["value"] = "PRODUCTION"
+	diff, err = r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes)
+}
+
+// TestOfflineNotificationsRulesJSON tests giving the rules of a channel as
+// JSON, compared ignoring formatting and order.
+func TestOfflineNotificationsRulesJSON(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceNotifications()
+	ctx := context.Background()
+	config := map[string]interface{}{
+		"channel": "slack",
+		"rules_json": `[
+			{"trigger": "new_item", "filters": [{"type": "level", "operation": "gte", "value": "error"}],
+			 "config": {"channel": "#errors", "future_setting": true}},
+			{"trigger": "deploy"}
+		]`,
+	}
+	d := offlineApply(t, r, r.TestResourceData(), m, config)
+	require.Len(t, f.rules["slack"], 2)
+	assert.Equal(t, true, f.rules["slack"][0].Config["future_setting"], "config is sent as is")
+	assert.Equal(t, "deploy", f.rules["slack"][1].Trigger)
+	assert.Equal(t, 0, d.Get("rule.#"))
+
+	config["rules_json"] = `[{"trigger":"deploy","config":{},"filters":[]},{"config":{"future_setting":true,"channel":"#errors"},"trigger":"new_item","filters":[{"value":"error","type":"level","operation":"gte"}]}]`
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes, "equivalent JSON is not a change")
+
+	config["rules_json"] = `[{"trigger": "occurrence_rate", "filters": [{"type": "rate", "period": 300, "count": 0}]}]`
+	_, err = r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	assert.ErrorContains(t, err, `rules_json rule 0: filter 0: rate count must be at least 1`)
+
+	// Unrecognized filters are warned about when validating the configuration
+	config["rules_json"] = `[{"trigger": "deploy", "filters": [{"type": "level", "operation": "gte", "value": "error"}]}]`
+	var resp schema.ValidateResourceConfigFuncResponse
+	validateNotificationRules(ctx, schema.ValidateResourceConfigFuncRequest{
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"rules_json": cty.StringVal(config["rules_json"].(string)),
+			"rule":       cty.NullVal(cty.DynamicPseudoType),
+		}),
+	}, &resp)
+	diags := resp.Diagnostics
+	assert.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("rules_json"), diags[0].AttributePath)
+	assert.Contains(t, diags[0].Detail, `Rule 0: filter 0: level filters are not known to be supported by the "deploy" trigger`)
+}
+
+// TestOfflineNotificationsInvalidRule tests that rules are validated against
+// their trigger at plan time.
+func TestOfflineNotificationsInvalidRule(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	r := resourceNotifications()
+	c := map[string]interface{}{
+		"channel": "email",
+		"rule": []interface{}{map[string]interface{}{
+			"trigger": "new_item",
+			"filters": []interface{}{map[string]interface{}{"type": "path", "operation": "eq", "value": "error"}},
+		}},
+	}
+	_, err := r.SimpleDiff(context.Background(), r.TestResourceData().State(), sdkterraform.NewResourceConfigRaw(c), m)
+	assert.ErrorContains(t, err, "rule 0: filter 0: path filters require a path")
+}
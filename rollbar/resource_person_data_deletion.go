@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Possible statuses of a person data deletion job
+const (
+	personDataDeletionPending   = "pending"
+	personDataDeletionCompleted = "completed"
+)
+
+// personDataDeletionPollInterval is how often the API is polled while waiting
+// for a person data deletion job to complete.  Tests shorten it.
+var personDataDeletionPollInterval = 10 * time.Second
+
+// resourcePersonDataDeletion constructs a resource that deletes all data about
+// a person tracked by a Rollbar project, e.g. to honor a GDPR erasure request.
+// The resource records the deletion in state for auditing; destroying it does
+// not, and can not, restore any data.
+func resourcePersonDataDeletion() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePersonDataDeletionCreate,
+		ReadContext:   resourcePersonDataDeletionRead,
+		DeleteContext: resourcePersonDataDeletionDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
+		Schema: map[string]*schema.Schema{
+			// Required
+			"person_id": {
+				Description: "ID of the person whose data is deleted",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+
+			// Optional
+			"wait_for_completion": {
+				Description: "Wait for the deletion job to complete before finishing the apply",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+			},
+
+			// Computed
+			"status": {
+				Description: "Status of the deletion job.  Either `pending` or `completed`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"date_submitted": {
+				Description: "Date the deletion job was submitted",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// personDataDeletionStatus returns the status of the data deletion job for a
+// person.  Data is deleted once the person is no longer found.
+func personDataDeletionStatus(c *client.RollbarAPIClient, personID int) (string, error) {
+	_, err := c.ReadPerson(personID)
+	if err == client.ErrNotFound {
+		return personDataDeletionCompleted, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return personDataDeletionPending, nil
+}
+
+func resourcePersonDataDeletionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	personID := d.Get("person_id").(int)
+	l := log.With().
+		Int("person_id", personID).
+		Logger()
+	l.Info().Msg("Creating rollbar_person_data_deletion resource")
+
+	// People belong to a project, so are deleted with the project access token
+	c, err := clientFor(ctx, m, "rollbar_person_data_deletion", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = c.DeletePersonData(personID)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err, "person_id")
+	}
+	d.SetId(strconv.Itoa(personID))
+	mustSet(d, "date_submitted", int(timeNow().Unix()))
+
+	if d.Get("wait_for_completion").(bool) {
+		l.Debug().Msg("Waiting for person data deletion to complete")
+		conf := &retry.StateChangeConf{
+			Pending:      []string{personDataDeletionPending},
+			Target:       []string{personDataDeletionCompleted},
+			Timeout:      d.Timeout(schema.TimeoutCreate),
+			PollInterval: personDataDeletionPollInterval,
+			Refresh: func() (interface{}, string, error) {
+				status, err := personDataDeletionStatus(c, personID)
+				return status, status, err
+			},
+		}
+		_, err = conf.WaitForStateContext(ctx)
+		if err != nil {
+			l.Err(err).Send()
+			return diagFromErr(err)
+		}
+	}
+
+	l.Debug().Msg("Successfully created rollbar_person_data_deletion resource")
+	return resourcePersonDataDeletionRead(ctx, d, m)
+}
+
+func resourcePersonDataDeletionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	personID := mustGetID(d)
+	l := log.With().
+		Int("person_id", personID).
+		Logger()
+	l.Info().Msg("Reading rollbar_person_data_deletion resource")
+
+	// The deletion is kept in state as an audit record, even once complete.
+	c, err := clientFor(ctx, m, "rollbar_person_data_deletion", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	status, err := personDataDeletionStatus(c, personID)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err)
+	}
+	mustSet(d, "status", status)
+
+	l.Debug().Msg("Successfully read rollbar_person_data_deletion resource")
+	return nil
+}
+
+func resourcePersonDataDeletionDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	l := log.With().
+		Str("person_id", d.Id()).
+		Logger()
+	l.Info().Msg("Deleting rollbar_person_data_deletion resource")
+
+	// Deleted data cannot be restored, so only the audit record is removed.
+	d.SetId("")
+
+	l.Debug().Msg("Successfully deleted rollbar_person_data_deletion resource")
+	return nil
+}
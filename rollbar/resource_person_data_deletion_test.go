@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflinePersonDataDeletion tests submitting a person data deletion,
+// waiting for it to complete, and keeping it in state as an audit record.
+func TestOfflinePersonDataDeletion(t *testing.T) {
+	interval := personDataDeletionPollInterval
+	personDataDeletionPollInterval = time.Millisecond
+	defer func() { personDataDeletionPollInterval = interval }()
+	f := newFakeAPI(t)
+	f.people[7] = client.Person{ID: 7, Username: "person"}
+	f.people[8] = client.Person{ID: 8, Username: "other"}
+	m := offlineMeta(f)
+	r := resourcePersonDataDeletion()
+	ctx := context.Background()
+
+	// Create waits until the person is no longer found
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"person_id": 7,
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	assert.Equal(t, "7", d.Id())
+	assert.Equal(t, personDataDeletionCompleted, d.Get("status"))
+	assert.NotZero(t, d.Get("date_submitted"))
+	assert.NotContains(t, f.people, 7)
+	assert.Contains(t, f.people, 8)
+
+	// The completed deletion stays in state
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, "7", d.Id())
+	assert.Equal(t, personDataDeletionCompleted, d.Get("status"))
+
+	// Destroying only forgets the audit record
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	assert.Equal(t, "", d.Id())
+
+	// Without waiting, the deletion is pending until a later read
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"person_id":           8,
+		"wait_for_completion": false,
+	})
+	require.False(t, r.CreateContext(ctx, d, m).HasError())
+	assert.Equal(t, personDataDeletionPending, d.Get("status"))
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, "8", d.Id())
+	assert.Equal(t, personDataDeletionCompleted, d.Get("status"))
+
+	// Deleting the data of an unknown person fails
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"person_id": 9,
+	})
+	diags := r.CreateContext(ctx, d, m)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "", d.Id())
+}
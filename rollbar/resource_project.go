@@ -29,27 +29,42 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
+	"slices"
 	"strconv"
 )
 
+// defaultPostTokenAttrs maps the names of the default post tokens Rollbar
+// creates with a project to the attributes exposing them.
+var defaultPostTokenAttrs = map[string]string{
+	"post_server_item": "post_server_item_access_token",
+	"post_client_item": "post_client_item_access_token",
+}
+
+// projectIdentity identifies a rollbar_project in import blocks.
+var projectIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "id", typ: schema.TypeInt, description: "The project's numeric ID"},
+}}
+
 func resourceProject() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceProjectCreate,
 		ReadContext:   resourceProjectRead,
 		DeleteContext: resourceProjectDelete,
 		UpdateContext: resourceProjectUpdate,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceProjectImporter,
 		},
 
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
 		Schema: map[string]*schema.Schema{
 			// Required
 			"name": {
 				Description: "The human readable name for the project",
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 			},
 
 			// Optional
@@ -61,6 +76,31 @@ func resourceProject() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
+			"delete_protection": {
+				Description: "Prevent the project from being destroyed.  Destroying a project permanently deletes all of its items.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_existing": {
+				Description: "If a project with the same name already exists, adopt it rather than failing",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"purge_tokens_on_destroy": {
+				Description: "Delete all remaining access tokens of the project before destroying it",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"keep_default_tokens": {
+				Description: "Keep the `post_server_item` and `post_client_item` access tokens Rollbar creates with a new project, rather than deleting them",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
 
 			// Computed
 			"account_id": {
@@ -83,8 +123,87 @@ func resourceProject() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"post_server_item_access_token": {
+				Description: "The default `post_server_item` access token, if `keep_default_tokens` is true",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"post_client_item_access_token": {
+				Description: "The default `post_client_item` access token, if `keep_default_tokens` is true",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
 		},
+	}, projectIdentity)
+}
+
+// defaultPostTokens returns the access tokens of the enabled default post
+// tokens among `tokens`, keyed by token name.
+func defaultPostTokens(tokens []client.ProjectAccessToken) map[string]string {
+	found := make(map[string]string)
+	for _, t := range tokens {
+		if defaultPostTokenAttrs[t.Name] == "" || t.Status != client.StatusEnabled {
+			continue
+		}
+		found[t.Name] = t.AccessToken
+	}
+	return found
+}
+
+// resourceProjectAdopt adopts an existing project into state in place of
+// creating a new one.  Its access tokens are left untouched, but its team
+// assignments are converged on the configuration.
+func resourceProjectAdopt(ctx context.Context, d *schema.ResourceData, m interface{}, projectID int) diag.Diagnostics {
+	c, err := clientFor(ctx, m, "rollbar_project", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	teamIDs := projectTeamIDs(d, c)
+	l := log.With().
+		Int("project_id", projectID).
+		Ints("team_ids", teamIDs).
+		Logger()
+	l.Info().Msg("Adopting existing Rollbar project")
+
+	err = c.UpdateProjectTeams(projectID, teamIDs)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err, "team_ids")
+	}
+	d.SetId(strconv.Itoa(projectID))
+
+	l.Debug().Msg("Successfully adopted existing Rollbar project")
+	return resourceProjectRead(ctx, d, m)
+}
+
+// resourceProjectImporter imports a project by its numeric ID, or by name if
+// the ID is not numeric.
+func resourceProjectImporter(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.Atoi(d.Id()); err == nil {
+		return []*schema.ResourceData{d}, nil
+	}
+	name := d.Id()
+	l := log.With().
+		Str("name", name).
+		Logger()
+	l.Info().Msg("Importing rollbar_project resource by name")
+
+	c, err := clientFor(ctx, meta, "rollbar_project", "read")
+	if err != nil {
+		return nil, err
 	}
+	p, err := c.GetProjectByName(name)
+	if err == client.ErrNotFound {
+		return nil, fmt.Errorf("no project with the name %q found", name)
+	}
+	if err != nil {
+		l.Err(err).Send()
+		return nil, err
+	}
+	d.SetId(strconv.Itoa(p.ID))
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -92,11 +211,24 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 	l := log.With().Str("name", name).Logger()
 	l.Info().Msg("Creating new Rollbar project resource")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_project", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("allow_existing").(bool) {
+		existing, err := c.GetProjectByName(name)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Send()
+			return diagFromErr(err, "name")
+		}
+		if err == nil {
+			return resourceProjectAdopt(ctx, d, m, existing.ID)
+		}
+	}
 	p, err := c.CreateProject(name)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err, "name")
 	}
 	l.Debug().Interface("project", p).Msg("CreateProject() result")
 	projectID := p.ID
@@ -106,7 +238,9 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 	// A set of four default access tokens are automagically created by Rollbar
 	// when creating a new project.  However we only want access tokens that are
 	// explicitly created and managed by Terraform.  Therefore we delete the
-	// default tokens for our new project.
+	// default tokens for our new project, except the post tokens if the user
+	// asked to keep them.
+	keepDefaultTokens := d.Get("keep_default_tokens").(bool)
 	expectedTokenNames := map[string]bool{
 		"read":             true,
 		"write":            true,
@@ -116,7 +250,7 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 	tokens, err := c.ListProjectAccessTokens(projectID)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return withPartialState(ctx, d, m, resourceProjectRead, diagFromErr(err))
 	}
 	for _, t := range tokens {
 		// Sanity check
@@ -124,28 +258,30 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 		if !expected {
 			err = fmt.Errorf("unexpected token name in default tokens")
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return withPartialState(ctx, d, m, resourceProjectRead, diagFromErr(err))
+		}
+		if keepDefaultTokens && defaultPostTokenAttrs[t.Name] != "" {
+			continue
 		}
 		// Deletion
 		err = c.DeleteProjectAccessToken(projectID, t.AccessToken)
 		if err != nil {
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return withPartialState(ctx, d, m, resourceProjectRead, diagFromErr(err))
 		}
 		l.Debug().
 			Str("name", t.Name).
 			Msg("Successfully deleted a default access token")
 	}
 
-	// Team assignments
-	teamIDsSet := d.Get("team_ids").(*schema.Set)
-	for _, teamIDiface := range teamIDsSet.List() {
-		teamID := teamIDiface.(int)
+	// Team assignments, including the provider's default teams.  If one
+	// fails, reading back the project records the teams assigned so far.
+	for _, teamID := range projectTeamIDs(d, c) {
 		l = l.With().Int("team_id", teamID).Logger()
 		err = c.AssignTeamToProject(teamID, projectID)
 		if err != nil {
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return withPartialState(ctx, d, m, resourceProjectRead, diagFromErr(err, "team_ids"))
 		}
 	}
 
@@ -160,7 +296,10 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 		Logger()
 	l.Info().Msg("Reading Rollbar project resource")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_project", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	proj, err := c.ReadProject(projectID)
 	if err == client.ErrNotFound {
 		l.Debug().Msg("Project not found on Rollbar - removing from state")
@@ -169,7 +308,7 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	var mProj map[string]interface{}
@@ -184,9 +323,33 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	teamIDs, err := c.FindProjectTeamIDs(projectID)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	mustSet(d, "team_ids", withoutDefaultTeams(teamIDs, getTeamIDs(d), c.DefaultTeamIDs))
+
+	// delete_protection, purge_tokens_on_destroy, and allow_existing are not
+	// known to the API, so they are only ever read from configuration.  Setting them explicitly
+	// ensures they are present in state after import.
+	mustSet(d, "delete_protection", d.Get("delete_protection").(bool))
+	mustSet(d, "purge_tokens_on_destroy", d.Get("purge_tokens_on_destroy").(bool))
+	mustSet(d, "allow_existing", d.Get("allow_existing").(bool))
+	keepDefaultTokens := d.Get("keep_default_tokens").(bool)
+	mustSet(d, "keep_default_tokens", keepDefaultTokens)
+
+	// Default post tokens
+	for _, attr := range defaultPostTokenAttrs {
+		mustSet(d, attr, "")
+	}
+	if keepDefaultTokens {
+		tokens, err := c.ListProjectAccessTokens(projectID)
+		if err != nil {
+			l.Err(err).Send()
+			return diagFromErr(err)
+		}
+		for name, token := range defaultPostTokens(tokens) {
+			mustSet(d, defaultPostTokenAttrs[name], token)
+		}
 	}
-	mustSet(d, "team_ids", teamIDs)
 
 	d.SetId(strconv.Itoa(proj.ID))
 	l.Debug().Msg("Successfully read Rollbar project resource from the API")
@@ -194,19 +357,36 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 }
 
 // resourceProjectUpdate handles update for a `rollbar_project` resource.
+// Renaming a project updates it in place, preserving its items and tokens.
 func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	teamIDs := getTeamIDs(d)
+	c, err := clientFor(ctx, m, "rollbar_project", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	teamIDs := projectTeamIDs(d, c)
 	projectID := mustGetID(d)
 	l := log.With().
 		Int("project_id", projectID).
 		Ints("team_ids", teamIDs).
 		Logger()
 	l.Debug().Msg("Updating rollbar_project resource")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	err := c.UpdateProjectTeams(projectID, teamIDs)
-	if err != nil {
-		l.Err(err).Msg("Error updating rollbar_project resource")
-		return diag.FromErr(err)
+	if d.HasChange("name") {
+		name := d.Get("name").(string)
+		_, err := c.UpdateProject(projectID, name)
+		if err != nil {
+			l.Err(err).Msg("Error renaming rollbar_project resource")
+			return diagFromErr(err, "name")
+		}
+	}
+	// Converge team assignments on team_ids.  If that fails part way, reading
+	// back the project records the teams actually assigned, so the remainder
+	// is planned again.
+	if d.HasChange("team_ids") {
+		err := c.UpdateProjectTeams(projectID, teamIDs)
+		if err != nil {
+			l.Err(err).Msg("Error updating rollbar_project resource")
+			return withPartialState(ctx, d, m, resourceProjectRead, diagFromErr(err, "team_ids"))
+		}
 	}
 	l.Debug().Msg("Successfully updated rollbar_project resource")
 	return resourceProjectRead(ctx, d, m)
@@ -219,12 +399,65 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 		Int("projectID", projectID).
 		Logger()
 	l.Info().Msg("Deleting rollbar_project resource")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	err := c.DeleteProject(projectID)
+	if d.Get("delete_protection").(bool) {
+		l.Warn().Msg("Refusing to delete protected rollbar_project resource")
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Project is protected from deletion",
+			Detail: fmt.Sprintf("Project %d has delete_protection enabled.  To destroy it, first set "+
+				"delete_protection = false and run terraform apply.", projectID),
+		}}
+	}
+	c, err := clientFor(ctx, m, "rollbar_project", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("purge_tokens_on_destroy").(bool) {
+		tokens, err := c.ListProjectAccessTokens(projectID)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Msg("Error listing access tokens of rollbar_project resource")
+			return diagFromErr(err)
+		}
+		for _, t := range tokens {
+			err = c.DeleteProjectAccessToken(projectID, t.AccessToken)
+			if err != nil && err != client.ErrNotFound {
+				l.Err(err).Msg("Error purging access token of rollbar_project resource")
+				return diagFromErr(err)
+			}
+			l.Debug().Str("name", t.Name).Msg("Purged access token")
+		}
+	}
+	err = c.DeleteProject(projectID)
 	if err != nil {
 		l.Err(err).Msg("Error deleting rollbar_project resource")
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	l.Debug().Msg("Successfully deleted rollbar_project resource")
 	return nil
 }
+
+// projectTeamIDs returns the teams to assign to a project: those in its
+// team_ids, plus the provider's default_team_ids.
+func projectTeamIDs(d *schema.ResourceData, c *client.RollbarAPIClient) []int {
+	teamIDs := getTeamIDs(d)
+	for _, teamID := range c.DefaultTeamIDs {
+		if !slices.Contains(teamIDs, teamID) {
+			teamIDs = append(teamIDs, teamID)
+		}
+	}
+	return teamIDs
+}
+
+// withoutDefaultTeams removes the provider's default teams from the teams
+// assigned to a project, unless they are also in its configured team_ids, so
+// they are not reported as drift.
+func withoutDefaultTeams(assigned, configured, defaults []int) []int {
+	teamIDs := []int{}
+	for _, teamID := range assigned {
+		if slices.Contains(defaults, teamID) && !slices.Contains(configured, teamID) {
+			continue
+		}
+		teamIDs = append(teamIDs, teamID)
+	}
+	return teamIDs
+}
@@ -25,25 +25,44 @@ package rollbar
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// timeNow returns the current time.  It is a variable so tests can control
+// the clock when exercising token rotation.
+var timeNow = time.Now
+
+// projectAccessTokenIdentity identifies a rollbar_project_access_token in import blocks.
+var projectAccessTokenIdentity = resourceIdentity{separator: "/", attrs: []identityAttr{
+	{name: "project_id", attr: "project_id", typ: schema.TypeInt, description: "ID of the project the token belongs to"},
+	{name: "name", attr: "name", typ: schema.TypeString, description: "The token's name, unique within its project"},
+}}
+
 func resourceProjectAccessToken() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceProjectAccessTokenCreate,
 		ReadContext:   resourceProjectAccessTokenRead,
 		DeleteContext: resourceProjectAccessTokenDelete,
 		UpdateContext: resourceProjectAccessTokenUpdate,
+		CustomizeDiff: resourceProjectAccessTokenCustomizeDiff,
 
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceProjectAccessTokenImporter,
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			stateUpgrader(0, resourceProjectAccessTokenV0(), stateDedupeListAttr("scopes")),
+		},
+
 		Schema: map[string]*schema.Schema{
 			// Required fields
 			"project_id": {
@@ -59,32 +78,58 @@ func resourceProjectAccessToken() *schema.Resource {
 				ForceNew:    true, // FIXME: https://github.com/rollbar/terraform-provider-rollbar/issues/41
 			},
 			"scopes": {
-				Description: `List of access scopes granted to the token.  Possible values are "read", "write", "post_server_item", and "post_client_server".`,
-				Type:        schema.TypeList,
+				Description: fmt.Sprintf("Set of access scopes granted to the token.  Possible values are %s.", quotedList(client.ScopeStrings(), "and")),
+				Type:        schema.TypeSet,
 				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				ForceNew:    true, // FIXME: https://github.com/rollbar/terraform-provider-rollbar/issues/41
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: resourceProjectAccessTokenValidateScope,
+				},
+				ForceNew: true, // FIXME: https://github.com/rollbar/terraform-provider-rollbar/issues/41
 			},
 
 			// Optional fields
 			"status": {
-				Description: `Status of the token.  Possible values are "enabled" and "disabled"`,
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "enabled",
-				ForceNew:    true, // FIXME: https://github.com/rollbar/terraform-provider-rollbar/issues/41
+				Description:  `Status of the token.  Possible values are "enabled" and "disabled".  Can be changed without replacing the token`,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "enabled",
+				ValidateFunc: validation.StringInSlice([]string{string(client.StatusEnabled), string(client.StatusDisabled)}, false),
 			},
 			"rate_limit_window_count": {
-				Description: "Total number of calls allowed within the rate limit window",
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
+				Description:      "Total number of calls allowed within the rate limit window.  0 means unlimited",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: resourceProjectAccessTokenValidateRateLimitCount,
 			},
 			"rate_limit_window_size": {
-				Description: "Total number of seconds that makes up the rate limit window",
-				Type:        schema.TypeInt,
+				Description:      "Total number of seconds that makes up the rate limit window.  Possible values are 0 (no rate limit), 60, 300, 1800, 3600, 86400, 604800, and 2592000",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: resourceProjectAccessTokenValidateRateLimitSize,
+			},
+			"rotation_days": {
+				Description:  "Number of days after creation when the token should be rotated.  When exceeded, the next apply replaces the token with a new one",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"adopt_existing": {
+				Description: "If a token with the same name already exists in the project, such as a default token created by Rollbar, adopt it rather than creating a new token.  Its scopes must match `scopes`",
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     0,
+				Default:     false,
+			},
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger rotation of the token",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				ForceNew:    true,
 			},
 
 			// Computed fields
@@ -92,6 +137,7 @@ func resourceProjectAccessToken() *schema.Resource {
 				Description: "Access token for Rollbar API",
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 			"date_created": {
 				Description: "Date the project was created",
@@ -113,14 +159,131 @@ func resourceProjectAccessToken() *schema.Resource {
 				Type:        schema.TypeInt,
 				Computed:    true,
 			},
+			"ready_for_rotation": {
+				Description: "True if the token is older than rotation_days and will be replaced on the next apply",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"created_at": {
+				Description: "RFC 3339 timestamp of when the token was created",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"expires_at": {
+				Description: "RFC 3339 timestamp after which the token will be rotated, if rotation_days is set",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}, projectAccessTokenIdentity)
+}
+
+// resourceProjectAccessTokenV0 is the project access token resource at schema
+// version 0, when scopes was an ordered list.
+func resourceProjectAccessTokenV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"project_id":                  {Type: schema.TypeInt, Required: true, ForceNew: true},
+			"name":                        {Type: schema.TypeString, Required: true, ForceNew: true},
+			"scopes":                      {Type: schema.TypeList, Required: true, ForceNew: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"status":                      {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"rate_limit_window_count":     {Type: schema.TypeInt, Optional: true},
+			"rate_limit_window_size":      {Type: schema.TypeInt, Optional: true},
+			"rotation_days":               {Type: schema.TypeInt, Optional: true},
+			"keepers":                     {Type: schema.TypeMap, Optional: true, ForceNew: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"access_token":                {Type: schema.TypeString, Computed: true, Sensitive: true},
+			"date_created":                {Type: schema.TypeInt, Computed: true},
+			"date_modified":               {Type: schema.TypeInt, Computed: true},
+			"cur_rate_limit_window_count": {Type: schema.TypeInt, Computed: true},
+			"cur_rate_limit_window_start": {Type: schema.TypeInt, Computed: true},
+			"ready_for_rotation":          {Type: schema.TypeBool, Computed: true},
 		},
 	}
 }
 
+func resourceProjectAccessTokenValidateScope(v interface{}, p cty.Path) diag.Diagnostics {
+	s := client.Scope(v.(string))
+	switch s {
+	case client.ScopeWrite:
+		// Valid, but worth a second look
+		d := diag.Diagnostic{
+			Severity:      diag.Warning,
+			AttributePath: p,
+			Summary:       `Token is granted the "write" scope`,
+			Detail:        `Tokens with the "write" scope can modify or delete project data.  If the token is only used to report errors, use "post_server_item" or "post_client_item" instead.`,
+		}
+		return diag.Diagnostics{d}
+	}
+	if s.Valid() {
+		return nil
+	}
+	summary := fmt.Sprintf(`Invalid scope: "%s"`, s)
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		AttributePath: p,
+		Summary:       summary,
+		Detail:        fmt.Sprintf("Must be %s", quotedList(client.ScopeStrings(), "or")),
+	}
+	return diag.Diagnostics{d}
+}
+
+// rateLimitWindowSizes are the rate limit window sizes, in seconds, accepted by
+// the Rollbar API.  A size of 0 disables rate limiting.
+var rateLimitWindowSizes = []int{0, 60, 300, 1800, 3600, 86400, 604800, 2592000}
+
+func resourceProjectAccessTokenValidateRateLimitSize(v interface{}, p cty.Path) diag.Diagnostics {
+	size := v.(int)
+	for _, allowed := range rateLimitWindowSizes {
+		if size == allowed {
+			return nil
+		}
+	}
+	summary := fmt.Sprintf("Invalid rate_limit_window_size: %d", size)
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		AttributePath: p,
+		Summary:       summary,
+		Detail:        "Must be 0 (no rate limit), 60 (1 minute), 300 (5 minutes), 1800 (30 minutes), 3600 (1 hour), 86400 (1 day), 604800 (1 week), or 2592000 (30 days)",
+	}
+	return diag.Diagnostics{d}
+}
+
+func resourceProjectAccessTokenValidateRateLimitCount(v interface{}, p cty.Path) diag.Diagnostics {
+	count := v.(int)
+	if count >= 0 {
+		return nil
+	}
+	summary := fmt.Sprintf("Invalid rate_limit_window_count: %d", count)
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		AttributePath: p,
+		Summary:       summary,
+		Detail:        "Must be zero (unlimited) or greater",
+	}
+	return diag.Diagnostics{d}
+}
+
+// resourceProjectAccessTokenValidateRateLimit checks that a rate limit count is
+// only set together with a rate limit window.
+func resourceProjectAccessTokenValidateRateLimit(d *schema.ResourceDiff) error {
+	size, sizeOK := d.GetOk("rate_limit_window_size")
+	count, countOK := d.GetOk("rate_limit_window_count")
+	if !d.NewValueKnown("rate_limit_window_size") || !d.NewValueKnown("rate_limit_window_count") {
+		return nil
+	}
+	if countOK && !sizeOK {
+		return fmt.Errorf("rate_limit_window_count (%d) requires a non-zero rate_limit_window_size", count.(int))
+	}
+	if sizeOK && !countOK {
+		return fmt.Errorf("rate_limit_window_size (%d) requires a non-zero rate_limit_window_count", size.(int))
+	}
+	return nil
+}
+
 func resourceProjectAccessTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	projectID := d.Get("project_id").(int)
 	name := d.Get("name").(string)
-	scopesInterface := d.Get("scopes").([]interface{})
+	scopesInterface := d.Get("scopes").(*schema.Set).List()
 	scopes := []client.Scope{}
 	for _, v := range scopesInterface {
 		s := v.(string)
@@ -139,7 +302,31 @@ func resourceProjectAccessTokenCreate(ctx context.Context, d *schema.ResourceDat
 		Logger()
 	l.Debug().Msg("Creating new project access token")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_project_access_token", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("adopt_existing").(bool) {
+		existing, err := c.ReadProjectAccessTokenByName(projectID, name)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Send()
+			return diagFromErr(err, "project_id", "name")
+		}
+		if err == nil {
+			l.Debug().Msg("Adopting existing project access token")
+			if !sameScopes(existing.Scopes, scopes) {
+				return diag.Diagnostics{{
+					Severity:      diag.Error,
+					AttributePath: cty.GetAttrPath("scopes"),
+					Summary:       fmt.Sprintf(`Cannot adopt project access token "%s"`, name),
+					Detail:        "The existing token has different scopes, which cannot be changed.  Adjust `scopes` to match the existing token, or delete it.",
+				}}
+			}
+			// Reconcile status and rate limits
+			d.SetId(existing.AccessToken)
+			return resourceProjectAccessTokenUpdate(ctx, d, m)
+		}
+	}
 	pat, err := c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
 		Name:                 name,
 		ProjectID:            projectID,
@@ -149,12 +336,47 @@ func resourceProjectAccessTokenCreate(ctx context.Context, d *schema.ResourceDat
 		RateLimitWindowCount: count,
 	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err, "project_id", "name", "scopes", "status", "rate_limit_window_size", "rate_limit_window_count")
 	}
 
+	// Record the token before reading it back.  If reading fails, the token
+	// is saved in state as tainted, and replaced by the next apply, rather
+	// than leaked.
 	d.SetId(pat.AccessToken)
+	diags := resourceProjectAccessTokenRead(ctx, d, m)
+	if d.Id() == "" {
+		// Reading found no such token, so it cannot be recorded: delete it.
+		l.Warn().Msg("Created project access token not found, deleting it")
+		detail := "The token was deleted rather than left outside Terraform state.  Try again."
+		err = c.DeleteProjectAccessToken(projectID, pat.AccessToken)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Msg("Error deleting project access token")
+			detail = fmt.Sprintf("Deleting the token failed, so it exists outside Terraform state: %s.  Delete token %s in Rollbar, then try again.",
+				err, client.RedactToken(pat.AccessToken))
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf(`Project access token "%s" not found after creation`, name),
+			Detail:   detail,
+		})
+	}
+	return diags
+}
 
-	return resourceProjectAccessTokenRead(ctx, d, m)
+// sameScopes reports whether two lists contain the same set of scopes.
+func sameScopes(a, b []client.Scope) bool {
+	set := make(map[client.Scope]bool)
+	for _, s := range a {
+		set[s] = true
+	}
+	other := make(map[client.Scope]bool)
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+		other[s] = true
+	}
+	return len(set) == len(other)
 }
 
 func resourceProjectAccessTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -163,19 +385,38 @@ func resourceProjectAccessTokenRead(ctx context.Context, d *schema.ResourceData,
 	accessToken := d.Id()
 	projectID := d.Get("project_id").(int)
 	l := log.With().
-		Str("accessToken", accessToken).
+		Str("accessToken", client.RedactToken(accessToken)).
 		Logger()
 	l.Debug().Msg("Reading resource project access token")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_project_access_token", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	pat, err := c.ReadProjectAccessToken(projectID, accessToken)
+	if name := d.Get("name").(string); err == client.ErrNotFound && name != "" {
+		// The token may have been rotated outside of Terraform, giving it a
+		// new value but keeping its name.
+		pat, err = c.ReadProjectAccessTokenByName(projectID, name)
+		if err == nil {
+			l.Warn().
+				Str("new_access_token", client.RedactToken(pat.AccessToken)).
+				Msg("Token was rotated outside of Terraform")
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf(`Project access token "%s" was rotated outside of Terraform`, name),
+				Detail:   "The new token value has been read into state.  Any change to its scopes or settings will be corrected on the next apply.",
+			})
+			d.SetId(pat.AccessToken)
+		}
+	}
 	if err == client.ErrNotFound {
 		d.SetId("")
 		l.Debug().Msg("Token not found on Rollbar - removed from state")
 		return nil
 	}
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	var mPat map[string]interface{}
@@ -183,28 +424,121 @@ func resourceProjectAccessTokenRead(ctx context.Context, d *schema.ResourceData,
 	for k, v := range mPat {
 		mustSet(d, k, v)
 	}
+	setTokenRotation(d, pat.DateCreated)
+
+	// adopt_existing is not known to the API.  Setting it explicitly ensures it
+	// is present in state after import.
+	mustSet(d, "adopt_existing", d.Get("adopt_existing").(bool))
 
 	return diags
 }
 
+// setTokenRotation sets the attributes following from the creation time
+// `dateCreated` of a token and its `rotation_days`.
+func setTokenRotation(d *schema.ResourceData, dateCreated int) {
+	rotationDays := d.Get("rotation_days").(int)
+	mustSet(d, "ready_for_rotation", tokenReadyForRotation(dateCreated, rotationDays))
+	createdAt, expiresAt := tokenTimestamps(dateCreated, rotationDays)
+	mustSet(d, "created_at", createdAt)
+	mustSet(d, "expires_at", expiresAt)
+}
+
+// tokenReadyForRotation returns true if a token created at Unix time
+// `dateCreated` is older than `rotationDays`.  A zero `rotationDays` disables
+// rotation.
+func tokenReadyForRotation(dateCreated int, rotationDays int) bool {
+	if rotationDays <= 0 {
+		return false
+	}
+	return !timeNow().Before(tokenExpiry(dateCreated, rotationDays))
+}
+
+// tokenExpiry returns the time after which a token created at Unix time
+// `dateCreated` is due for rotation.  The API has no notion of token
+// expiration, so expiry is enforced by the provider through rotation.
+func tokenExpiry(dateCreated int, rotationDays int) time.Time {
+	return time.Unix(int64(dateCreated), 0).Add(time.Duration(rotationDays) * 24 * time.Hour)
+}
+
+// tokenTimestamps returns the `created_at` and `expires_at` attributes of a
+// token.  `expires_at` is blank if rotation is disabled.
+func tokenTimestamps(dateCreated int, rotationDays int) (createdAt, expiresAt string) {
+	createdAt = time.Unix(int64(dateCreated), 0).UTC().Format(time.RFC3339)
+	if rotationDays > 0 {
+		expiresAt = tokenExpiry(dateCreated, rotationDays).UTC().Format(time.RFC3339)
+	}
+	return createdAt, expiresAt
+}
+
+// resourceProjectAccessTokenCustomizeDiff validates rate limits and forces
+// replacement of a token that is due for rotation.
+func resourceProjectAccessTokenCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	err := resourceProjectAccessTokenValidateRateLimit(d)
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	rotationDays := d.Get("rotation_days").(int)
+	dateCreated := d.Get("date_created").(int)
+	wasReady := d.Get("ready_for_rotation").(bool)
+	ready := tokenReadyForRotation(dateCreated, rotationDays)
+	if !wasReady || !ready {
+		if !d.HasChange("rotation_days") {
+			// Readiness is determined on refresh, so rotation waits for it.
+			return nil
+		}
+		// Update applies rotation_days without reading the token back, so
+		// plan the attributes following from it.
+		_, expiresAt := tokenTimestamps(dateCreated, rotationDays)
+		err = d.SetNew("expires_at", expiresAt)
+		if err != nil {
+			return err
+		}
+		return d.SetNew("ready_for_rotation", ready)
+	}
+	l := log.With().
+		Str("accessToken", client.RedactToken(d.Id())).
+		Int("rotation_days", rotationDays).
+		Logger()
+	l.Debug().Msg("Project access token is due for rotation")
+	err = d.SetNew("ready_for_rotation", false)
+	if err != nil {
+		return err
+	}
+	return d.ForceNew("ready_for_rotation")
+}
+
 func resourceProjectAccessTokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	accessToken := d.Id()
 	projectID := d.Get("project_id").(int)
 	size := d.Get("rate_limit_window_size").(int)
 	count := d.Get("rate_limit_window_count").(int)
+	status := client.Status(d.Get("status").(string))
 	args := client.ProjectAccessTokenUpdateArgs{
 		ProjectID:            projectID,
 		AccessToken:          accessToken,
 		RateLimitWindowSize:  size,
 		RateLimitWindowCount: count,
+		Status:               status,
 	}
 	l := log.With().Interface("args", args).Logger()
 	l.Debug().Msg("Updating resource project access token")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	err := c.UpdateProjectAccessToken(args)
+	// Changes to other attributes, such as rotation_days, need no API call
+	if !d.HasChanges("status", "rate_limit_window_size", "rate_limit_window_count") {
+		l.Debug().Msg("No remote change to project access token")
+		setTokenRotation(d, d.Get("date_created").(int))
+		return nil
+	}
+	c, err := clientFor(ctx, m, "rollbar_project_access_token", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = c.UpdateProjectAccessToken(args)
 	if err != nil {
 		log.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err, "status", "rate_limit_window_size", "rate_limit_window_count")
 	}
 	diags := resourceProjectAccessTokenRead(ctx, d, m)
 	return diags
@@ -216,25 +550,28 @@ func resourceProjectAccessTokenDelete(ctx context.Context, d *schema.ResourceDat
 
 	l := log.With().
 		Int("projectID", projectID).
-		Str("accessToken", accessToken).
+		Str("accessToken", client.RedactToken(accessToken)).
 		Logger()
 	l.Debug().Msg("Deleting resource project access token")
 
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	err := c.DeleteProjectAccessToken(projectID, accessToken)
+	c, err := clientFor(ctx, m, "rollbar_project_access_token", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = c.DeleteProjectAccessToken(projectID, accessToken)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
-func resourceProjectAccessTokenImporter(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	l := log.With().Str("id", d.Id()).Logger()
+func resourceProjectAccessTokenImporter(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	l := log.With().Str("id", client.RedactToken(d.Id())).Logger()
 	l.Debug().Msg("Importing resource rollbar project access token")
 	idParts := strings.Split(d.Id(), "/")
 	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		return nil, fmt.Errorf("unexpected format of ID (%q), expected PROJECT-ID/ACCESS-TOKEN", d.Id())
+		return nil, fmt.Errorf("unexpected format of ID (%q), expected PROJECT-ID/ACCESS-TOKEN", client.RedactToken(d.Id()))
 	}
 	projectIDString := idParts[0]
 	accessToken := idParts[1]
@@ -245,9 +582,34 @@ func resourceProjectAccessTokenImporter(_ context.Context, d *schema.ResourceDat
 	}
 	l.Debug().
 		Int("project_id", projectID).
-		Str("access_token", accessToken).
+		Str("access_token", client.RedactToken(accessToken)).
 		Send()
-	mustSet(d, "project_id", projectID)
-	d.SetId(accessToken)
+
+	// Resolve the token value, which may be given as the token's name, and
+	// store all its fields so the first plan after import is clean.
+	c, err := clientFor(ctx, meta, "rollbar_project_access_token", "read")
+	if err != nil {
+		return nil, err
+	}
+	pat, err := c.ReadProjectAccessToken(projectID, accessToken)
+	if err == client.ErrNotFound {
+		pat, err = c.ReadProjectAccessTokenByName(projectID, accessToken)
+	}
+	if err == client.ErrNotFound {
+		return nil, fmt.Errorf("could not find access token or token name %q in project %d", client.RedactToken(accessToken), projectID)
+	}
+	if err != nil {
+		l.Err(err).Send()
+		return nil, err
+	}
+	var mPat map[string]interface{}
+	mustDecodeMapStructure(pat, &mPat)
+	for k, v := range mPat {
+		mustSet(d, k, v)
+	}
+	mustSet(d, "rotation_days", 0)
+	mustSet(d, "adopt_existing", false)
+	mustSet(d, "ready_for_rotation", false)
+	d.SetId(pat.AccessToken)
 	return []*schema.ResourceData{d}, nil
 }
@@ -24,14 +24,24 @@ package rollbar
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"testing"
+	"time"
 )
 
 func (s *AccSuite) TestAccTokenImportInvalidID() {
@@ -51,9 +61,9 @@ func (s *AccSuite) TestAccTokenImportInvalidID() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -87,9 +97,9 @@ func (s *AccSuite) TestAccTokenImport() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -100,6 +110,19 @@ func (s *AccSuite) TestAccTokenImport() {
 				ImportStateIdFunc: importIdProjectAccessToken(rn),
 				ImportStateVerify: true,
 			},
+			// Import by name
+			{
+				ResourceName: rn,
+				ImportState:  true,
+				ImportStateIdFunc: func(ts *terraform.State) (string, error) {
+					rs, ok := ts.RootModule().Resources[rn]
+					if !ok {
+						return "", fmt.Errorf("not found: %s", rn)
+					}
+					return rs.Primary.Attributes["project_id"] + "/test-token", nil
+				},
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -139,15 +162,15 @@ func (s *AccSuite) TestAccTokenUpdateScope() {
 	`
 	config2 := fmt.Sprintf(tmpl2, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config1,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(rn, "scopes.#", `1`),
-					resource.TestCheckResourceAttr(rn, "scopes.0", "read"),
+					resource.TestCheckTypeSetElemAttr(rn, "scopes.*", "read"),
 				),
 			},
 			{
@@ -155,7 +178,7 @@ func (s *AccSuite) TestAccTokenUpdateScope() {
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "scopes.#", `1`),
-					resource.TestCheckResourceAttr(rn, "scopes.0", "post_server_item"),
+					resource.TestCheckTypeSetElemAttr(rn, "scopes.*", "post_server_item"),
 					s.checkProjectAccessToken(rn),
 				),
 			},
@@ -198,9 +221,9 @@ func (s *AccSuite) TestAccTokenUpdateRateLimit() {
 	`
 	config2 := fmt.Sprintf(tmpl2, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config1,
@@ -224,6 +247,64 @@ func (s *AccSuite) TestAccTokenUpdateRateLimit() {
 	})
 }
 
+// TestAccTokenUpdateStatus tests disabling and re-enabling a Rollbar project
+// access token without replacing it.
+func (s *AccSuite) TestAccTokenUpdateStatus() {
+	rn := "rollbar_project_access_token.test" // Resource name
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "test" {
+		  name         = "%s"
+		}
+
+		resource "rollbar_project_access_token" "test" {
+			project_id = rollbar_project.test.id
+			name = "test-token"
+			scopes = ["read"]
+			status = "%s"
+		}
+	`
+	var accessToken string
+	checkSameToken := func(ts *terraform.State) error {
+		return resource.TestCheckResourceAttr(rn, "access_token", accessToken)(ts)
+	}
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tmpl, s.randName, "enabled"),
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttr(rn, "status", "enabled"),
+					func(ts *terraform.State) error {
+						var err error
+						accessToken, err = s.getResourceIDString(ts, rn)
+						return err
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(tmpl, s.randName, "disabled"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rn, "status", "disabled"),
+					s.checkProjectAccessToken(rn),
+					checkSameToken,
+				),
+			},
+			{
+				Config: fmt.Sprintf(tmpl, s.randName, "enabled"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rn, "status", "enabled"),
+					s.checkProjectAccessToken(rn),
+					checkSameToken,
+				),
+			},
+		},
+	})
+}
+
 // TestAccTokenCreate tests creating a project access token.
 func (s *AccSuite) TestAccTokenCreate() {
 	projectResourceName := "rollbar_project.test"
@@ -243,12 +324,18 @@ func (s *AccSuite) TestAccTokenCreate() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(tokenResourceName, plancheck.ResourceActionCreate),
+						plancheck.ExpectUnknownValue(tokenResourceName, tfjsonpath.New("access_token")),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(tokenResourceName),
 					resource.TestCheckResourceAttrSet(tokenResourceName, "access_token"),
@@ -258,8 +345,13 @@ func (s *AccSuite) TestAccTokenCreate() {
 					resource.TestCheckResourceAttr(tokenResourceName, "rate_limit_window_size", "0"),
 					resource.TestCheckResourceAttr(tokenResourceName, "rate_limit_window_count", "0"),
 					resource.TestCheckResourceAttr(tokenResourceName, "scopes.#", `1`),
-					resource.TestCheckResourceAttr(tokenResourceName, "scopes.0", "read"),
+					resource.TestCheckTypeSetElemAttr(tokenResourceName, "scopes.*", "read"),
 				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(tokenResourceName, tfjsonpath.New("status"), knownvalue.StringExact("enabled")),
+					statecheck.ExpectKnownValue(tokenResourceName, tfjsonpath.New("scopes"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact("read")})),
+				},
 			},
 		},
 	})
@@ -290,9 +382,9 @@ func (s *AccSuite) TestAccTokenDelete() {
 	`
 	config2 := fmt.Sprintf(tmpl2, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config1,
@@ -329,13 +421,13 @@ func (s *AccSuite) TestAccTokenInvalidScope() {
 	config := fmt.Sprintf(tmpl, s.randName)
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config:      config,
-				ExpectError: regexp.MustCompile("invalid scope"),
+				ExpectError: regexp.MustCompile("Invalid scope"),
 			},
 		},
 	})
@@ -356,9 +448,9 @@ func (s *AccSuite) TestAccTokenCreateWithNonExistentProjectID() {
 		}
 	`
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				ExpectError: regexp.MustCompile("not found"),
@@ -395,9 +487,9 @@ func (s *AccSuite) TestAccTokenDeleteOnAPIBeforeApply() {
 	`
 	config := fmt.Sprintf(tmpl, projectName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
@@ -406,7 +498,7 @@ func (s *AccSuite) TestAccTokenDeleteOnAPIBeforeApply() {
 			// Before running Terraform, delete the token on Rollbar but not in local state
 			{
 				PreConfig: func() {
-					c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
 					var projectID int
 					projects, err := c.ListProjects()
 					s.Nil(err)
@@ -440,6 +532,71 @@ func (s *AccSuite) TestAccTokenDeleteOnAPIBeforeApply() {
 	})
 }
 
+// TestAccTokenRotatedOnAPIBeforeApply tests that a token rotated outside of
+// Terraform, keeping its name but getting a new value, is read into state
+// rather than recreated.
+func (s *AccSuite) TestAccTokenRotatedOnAPIBeforeApply() {
+	tokenResourceName := "rollbar_project_access_token.test"
+	projectName := s.randName + "-0"
+	var newToken string
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "test" {
+		  name         = "%s"
+		}
+
+		resource "rollbar_project_access_token" "test" {
+			project_id = rollbar_project.test.id
+			name = "test-token"
+			scopes = ["read"]
+		}
+	`
+	config := fmt.Sprintf(tmpl, projectName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			// Initial create
+			{
+				Config: config,
+			},
+			// Before running Terraform, replace the token on Rollbar with a new
+			// one of the same name, as rotating it in the UI would
+			{
+				PreConfig: func() {
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
+					p, err := c.GetProjectByName(projectName)
+					s.Nil(err)
+					old, err := c.ReadProjectAccessTokenByName(p.ID, "test-token")
+					s.Nil(err)
+					pat, err := c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+						ProjectID: p.ID,
+						Name:      "test-token",
+						Scopes:    []client.Scope{client.ScopeRead},
+						Status:    client.StatusEnabled,
+					})
+					s.Nil(err)
+					newToken = pat.AccessToken
+					err = c.DeleteProjectAccessToken(p.ID, old.AccessToken)
+					s.Nil(err)
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(tokenResourceName),
+					s.checkProjectAccessToken(tokenResourceName),
+					func(ts *terraform.State) error {
+						accessToken, err := s.getResourceIDString(ts, tokenResourceName)
+						s.Nil(err)
+						s.Equal(newToken, accessToken, "rotated token was not read into state")
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 // checkProjectAccessToken checks that a PAT exists and has the expected
 // properties.
 func (s *AccSuite) checkProjectAccessToken(resourceName string) resource.TestCheckFunc {
@@ -474,13 +631,15 @@ func (s *AccSuite) checkProjectAccessToken(resourceName string) resource.TestChe
 			return err
 		}
 		var scopes []client.Scope
-		for i := 0; i < scopesCount; i++ {
-			attr := "scopes." + strconv.Itoa(i)
-			scopeString := rs.Primary.Attributes[attr]
-			s := client.Scope(scopeString)
-			scopes = append(scopes, s)
+		for k, v := range rs.Primary.Attributes {
+			if strings.HasPrefix(k, "scopes.") && k != "scopes.#" {
+				scopes = append(scopes, client.Scope(v))
+			}
 		}
-		if !assert.ObjectsAreEqual(pat.Scopes, scopes) {
+		apiScopes := append([]client.Scope{}, pat.Scopes...)
+		sort.Slice(apiScopes, func(i, j int) bool { return apiScopes[i] < apiScopes[j] })
+		sort.Slice(scopes, func(i, j int) bool { return scopes[i] < scopes[j] })
+		if len(scopes) != scopesCount || !assert.ObjectsAreEqual(apiScopes, scopes) {
 			return fmt.Errorf("token scopes from API do not match token scopes in Terraform config")
 
 		}
@@ -564,3 +723,109 @@ func (s *AccSuite) checkNoUnexpectedTokens(projectResourceName string, expectedT
 		return nil
 	}
 }
+
+// TestTokenReadyForRotation tests deciding whether a token is due for rotation.
+func TestTokenReadyForRotation(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	day := 24 * 60 * 60
+	created := int(now.Unix())
+	assert.False(t, tokenReadyForRotation(created, 0))
+	assert.False(t, tokenReadyForRotation(created-100*day, 0))
+	assert.False(t, tokenReadyForRotation(created, 30))
+	assert.False(t, tokenReadyForRotation(created-29*day, 30))
+	assert.True(t, tokenReadyForRotation(created-30*day, 30))
+	assert.True(t, tokenReadyForRotation(created-31*day, 30))
+}
+
+// TestAccTokenAdoptExisting tests adopting a default token created by Rollbar.
+func (s *AccSuite) TestAccTokenAdoptExisting() {
+	rn := "rollbar_project_access_token.test"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "test" {
+			name                = "%s"
+			keep_default_tokens = true
+		}
+
+		resource "rollbar_project_access_token" "test" {
+			project_id     = rollbar_project.test.id
+			name           = "post_server_item"
+			scopes         = ["post_server_item"]
+			adopt_existing = true
+		}
+	`
+	config := fmt.Sprintf(tmpl, s.randName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttrPair(rn, "access_token", "rollbar_project.test", "post_server_item_access_token"),
+				),
+			},
+		},
+	})
+}
+
+func TestTokenTimestamps(t *testing.T) {
+	created := 1600000000
+	createdAt, expiresAt := tokenTimestamps(created, 0)
+	assert.Equal(t, "2020-09-13T12:26:40Z", createdAt)
+	assert.Equal(t, "", expiresAt)
+	createdAt, expiresAt = tokenTimestamps(created, 30)
+	assert.Equal(t, "2020-09-13T12:26:40Z", createdAt)
+	assert.Equal(t, "2020-10-13T12:26:40Z", expiresAt)
+}
+
+// TestProjectAccessTokenValidateScope tests plan-time validation of scopes.
+func TestProjectAccessTokenValidateScope(t *testing.T) {
+	p := cty.GetAttrPath("scopes").IndexInt(0)
+	for _, v := range []string{"read", "post_server_item", "post_client_item"} {
+		assert.Nil(t, resourceProjectAccessTokenValidateScope(v, p))
+	}
+	diags := resourceProjectAccessTokenValidateScope("write", p)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.False(t, diags.HasError())
+	diags = resourceProjectAccessTokenValidateScope("avocado", p)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, `Invalid scope: "avocado"`, diags[0].Summary)
+	assert.Equal(t, p, diags[0].AttributePath)
+}
+
+// TestProjectAccessTokenValidateRateLimit tests plan-time validation of rate
+// limit window size and count.
+func TestProjectAccessTokenValidateRateLimit(t *testing.T) {
+	p := cty.GetAttrPath("rate_limit_window_size")
+	for _, v := range []int{0, 60, 300, 1800, 3600, 86400, 604800, 2592000} {
+		assert.Nil(t, resourceProjectAccessTokenValidateRateLimitSize(v, p))
+	}
+	for _, v := range []int{-1, 1, 61, 7200} {
+		diags := resourceProjectAccessTokenValidateRateLimitSize(v, p)
+		assert.Len(t, diags, 1)
+		assert.Equal(t, p, diags[0].AttributePath)
+	}
+
+	p = cty.GetAttrPath("rate_limit_window_count")
+	assert.Nil(t, resourceProjectAccessTokenValidateRateLimitCount(0, p))
+	assert.Nil(t, resourceProjectAccessTokenValidateRateLimitCount(500, p))
+	diags := resourceProjectAccessTokenValidateRateLimitCount(-1, p)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Invalid rate_limit_window_count: -1", diags[0].Summary)
+	assert.Equal(t, p, diags[0].AttributePath)
+}
+
+func TestSameScopes(t *testing.T) {
+	read, write := client.ScopeRead, client.ScopeWrite
+	assert.True(t, sameScopes([]client.Scope{read, write}, []client.Scope{write, read}))
+	assert.True(t, sameScopes([]client.Scope{read, read}, []client.Scope{read}))
+	assert.False(t, sameScopes([]client.Scope{read}, []client.Scope{read, write}))
+	assert.False(t, sameScopes([]client.Scope{read, write}, []client.Scope{read}))
+}
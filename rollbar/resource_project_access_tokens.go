@@ -0,0 +1,393 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// projectAccessTokensIdentity identifies a rollbar_project_access_tokens in
+// import blocks.
+var projectAccessTokensIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "project_id", attr: "project_id", typ: schema.TypeInt, description: "ID of the project"},
+}}
+
+// projectDefaultTokenNames lists the names of the access tokens Rollbar creates
+// with a project.
+var projectDefaultTokenNames = []string{"read", "write", "post_server_item", "post_client_item"}
+
+// resourceProjectAccessTokens constructs a resource authoritatively managing
+// all the access tokens of a project.
+func resourceProjectAccessTokens() *schema.Resource {
+	return withIdentity(&schema.Resource{
+		CreateContext: resourceProjectAccessTokensCreateOrUpdate,
+		UpdateContext: resourceProjectAccessTokensCreateOrUpdate,
+		ReadContext:   resourceProjectAccessTokensRead,
+		DeleteContext: resourceProjectAccessTokensDelete,
+
+		CustomizeDiff: resourceProjectAccessTokensCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProjectAccessTokensImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Description: "ID of the Rollbar project",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"token": {
+				Description: "Access tokens the project should have.  Tokens not listed, nor named in `ignore_names`, nor kept by `keep_default_tokens`, are deleted.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The human readable name for the token, unique within the project",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"scopes": {
+							Description: fmt.Sprintf("Set of access scopes granted to the token.  Possible values are %s.  Changing them replaces the token.", quotedList(client.ScopeStrings(), "and")),
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: resourceProjectAccessTokenValidateScope,
+							},
+						},
+						"status": {
+							Description:  `Status of the token.  Possible values are "enabled" and "disabled"`,
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "enabled",
+							ValidateFunc: validation.StringInSlice([]string{string(client.StatusEnabled), string(client.StatusDisabled)}, false),
+						},
+						"rate_limit_window_count": {
+							Description:      "Total number of calls allowed within the rate limit window.  0 means unlimited",
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          0,
+							ValidateDiagFunc: resourceProjectAccessTokenValidateRateLimitCount,
+						},
+						"rate_limit_window_size": {
+							Description:      "Total number of seconds that makes up the rate limit window",
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          0,
+							ValidateDiagFunc: resourceProjectAccessTokenValidateRateLimitSize,
+						},
+					},
+				},
+			},
+			"ignore_names": {
+				Description: "Names of tokens left alone, neither managed nor deleted",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"keep_default_tokens": {
+				Description: "Leave alone the `read`, `write`, `post_server_item` and `post_client_item` tokens Rollbar creates with a project, unless configured in `token`",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			// Computed fields
+			"access_tokens": {
+				Description: "Map of token names to access tokens",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}, projectAccessTokensIdentity)
+}
+
+// resourceProjectAccessTokensCustomizeDiff checks that token names are unique
+// and not ignored.
+func resourceProjectAccessTokensCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("token") || !d.NewValueKnown("ignore_names") {
+		return nil
+	}
+	ignored := d.Get("ignore_names").(*schema.Set)
+	seen := make(map[string]bool)
+	for _, t := range d.Get("token").([]interface{}) {
+		token, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := token["name"].(string)
+		switch {
+		case name == "":
+			continue
+		case seen[name]:
+			return fmt.Errorf("duplicate token name %q", name)
+		case ignored.Contains(name):
+			return fmt.Errorf("token %q is also in ignore_names", name)
+		}
+		seen[name] = true
+		size, _ := token["rate_limit_window_size"].(int)
+		count, _ := token["rate_limit_window_count"].(int)
+		if (size == 0) != (count == 0) {
+			return fmt.Errorf("token %q: rate_limit_window_size and rate_limit_window_count must both be zero or both be non-zero", name)
+		}
+	}
+	return nil
+}
+
+// resourceProjectAccessTokensImport imports the access tokens of a project by
+// its ID.  As `keep_default_tokens` is not yet in state, it is set to its
+// default so the default tokens are not read into `token`.
+func resourceProjectAccessTokensImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	mustSet(d, "keep_default_tokens", true)
+	return importNumericID(ctx, d, m)
+}
+
+// projectAccessTokensIgnored returns a function reporting whether an existing
+// token is left alone: its name is in `ignore_names`, or it is a default token
+// not configured in `token` while `keep_default_tokens` is set.
+func projectAccessTokensIgnored(d *schema.ResourceData) func(name string) bool {
+	ignored := d.Get("ignore_names").(*schema.Set)
+	keepDefaults := d.Get("keep_default_tokens").(bool)
+	configured := make(map[string]bool)
+	for _, t := range d.Get("token").([]interface{}) {
+		if token, ok := t.(map[string]interface{}); ok {
+			configured[token["name"].(string)] = true
+		}
+	}
+	return func(name string) bool {
+		return ignored.Contains(name) ||
+			keepDefaults && !configured[name] && find(projectDefaultTokenNames, name)
+	}
+}
+
+// expandProjectAccessTokensArgs returns the creation arguments of a `token`
+// element.
+func expandProjectAccessTokensArgs(projectID int, token map[string]interface{}) client.ProjectAccessTokenCreateArgs {
+	scopes := []client.Scope{}
+	for _, s := range token["scopes"].(*schema.Set).List() {
+		scopes = append(scopes, client.Scope(s.(string)))
+	}
+	return client.ProjectAccessTokenCreateArgs{
+		ProjectID:            projectID,
+		Name:                 token["name"].(string),
+		Scopes:               scopes,
+		Status:               client.Status(token["status"].(string)),
+		RateLimitWindowSize:  token["rate_limit_window_size"].(int),
+		RateLimitWindowCount: token["rate_limit_window_count"].(int),
+	}
+}
+
+// resourceProjectAccessTokensCreateOrUpdate makes the access tokens of a
+// project match the configured tokens.  Existing tokens are matched by name:
+// they are updated in place, or replaced if their scopes differ.  Tokens
+// neither configured nor ignored are deleted, as are any further tokens with
+// the name of a configured token, which the API does not prevent.
+func resourceProjectAccessTokensCreateOrUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	projectID := d.Get("project_id").(int)
+	ignored := projectAccessTokensIgnored(d)
+	l := log.With().Int("project_id", projectID).Logger()
+	l.Info().Msg("Applying rollbar_project_access_tokens resource")
+
+	c, err := clientFor(ctx, m, "rollbar_project_access_tokens", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	existing, err := c.ListProjectAccessTokens(projectID)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err, "project_id")
+	}
+	byName := make(map[string]client.ProjectAccessToken, len(existing))
+	for _, t := range existing {
+		if _, ok := byName[t.Name]; !ok {
+			byName[t.Name] = t
+		}
+	}
+	d.SetId(strconv.Itoa(projectID))
+
+	configured := make(map[string]bool)
+	for _, t := range d.Get("token").([]interface{}) {
+		args := expandProjectAccessTokensArgs(projectID, t.(map[string]interface{}))
+		configured[args.Name] = true
+		tl := l.With().Str("name", args.Name).Logger()
+		current, exists := byName[args.Name]
+		if exists && !sameScopes(current.Scopes, args.Scopes) {
+			tl.Debug().Msg("Replacing project access token with new scopes")
+			err = c.DeleteProjectAccessToken(projectID, current.AccessToken)
+			if err != nil {
+				tl.Err(err).Send()
+				return withPartialState(ctx, d, m, resourceProjectAccessTokensRead, diagFromErr(err, "token"))
+			}
+			exists = false
+		}
+		switch {
+		case !exists:
+			_, err = c.CreateProjectAccessToken(args)
+		case current.Status != args.Status ||
+			current.RateLimitWindowSize != args.RateLimitWindowSize ||
+			current.RateLimitWindowCount != args.RateLimitWindowCount:
+			err = c.UpdateProjectAccessToken(client.ProjectAccessTokenUpdateArgs{
+				ProjectID:            projectID,
+				AccessToken:          current.AccessToken,
+				RateLimitWindowSize:  args.RateLimitWindowSize,
+				RateLimitWindowCount: args.RateLimitWindowCount,
+				Status:               args.Status,
+			})
+		}
+		if err != nil {
+			tl.Err(err).Send()
+			return withPartialState(ctx, d, m, resourceProjectAccessTokensRead, diagFromErr(err, "token"))
+		}
+	}
+	for _, t := range existing {
+		matched := configured[t.Name] && byName[t.Name].AccessToken == t.AccessToken
+		if matched || ignored(t.Name) {
+			continue
+		}
+		err = c.DeleteProjectAccessToken(projectID, t.AccessToken)
+		if err != nil {
+			l.Err(err).Str("name", t.Name).Send()
+			return withPartialState(ctx, d, m, resourceProjectAccessTokensRead, diagFromErr(err))
+		}
+		if configured[t.Name] {
+			l.Debug().Str("name", t.Name).Msg("Deleted duplicate project access token")
+		} else {
+			l.Debug().Str("name", t.Name).Msg("Deleted unmanaged project access token")
+		}
+	}
+	l.Debug().Msg("Successfully applied rollbar_project_access_tokens resource")
+	return resourceProjectAccessTokensRead(ctx, d, m)
+}
+
+func resourceProjectAccessTokensRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	projectID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	ignored := projectAccessTokensIgnored(d)
+	l := log.With().Int("project_id", projectID).Logger()
+	l.Info().Msg("Reading rollbar_project_access_tokens resource")
+	c, err := clientFor(ctx, m, "rollbar_project_access_tokens", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tokens, err := c.ListProjectAccessTokens(projectID)
+	if err == client.ErrNotFound {
+		d.SetId("")
+		l.Info().Msg("Project not found - removed from state")
+		return nil
+	}
+	if err != nil {
+		l.Err(err).Msg("Error reading rollbar_project_access_tokens resource")
+		return diagFromErr(err)
+	}
+
+	// Keep the configured order, followed by any unmanaged tokens by name
+	order := make(map[string]int)
+	for i, t := range d.Get("token").([]interface{}) {
+		if token, ok := t.(map[string]interface{}); ok {
+			order[token["name"].(string)] = i
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		oi, iok := order[tokens[i].Name]
+		oj, jok := order[tokens[j].Name]
+		if iok != jok {
+			return iok
+		}
+		if iok {
+			return oi < oj
+		}
+		return tokens[i].Name < tokens[j].Name
+	})
+	list := []interface{}{}
+	accessTokens := make(map[string]interface{})
+	for _, t := range tokens {
+		if ignored(t.Name) {
+			continue
+		}
+		scopes := make([]interface{}, len(t.Scopes))
+		for i, s := range t.Scopes {
+			scopes[i] = string(s)
+		}
+		list = append(list, map[string]interface{}{
+			"name":                    t.Name,
+			"scopes":                  scopes,
+			"status":                  string(t.Status),
+			"rate_limit_window_count": t.RateLimitWindowCount,
+			"rate_limit_window_size":  t.RateLimitWindowSize,
+		})
+		accessTokens[t.Name] = t.AccessToken
+	}
+	mustSet(d, "project_id", projectID)
+	mustSet(d, "token", list)
+	mustSet(d, "access_tokens", accessTokens)
+	l.Debug().Int("count", len(list)).Msg("Successfully read rollbar_project_access_tokens resource")
+	return nil
+}
+
+func resourceProjectAccessTokensDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	projectID := d.Get("project_id").(int)
+	ignored := projectAccessTokensIgnored(d)
+	l := log.With().Int("project_id", projectID).Logger()
+	l.Info().Msg("Deleting rollbar_project_access_tokens resource")
+	c, err := clientFor(ctx, m, "rollbar_project_access_tokens", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tokens, err := c.ListProjectAccessTokens(projectID)
+	if err == client.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err)
+	}
+	for _, t := range tokens {
+		if ignored(t.Name) {
+			continue
+		}
+		err = c.DeleteProjectAccessToken(projectID, t.AccessToken)
+		if err != nil {
+			l.Err(err).Str("name", t.Name).Msg("Error deleting rollbar_project_access_tokens resource")
+			return diagFromErr(err)
+		}
+	}
+	l.Debug().Msg("Successfully deleted rollbar_project_access_tokens resource")
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokenNames returns the names of a fake project's access tokens, sorted.
+func fakeTokenNames(f *fakeAPI, projectID int) []string {
+	names := []string{}
+	for _, t := range f.tokens[projectID] {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestOfflineProjectAccessTokensCRUD tests authoritatively managing the access
+// tokens of a project.
+func TestOfflineProjectAccessTokensCRUD(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProjectAccessTokens()
+	ctx := context.Background()
+
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+	serverToken, err := c.ReadProjectAccessTokenByName(p.ID, "post_server_item")
+	require.NoError(t, err)
+	config := map[string]interface{}{
+		"project_id": p.ID,
+		"token": []interface{}{
+			map[string]interface{}{"name": "ci", "scopes": []interface{}{"read"}},
+			map[string]interface{}{"name": "post_server_item", "scopes": []interface{}{"post_server_item"}},
+		},
+		"ignore_names": []interface{}{"post_client_item"},
+	}
+
+	// Default tokens not configured are kept by default
+	d := offlineApply(t, r, r.TestResourceData(), m, config)
+	assert.Equal(t, []string{"ci", "post_client_item", "post_server_item", "read", "write"}, fakeTokenNames(f, p.ID))
+	assert.Equal(t, serverToken.AccessToken, d.Get("access_tokens.post_server_item"), "unchanged tokens are kept")
+	ciToken := d.Get("access_tokens.ci").(string)
+	assert.NotEmpty(t, ciToken)
+	assert.Equal(t, "ci", d.Get("token.0.name"))
+	assert.Equal(t, 2, d.Get("token.#"))
+
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes, "no changes after apply")
+
+	// Tokens created outside Terraform show up as drift and are deleted
+	_, err = c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+		ProjectID: p.ID,
+		Name:      "click-ops",
+		Scopes:    []client.Scope{client.ScopeWrite},
+		Status:    client.StatusEnabled,
+	})
+	require.NoError(t, err)
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, 3, d.Get("token.#"))
+	assert.Equal(t, "click-ops", d.Get("token.2.name"))
+
+	// Changing scopes replaces a token; other changes update it in place
+	config["token"] = []interface{}{
+		map[string]interface{}{"name": "ci", "scopes": []interface{}{"read", "write"}},
+		map[string]interface{}{"name": "post_server_item", "scopes": []interface{}{"post_server_item"}, "status": "disabled"},
+	}
+	d = offlineApply(t, r, d, m, config)
+	assert.Equal(t, []string{"ci", "post_client_item", "post_server_item", "read", "write"}, fakeTokenNames(f, p.ID))
+	assert.NotEqual(t, ciToken, d.Get("access_tokens.ci"))
+	assert.Equal(t, client.StatusDisabled, f.tokens[p.ID][serverToken.AccessToken].Status)
+
+	// Unless asked to keep them, default tokens not configured nor ignored
+	// are deleted
+	config["keep_default_tokens"] = false
+	d = offlineApply(t, r, d, m, config)
+	assert.Equal(t, []string{"ci", "post_client_item", "post_server_item"}, fakeTokenNames(f, p.ID))
+
+	require.False(t, r.DeleteContext(ctx, d, m).HasError())
+	assert.Equal(t, []string{"post_client_item"}, fakeTokenNames(f, p.ID))
+}
+
+// TestOfflineProjectAccessTokensDuplicateNames tests that further tokens with
+// the name of a configured token, which the API allows, are deleted.
+func TestOfflineProjectAccessTokensDuplicateNames(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	r := resourceProjectAccessTokens()
+	ctx := context.Background()
+
+	p, err := c.CreateProject("offline-project")
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = c.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+			ProjectID: p.ID,
+			Name:      "ci",
+			Scopes:    []client.Scope{client.ScopeRead},
+			Status:    client.StatusEnabled,
+		})
+		require.NoError(t, err)
+	}
+	config := map[string]interface{}{
+		"project_id": p.ID,
+		"token": []interface{}{
+			map[string]interface{}{"name": "ci", "scopes": []interface{}{"read"}},
+		},
+	}
+	d := offlineApply(t, r, r.TestResourceData(), m, config)
+	assert.Equal(t, []string{"ci", "post_client_item", "post_server_item", "read", "write"}, fakeTokenNames(f, p.ID))
+	assert.Equal(t, 1, d.Get("token.#"))
+
+	diff, err := r.SimpleDiff(ctx, d.State(), sdkterraform.NewResourceConfigRaw(config), m)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Attributes, "no changes after apply")
+
+	// Importing leaves the default tokens out of state too
+	d = r.TestResourceData()
+	d.SetId(strconv.Itoa(p.ID))
+	result, err := r.Importer.StateContext(ctx, d, m)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	d = result[0]
+	require.False(t, r.ReadContext(ctx, d, m).HasError())
+	assert.Equal(t, 1, d.Get("token.#"))
+	assert.Equal(t, "ci", d.Get("token.0.name"))
+}
+
+// TestOfflineProjectAccessTokensInvalid tests plan time validation of the
+// configured tokens.
+func TestOfflineProjectAccessTokensInvalid(t *testing.T) {
+	m := offlineMeta(newFakeAPI(t))
+	r := resourceProjectAccessTokens()
+	token := func(name string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "scopes": []interface{}{"read"}}
+	}
+	cases := []struct {
+		config map[string]interface{}
+		msg    string
+	}{
+		{map[string]interface{}{
+			"project_id": 1,
+			"token":      []interface{}{token("ci"), token("ci")},
+		}, `duplicate token name "ci"`},
+		{map[string]interface{}{
+			"project_id":   1,
+			"token":        []interface{}{token("ci")},
+			"ignore_names": []interface{}{"ci"},
+		}, `token "ci" is also in ignore_names`},
+		{map[string]interface{}{
+			"project_id": 1,
+			"token":      []interface{}{map[string]interface{}{"name": "ci", "scopes": []interface{}{"read"}, "rate_limit_window_size": 60}},
+		}, `token "ci": rate_limit_window_size and rate_limit_window_count must both be zero or both be non-zero`},
+	}
+	for _, tc := range cases {
+		_, err := r.SimpleDiff(context.Background(), r.TestResourceData().State(), sdkterraform.NewResourceConfigRaw(tc.config), m)
+		assert.EqualError(t, err, tc.msg)
+	}
+}
@@ -23,19 +23,96 @@
 package rollbar
 
 import (
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
 	"os"
 	"strings"
+	"testing"
 )
 
 func init() {
 	resource.AddTestSweepers("rollbar_project", &resource.Sweeper{
-		Name: "rollbar_project",
-		F:    sweepResourceProject,
+		Name:         "rollbar_project",
+		F:            sweepResourceProject,
+		Dependencies: []string{"rollbar_project_access_token"},
+	})
+}
+
+// TestAccProjectKeepDefaultTokens tests exposing the default post tokens of a
+// Rollbar project, and purging them when the project is destroyed.
+func (s *AccSuite) TestAccProjectKeepDefaultTokens() {
+	rn := "rollbar_project.foo"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "foo" {
+			name                    = "%s"
+			keep_default_tokens     = true
+			purge_tokens_on_destroy = true
+		}
+	`
+	config := fmt.Sprintf(tmpl, s.randName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttrSet(rn, "post_server_item_access_token"),
+					resource.TestCheckResourceAttrSet(rn, "post_client_item_access_token"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccProjectAllowExisting tests adopting an existing Rollbar project.
+func (s *AccSuite) TestAccProjectAllowExisting() {
+	rn := "rollbar_project.foo"
+	var projectID int
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "foo" {
+			name           = "%s"
+			allow_existing = true
+		}
+	`
+	config := fmt.Sprintf(tmpl, s.randName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
+					p, err := c.CreateProject(s.randName)
+					s.Nil(err)
+					projectID = p.ID
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					func(ts *terraform.State) error {
+						id, err := s.getResourceIDInt(ts, rn)
+						s.Nil(err)
+						s.Equal(projectID, id, "existing project was not adopted")
+						return nil
+					},
+				),
+			},
+		},
 	})
 }
 
@@ -44,25 +121,85 @@ func (s *AccSuite) TestAccProject() {
 	rn := "rollbar_project.foo"
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: s.configResourceProject(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "name", s.randName),
 					s.checkProjectExists(rn, s.randName),
 					s.checkProjectInProjectList(rn),
+					s.checkProjectHasNoTokens(rn),
 				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("name"), knownvalue.StringExact(s.randName)),
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("status"), knownvalue.StringExact("enabled")),
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("team_ids"), knownvalue.SetSizeExact(0)),
+				},
 			},
 			{
 				ResourceName:      rn,
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateId:     s.randName,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccProjectUpdateName tests renaming a Rollbar project in place.
+func (s *AccSuite) TestAccProjectUpdateName() {
+	rn := "rollbar_project.foo"
+	name2 := s.randName + "-renamed"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_project" "foo" {
+		  name         = "%s"
+		}
+	`
+	var projectID string
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: s.configResourceProject(),
+				Check: func(ts *terraform.State) error {
+					var err error
+					projectID, err = s.getResourceIDString(ts, rn)
+					return err
+				},
+			},
+			{
+				Config: fmt.Sprintf(tmpl, name2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttr(rn, "name", name2),
+					s.checkProjectExists(rn, name2),
+					func(ts *terraform.State) error {
+						return resource.TestCheckResourceAttr(rn, "id", projectID)(ts)
+					},
+				),
+			},
 		},
 	})
 }
@@ -85,9 +222,9 @@ func (s *AccSuite) TestAccTeamAssignProject() {
 	`
 	config := fmt.Sprintf(tmpl, teamName, projectName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -143,9 +280,9 @@ func (s *AccSuite) TestAccProjectAddTeam() {
 	config2 := fmt.Sprintf(tmpl2, team1Name, team2Name, projectName)
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config1,
@@ -208,9 +345,9 @@ func (s *AccSuite) TestAccProjectRemoveTeam() {
 	config2 := fmt.Sprintf(tmpl2, team1Name, projectName)
 
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config1,
@@ -243,10 +380,9 @@ func (s *AccSuite) TestAccProjectDeleteOnAPIBeforeApply() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
@@ -255,7 +391,7 @@ func (s *AccSuite) TestAccProjectDeleteOnAPIBeforeApply() {
 			// Before running Terraform, delete the project on Rollbar but not in local state
 			{
 				PreConfig: func() {
-					c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
 					projects, err := c.ListProjects()
 					s.Nil(err)
 					for _, p := range projects {
@@ -328,12 +464,34 @@ func (s *AccSuite) checkProjectInProjectList(rn string) resource.TestCheckFunc {
 	}
 }
 
+// checkProjectHasNoTokens tests that the default access tokens Rollbar creates
+// with a project have been deleted.
+func (s *AccSuite) checkProjectHasNoTokens(rn string) resource.TestCheckFunc {
+	return func(ts *terraform.State) error {
+		id, err := s.getResourceIDInt(ts, rn)
+		if err != nil {
+			return err
+		}
+		c := s.provider.Meta().(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+		tokens, err := c.ListProjectAccessTokens(id)
+		if err != nil {
+			return err
+		}
+		if len(tokens) > 0 {
+			return fmt.Errorf("default access tokens were not deleted: %d remain", len(tokens))
+		}
+		return nil
+	}
+}
+
 // sweepResourceProject cleans up orphaned projects created by failed acceptance
 // test runs.
 func sweepResourceProject(_ string) error {
 	log.Info().Msg("Cleaning up Rollbar projects from acceptance test runs.")
 
-	c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+	prefix := sweepPrefix()
+
+	c := sweepClient()
 	projects, err := c.ListProjects()
 	if err != nil {
 		log.Err(err).Send()
@@ -346,7 +504,7 @@ func sweepResourceProject(_ string) error {
 			Str("name", p.Name).
 			Int("id", p.ID).
 			Logger()
-		if strings.HasPrefix(p.Name, "tf-acc-test-") {
+		if strings.HasPrefix(p.Name, prefix) {
 			err = c.DeleteProject(p.ID)
 			if err != nil {
 				l.Err(err).Send()
@@ -376,3 +534,32 @@ func (s *AccSuite) checkProjectTeams(projectResourceName string) resource.TestCh
 		return nil
 	}
 }
+
+// TestProjectDeleteProtection tests that a protected project is not deleted.
+func TestProjectDeleteProtection(t *testing.T) {
+	d := resourceProject().TestResourceData()
+	d.SetId("411703")
+	mustSet(d, "delete_protection", true)
+	diags := resourceProjectDelete(context.Background(), d, nil) // nil client must not be used
+	assert.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Detail, "delete_protection = false")
+}
+
+func TestProjectImporterNumericID(t *testing.T) {
+	d := resourceProject().TestResourceData()
+	d.SetId("411703")
+	ds, err := resourceProjectImporter(context.Background(), d, nil)
+	assert.Nil(t, err)
+	assert.Len(t, ds, 1)
+	assert.Equal(t, "411703", ds[0].Id())
+}
+
+func TestDefaultPostTokens(t *testing.T) {
+	tokens := []client.ProjectAccessToken{
+		{Name: "read", AccessToken: "aaa", Status: client.StatusEnabled},
+		{Name: "post_server_item", AccessToken: "bbb", Status: client.StatusEnabled},
+		{Name: "post_client_item", AccessToken: "ccc", Status: client.StatusDisabled},
+	}
+	expected := map[string]string{"post_server_item": "bbb"}
+	assert.Equal(t, expected, defaultPostTokens(tokens))
+}
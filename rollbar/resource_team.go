@@ -11,34 +11,53 @@ import (
 	"strconv"
 )
 
+// teamIdentity identifies a rollbar_team in import blocks.
+var teamIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "id", typ: schema.TypeInt, description: "The team's numeric ID"},
+}}
+
 // resourceTeam constructs a resource representing a Rollbar team.
 func resourceTeam() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceTeamCreate,
 		ReadContext:   resourceTeamRead,
+		UpdateContext: resourceTeamUpdate,
 		DeleteContext: resourceTeamDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNumericID,
 		},
 
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
 		Schema: map[string]*schema.Schema{
 			// Required
 			"name": {
 				Description: "Human readable name for the team",
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 			},
 
 			// Optional
 			"access_level": {
-				Description:      `The team's access level.  Must be "standard", "light", or "view".  Defaults to "standard".`,
+				Description:      fmt.Sprintf(`The team's access level.  Must be %s.  Defaults to "%s".`, quotedList(client.TeamAccessLevels, "or"), client.TeamAccessLevelStandard),
 				Type:             schema.TypeString,
 				Optional:         true,
-				Default:          "standard",
-				ForceNew:         true,
-				ValidateDiagFunc: resourceTeamValidateAccessLevel,
+				Default:          client.TeamAccessLevelStandard,
+				ValidateDiagFunc: validateTeamAccessLevel,
+			},
+			"allow_existing": {
+				Description: "If a team with the same name already exists, adopt it rather than failing",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"user_emails": {
+				Description: "Email addresses of all members of the team.  If not empty, registered users are assigned to the team, other addresses are invited, and members not listed are removed",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 
 			// Computed
@@ -48,24 +67,24 @@ func resourceTeam() *schema.Resource {
 				Computed:    true,
 			},
 		},
-	}
+	}, teamIdentity)
 }
 
-func resourceTeamValidateAccessLevel(v interface{}, p cty.Path) diag.Diagnostics {
+// validateTeamAccessLevel validates a team access level against the levels
+// known to the client.  It is shared by all resources and data sources that
+// accept an access level.
+func validateTeamAccessLevel(v interface{}, p cty.Path) diag.Diagnostics {
 	s := v.(string)
-	switch s {
-	case "standard", "light", "view":
+	if client.ValidTeamAccessLevel(s) {
 		return nil
-	default:
-		summary := fmt.Sprintf(`Invalid access_level: "%s"`, s)
-		d := diag.Diagnostic{
-			Severity:      diag.Error,
-			AttributePath: p,
-			Summary:       summary,
-			Detail:        `Must be "standard", "light", or "view"`,
-		}
-		return diag.Diagnostics{d}
 	}
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		AttributePath: p,
+		Summary:       fmt.Sprintf(`Invalid access_level: "%s"`, s),
+		Detail:        fmt.Sprintf("Must be %s", quotedList(client.TeamAccessLevels, "or")),
+	}
+	return diag.Diagnostics{d}
 }
 
 func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -73,15 +92,44 @@ func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface
 	level := d.Get("access_level").(string)
 	l := log.With().Str("name", name).Str("access_level", level).Logger()
 	l.Info().Msg("Creating rollbar_team resource")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_team", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("allow_existing").(bool) {
+		teamID, err := c.FindTeamID(name)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Send()
+			return diagFromErr(err, "name")
+		}
+		if err == nil {
+			// Adopt the existing team, converging its access level
+			l = l.With().Int("teamID", teamID).Logger()
+			l.Debug().Msg("Adopting existing team")
+			_, err = c.UpdateTeam(teamID, name, level)
+			if err != nil {
+				l.Err(err).Send()
+				return diagFromErr(err, "access_level")
+			}
+			d.SetId(strconv.Itoa(teamID))
+			if diags := resourceTeamConvergeMembers(ctx, d, m); diags.HasError() {
+				return diags
+			}
+			l.Debug().Msg("Successfully adopted existing team")
+			return resourceTeamRead(ctx, d, m)
+		}
+	}
 	t, err := c.CreateTeam(name, level)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err, "name", "access_level")
 	}
 	teamID := t.ID
 	l = l.With().Int("teamID", teamID).Logger()
 	d.SetId(strconv.Itoa(teamID))
+	if diags := resourceTeamConvergeMembers(ctx, d, m); diags.HasError() {
+		return diags
+	}
 	l.Debug().Int("id", teamID).Msg("Successfully created rollbar_team resource")
 	return resourceTeamRead(ctx, d, m)
 }
@@ -92,7 +140,10 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		Int("id", id).
 		Logger()
 	l.Info().Msg("Reading rollbar_team resource")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, m, "rollbar_team", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	t, err := c.ReadTeam(id)
 	if err == client.ErrNotFound {
 		d.SetId("")
@@ -101,26 +152,103 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	}
 	if err != nil {
 		l.Err(err).Msg("error reading rollbar_team resource")
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	mustSet(d, "name", t.Name)
 	mustSet(d, "account_id", t.AccountID)
 	mustSet(d, "access_level", t.AccessLevel)
+	// allow_existing is not known to the API.  Setting it explicitly ensures it
+	// is present in state after import.
+	mustSet(d, "allow_existing", d.Get("allow_existing").(bool))
+
+	// Members are read only if managed inline, i.e. user_emails is in state,
+	// sparing each unmanaged team the extra API calls.
+	if known := setToStrings(d.Get("user_emails").(*schema.Set)); len(known) > 0 {
+		members, _, err := teamMembers(c, id, nil)
+		if err != nil {
+			l.Err(err).Msg("error reading members of rollbar_team resource")
+			return diagFromErr(err)
+		}
+		mustSet(d, "user_emails", memberEmails(members, known, true))
+	}
 	l.Debug().Msg("Successfully read rollbar_team resource")
 	return nil
 }
 
+// resourceTeamUpdate handles update for a `rollbar_team` resource.  Renaming a
+// team updates it in place, preserving its memberships and project
+// assignments.
+func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id := mustGetID(d)
+	name := d.Get("name").(string)
+	level := d.Get("access_level").(string)
+	l := log.With().
+		Int("id", id).
+		Str("name", name).
+		Str("access_level", level).
+		Logger()
+	l.Info().Msg("Updating rollbar_team resource")
+	c, err := clientFor(ctx, m, "rollbar_team", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.HasChanges("name", "access_level") {
+		_, err := c.UpdateTeam(id, name, level)
+		if err != nil {
+			l.Err(err).Msg("Error updating rollbar_team resource")
+			return diagFromErr(err, "name", "access_level")
+		}
+	}
+	if d.HasChange("user_emails") {
+		if diags := resourceTeamConvergeMembers(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+	l.Debug().Msg("Successfully updated rollbar_team resource")
+	return resourceTeamRead(ctx, d, m)
+}
+
 func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	id := mustGetID(d)
 
 	l := log.With().Int("id", id).Logger()
 	l.Info().Msg("Deleting rollbar_team resource")
-	c := m.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	err := c.DeleteTeam(id)
+	c, err := clientFor(ctx, m, "rollbar_team", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = c.DeleteTeam(id)
 	if err != nil {
 		l.Err(err).Msg("Error deleting rollbar_team resource")
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	l.Debug().Msg("Successfully deleted rollbar_team resource")
 	return nil
 }
+
+// resourceTeamConvergeMembers converges the members of a team on its
+// user_emails, if any.  If that fails part way, reading back the team records
+// the members actually added or removed.  An empty or absent user_emails stops
+// managing members without removing them.
+func resourceTeamConvergeMembers(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	emails := setToStrings(d.Get("user_emails").(*schema.Set))
+	if len(emails) == 0 {
+		return nil
+	}
+	id := mustGetID(d)
+	l := log.With().
+		Int("id", id).
+		Strs("user_emails", emails).
+		Logger()
+	l.Debug().Msg("Converging members of rollbar_team resource")
+	c, err := clientFor(ctx, m, "rollbar_team", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = resourceTeamMembershipConverge(c, id, emails, func(string) bool { return true })
+	if err != nil {
+		l.Err(err).Msg("Error converging members of rollbar_team resource")
+		return withPartialState(ctx, d, m, resourceTeamRead, diagFromErr(err, "user_emails"))
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+// teamMembershipIdentity identifies a rollbar_team_membership in import blocks.
+var teamMembershipIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "team_id", typ: schema.TypeInt, description: "ID of the team whose members are managed"},
+}}
+
+// resourceTeamMembership constructs a resource authoritatively managing the
+// full set of members of a Rollbar team.
+func resourceTeamMembership() *schema.Resource {
+	return withIdentity(&schema.Resource{
+		CreateContext: resourceTeamMembershipCreate,
+		ReadContext:   resourceTeamMembershipRead,
+		UpdateContext: resourceTeamMembershipUpdate,
+		DeleteContext: resourceTeamMembershipDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importNumericID,
+		},
+
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
+		Schema: map[string]*schema.Schema{
+			// Required
+			"team_id": {
+				Description: "ID of the team whose members are managed",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"emails": {
+				Description: "Email addresses of all members of the team.  Registered users are assigned to the team, other addresses are invited",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			// Optional
+			"ignore_unmanaged": {
+				Description: "If true, members of the team not listed in `emails` are left alone rather than removed",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}, teamMembershipIdentity)
+}
+
+// teamMember is a registered user belonging to, or an email invited to, a
+// Rollbar team.
+type teamMember struct {
+	email    string
+	userID   int // Zero if invited but not registered
+	inviteID int // Zero if registered
+}
+
+// teamMembers returns the current members of a Rollbar team, keyed by lower
+// case email address, and the IDs of the registered users in the account with
+// any of the email addresses `emails`, also keyed by lower case email address.
+// Only the users needed are kept as they are listed, so that even accounts with
+// very many users are processed in bounded memory.
+func teamMembers(c *client.RollbarAPIClient, teamID int, emails []string) (members map[string]teamMember, userIDs map[string]int, err error) {
+	teamUserIDs, err := c.ListTeamUserIDs(teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	onTeam := make(map[int]bool, len(teamUserIDs))
+	for _, id := range teamUserIDs {
+		onTeam[id] = true
+	}
+	wanted := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		wanted[strings.ToLower(email)] = true
+	}
+
+	members = make(map[string]teamMember)
+	userIDs = make(map[string]int)
+	err = c.EachUser(func(u client.User) error {
+		key := strings.ToLower(u.Email)
+		if onTeam[u.ID] && u.Email != "" {
+			members[key] = teamMember{email: u.Email, userID: u.ID}
+		}
+		if wanted[key] {
+			userIDs[key] = u.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invitations, err := c.ListPendingInvitations(teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, inv := range invitations {
+		key := strings.ToLower(inv.ToEmail)
+		if _, ok := members[key]; !ok {
+			members[key] = teamMember{email: inv.ToEmail, inviteID: inv.ID}
+		}
+	}
+	return members, userIDs, nil
+}
+
+// resourceTeamMembershipConverge adds missing members to the team, and removes
+// current members that are not wanted, if `removable` allows it.
+func resourceTeamMembershipConverge(c *client.RollbarAPIClient, teamID int, wanted []string, removable func(email string) bool) error {
+	l := log.With().Int("team_id", teamID).Logger()
+	members, userIDs, err := teamMembers(c, teamID, wanted)
+	if err != nil {
+		l.Err(err).Send()
+		return err
+	}
+
+	wantedKeys := make(map[string]bool)
+	for _, email := range wanted {
+		key := strings.ToLower(email)
+		wantedKeys[key] = true
+		if _, ok := members[key]; ok {
+			continue
+		}
+		if userID, ok := userIDs[key]; ok {
+			err = c.AssignUserToTeam(teamID, userID)
+			l.Debug().Int("user_id", userID).Msg("Assigning user to team")
+		} else {
+			_, err = c.CreateInvitation(teamID, email)
+			l.Debug().Str("email", email).Msg("Inviting email to team")
+		}
+		if err != nil {
+			l.Err(err).Send()
+			return err
+		}
+	}
+
+	for key, m := range members {
+		if wantedKeys[key] || !removable(m.email) {
+			continue
+		}
+		if m.userID != 0 {
+			err = c.RemoveUserFromTeam(m.userID, teamID)
+			l.Debug().Int("user_id", m.userID).Msg("Removing user from team")
+		} else {
+			err = c.CancelInvitation(m.inviteID)
+			l.Debug().Int("invite_id", m.inviteID).Msg("Canceling invitation to team")
+		}
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Send()
+			return err
+		}
+	}
+	return nil
+}
+
+// memberEmails returns the emails of those `members` listed in `known`, with
+// the spelling in `known`, since the API may return them in a different case.
+// If `all`, the emails of the other members are included too.
+func memberEmails(members map[string]teamMember, known []string, all bool) []string {
+	emails := []string{}
+	seen := make(map[string]bool)
+	for _, email := range known {
+		key := strings.ToLower(email)
+		if _, ok := members[key]; ok {
+			emails = append(emails, email)
+			seen[key] = true
+		}
+	}
+	if all {
+		for key, m := range members {
+			if !seen[key] {
+				emails = append(emails, m.email)
+			}
+		}
+	}
+	return emails
+}
+
+// setToStrings converts a set of strings to a slice.
+func setToStrings(s *schema.Set) []string {
+	ss := []string{}
+	for _, v := range s.List() {
+		ss = append(ss, v.(string))
+	}
+	return ss
+}
+
+func resourceTeamMembershipCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	teamID := d.Get("team_id").(int)
+	emails := setToStrings(d.Get("emails").(*schema.Set))
+	ignoreUnmanaged := d.Get("ignore_unmanaged").(bool)
+	l := log.With().
+		Int("team_id", teamID).
+		Strs("emails", emails).
+		Bool("ignore_unmanaged", ignoreUnmanaged).
+		Logger()
+	l.Info().Msg("Creating rollbar_team_membership resource")
+
+	// Set the ID first, so that if converging fails part way, the members
+	// added so far are recorded in state.
+	d.SetId(strconv.Itoa(teamID))
+	c, err := clientFor(ctx, m, "rollbar_team_membership", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = resourceTeamMembershipConverge(c, teamID, emails, func(string) bool {
+		return !ignoreUnmanaged
+	})
+	if err != nil {
+		return withPartialState(ctx, d, m, resourceTeamMembershipRead, diagFromErr(err, "team_id", "emails"))
+	}
+
+	l.Debug().Msg("Successfully created rollbar_team_membership resource")
+	return resourceTeamMembershipRead(ctx, d, m)
+}
+
+func resourceTeamMembershipRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	teamID := mustGetID(d)
+	ignoreUnmanaged := d.Get("ignore_unmanaged").(bool)
+	l := log.With().
+		Int("team_id", teamID).
+		Logger()
+	l.Info().Msg("Reading rollbar_team_membership resource")
+
+	c, err := clientFor(ctx, m, "rollbar_team_membership", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	members, _, err := teamMembers(c, teamID, nil)
+	if err == client.ErrNotFound {
+		d.SetId("")
+		l.Debug().Msg("Team not found on Rollbar - removed from state")
+		return nil
+	}
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err)
+	}
+
+	emails := memberEmails(members, setToStrings(d.Get("emails").(*schema.Set)), !ignoreUnmanaged)
+	mustSet(d, "team_id", teamID)
+	mustSet(d, "emails", emails)
+	mustSet(d, "ignore_unmanaged", ignoreUnmanaged)
+	l.Debug().Msg("Successfully read rollbar_team_membership resource")
+	return nil
+}
+
+func resourceTeamMembershipUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	teamID := mustGetID(d)
+	oldEmails, newEmails := d.GetChange("emails")
+	emails := setToStrings(newEmails.(*schema.Set))
+	ignoreUnmanaged := d.Get("ignore_unmanaged").(bool)
+	l := log.With().
+		Int("team_id", teamID).
+		Strs("emails", emails).
+		Bool("ignore_unmanaged", ignoreUnmanaged).
+		Logger()
+	l.Info().Msg("Updating rollbar_team_membership resource")
+
+	// Even when ignoring unmanaged members, those removed from config must go.
+	previous := make(map[string]bool)
+	for _, email := range setToStrings(oldEmails.(*schema.Set)) {
+		previous[strings.ToLower(email)] = true
+	}
+	c, err := clientFor(ctx, m, "rollbar_team_membership", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = resourceTeamMembershipConverge(c, teamID, emails, func(email string) bool {
+		return !ignoreUnmanaged || previous[strings.ToLower(email)]
+	})
+	if err != nil {
+		return diagFromErr(err, "emails")
+	}
+
+	l.Debug().Msg("Successfully updated rollbar_team_membership resource")
+	return resourceTeamMembershipRead(ctx, d, m)
+}
+
+func resourceTeamMembershipDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	teamID := mustGetID(d)
+	l := log.With().
+		Int("team_id", teamID).
+		Logger()
+	l.Info().Msg("Deleting rollbar_team_membership resource")
+
+	// Remove only the members recorded in state
+	managed := make(map[string]bool)
+	for _, email := range setToStrings(d.Get("emails").(*schema.Set)) {
+		managed[strings.ToLower(email)] = true
+	}
+	c, err := clientFor(ctx, m, "rollbar_team_membership", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	err = resourceTeamMembershipConverge(c, teamID, nil, func(email string) bool {
+		return managed[strings.ToLower(email)]
+	})
+	if err != nil && err != client.ErrNotFound {
+		return diagFromErr(err)
+	}
+
+	l.Debug().Msg("Successfully deleted rollbar_team_membership resource")
+	return nil
+}
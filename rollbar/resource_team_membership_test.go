@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccResourceTeamMembership tests creating, updating, importing and
+// destroying a rollbar_team_membership resource.
+func (s *AccSuite) TestAccResourceTeamMembership() {
+	rn := "rollbar_team_membership.test"
+	// language=hcl
+	tmpl := `
+		resource "rollbar_team" "test_team" {
+			name = "%s-team-0"
+		}
+
+		resource "rollbar_team_membership" "test" {
+			team_id = rollbar_team.test_team.id
+			emails  = [%s]
+		}
+	`
+	email0 := fmt.Sprintf(`"terraform-provider-test+%s-0@rollbar.com"`, s.randName)
+	email1 := fmt.Sprintf(`"terraform-provider-test+%s-1@rollbar.com"`, s.randName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tmpl, s.randName, email0+", "+email1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttr(rn, "emails.#", "2"),
+					resource.TestCheckResourceAttr(rn, "ignore_unmanaged", "false"),
+				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("emails"), knownvalue.SetSizeExact(2)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(tmpl, s.randName, email1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttr(rn, "emails.#", "1"),
+				),
+			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSetToStrings(t *testing.T) {
+	set := schema.NewSet(schema.HashString, []interface{}{"a@example.com", "b@example.com"})
+	assert.ElementsMatch(t, []string{"a@example.com", "b@example.com"}, setToStrings(set))
+	assert.Equal(t, []string{}, setToStrings(schema.NewSet(schema.HashString, nil)))
+}
+
+// TestOfflineTeamMembers tests that reading a team's members keeps only the
+// account's users that are needed.
+func TestOfflineTeamMembers(t *testing.T) {
+	f := newFakeAPI(t)
+	f.users[4] = client.User{ID: 4, Username: "member", Email: "Member@example.com"}
+	f.users[5] = client.User{ID: 5, Username: "other", Email: "other@example.com"}
+	f.teamUsers[1] = map[int]bool{4: true}
+	c, err := clientFor(context.Background(), offlineMeta(f), "rollbar_team_membership", "read")
+	require.NoError(t, err)
+
+	members, userIDs, err := teamMembers(c, 1, []string{"registered@example.com", "unknown@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]teamMember{"member@example.com": {email: "Member@example.com", userID: 4}}, members)
+	assert.Equal(t, map[string]int{"registered@example.com": 3}, userIDs)
+
+	_, userIDs, err = teamMembers(c, 1, nil)
+	require.NoError(t, err)
+	assert.Empty(t, userIDs)
+}
+
+// TestOfflineTeamMembershipConverge tests that converging a team's membership
+// assigns registered users, invites other emails, and removes or cancels only
+// the unwanted members that are removable.
+func TestOfflineTeamMembershipConverge(t *testing.T) {
+	f := newFakeAPI(t)
+	f.users[4] = client.User{ID: 4, Username: "unwanted", Email: "unwanted@example.com"}
+	f.users[5] = client.User{ID: 5, Username: "kept", Email: "kept@example.com"}
+	f.teamUsers[1] = map[int]bool{4: true, 5: true}
+	f.invitations[6] = client.Invitation{ID: 6, TeamID: 1, ToEmail: "stale@example.com", Status: "pending"}
+	f.invitations[7] = client.Invitation{ID: 7, TeamID: 1, ToEmail: "invited@example.com", Status: "pending"}
+	c, err := clientFor(context.Background(), offlineMeta(f), "rollbar_team_membership", "write")
+	require.NoError(t, err)
+
+	wanted := []string{"Registered@example.com", "new@example.com", "invited@example.com"}
+	removable := func(email string) bool { return email != "kept@example.com" }
+	err = resourceTeamMembershipConverge(c, 1, wanted, removable)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invited@example.com", "kept@example.com", "new@example.com", "registered@example.com"}, f.teamMemberEmails(1))
+	assert.Equal(t, "canceled", f.invitations[6].Status)
+	assert.Len(t, f.invitations, 3)
+
+	// Converging again changes nothing
+	err = resourceTeamMembershipConverge(c, 1, wanted, removable)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invited@example.com", "kept@example.com", "new@example.com", "registered@example.com"}, f.teamMemberEmails(1))
+	assert.Len(t, f.invitations, 3)
+}
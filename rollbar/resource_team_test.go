@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
@@ -31,14 +35,14 @@ func (s *AccSuite) TestAccTeamInvalidName() {
 		}
 	`
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Invalid name - failure expected
 			{
 				Config:      config,
-				ExpectError: regexp.MustCompile("name cannot be blank"),
+				ExpectError: regexp.MustCompile("Invalid team name"),
 			},
 		},
 	})
@@ -56,17 +60,26 @@ func (s *AccSuite) TestAccTeamCreate() {
 	`
 	config := fmt.Sprintf(tmpl, teamName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "name", teamName),
 					s.checkTeam(rn, teamName, "standard"),
 				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("name"), knownvalue.StringExact(teamName)),
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("access_level"), knownvalue.StringExact("standard")),
+				},
 			},
 		},
 	})
@@ -92,11 +105,11 @@ func (s *AccSuite) TestAccTeamUpdateAccessLevel() {
 		}
 	`
 	config2 := fmt.Sprintf(tmpl2, teamName)
+	var teamID string
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
@@ -105,15 +118,23 @@ func (s *AccSuite) TestAccTeamUpdateAccessLevel() {
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "name", teamName),
 					s.checkTeam(rn, teamName, "standard"),
+					func(ts *terraform.State) error {
+						var err error
+						teamID, err = s.getResourceIDString(ts, rn)
+						return err
+					},
 				),
 			},
-			// Update team access level
+			// Update team access level in place
 			{
 				Config: config2,
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "name", teamName),
 					s.checkTeam(rn, teamName, "light"),
+					func(ts *terraform.State) error {
+						return resource.TestCheckResourceAttr(rn, "id", teamID)(ts)
+					},
 				),
 			},
 		},
@@ -133,22 +154,36 @@ func (s *AccSuite) TestAccTeamUpdateName() {
 	`
 	config1 := fmt.Sprintf(tmpl, teamName1)
 	config2 := fmt.Sprintf(tmpl, teamName2)
+	var teamID string
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
 				Config: config1,
+				Check: func(ts *terraform.State) error {
+					var err error
+					teamID, err = s.getResourceIDString(ts, rn)
+					return err
+				},
 			},
-			// Update team name
+			// Update team name in place
 			{
 				Config: config2,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionUpdate),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttr(rn, "name", teamName2),
+					s.checkTeam(rn, teamName2, "standard"),
+					func(ts *terraform.State) error {
+						return resource.TestCheckResourceAttr(rn, "id", teamID)(ts)
+					},
 				),
 			},
 		},
@@ -167,10 +202,9 @@ func (s *AccSuite) TestAccTeamImport() {
 	`
 	config1 := fmt.Sprintf(tmpl, teamName1)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
@@ -186,6 +220,49 @@ func (s *AccSuite) TestAccTeamImport() {
 	})
 }
 
+// TestAccTeamAllowExisting tests adopting an existing Rollbar team and
+// converging its access level.
+func (s *AccSuite) TestAccTeamAllowExisting() {
+	rn := "rollbar_team.test"
+	teamName := fmt.Sprintf("%s-team-0", s.randName)
+	var teamID int
+	// language=hcl
+	tmpl := `
+		resource "rollbar_team" "test" {
+			name           = "%s"
+			access_level   = "light"
+			allow_existing = true
+		}
+	`
+	config := fmt.Sprintf(tmpl, teamName)
+	resource.ParallelTest(s.T(), resource.TestCase{
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
+					t, err := c.CreateTeam(teamName, "standard")
+					s.Nil(err)
+					teamID = t.ID
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					s.checkResourceStateSanity(rn),
+					resource.TestCheckResourceAttr(rn, "access_level", "light"),
+					func(ts *terraform.State) error {
+						id, err := s.getResourceIDInt(ts, rn)
+						s.Nil(err)
+						s.Equal(teamID, id, "existing team was not adopted")
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 // TestAccTeamDeleteOnAPIBeforeApply tests creating a Rollbar team with
 // Terraform, then deleting the team via API, before again applying Terraform
 // config.
@@ -202,10 +279,9 @@ func (s *AccSuite) TestAccTeamDeleteOnAPIBeforeApply() {
 	`
 	config1 := fmt.Sprintf(tmpl, teamName1)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck: func() { s.preCheck() },
-		//ProviderFactories: testAccProviderFactories(),
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			// Initial create
 			{
@@ -214,7 +290,7 @@ func (s *AccSuite) TestAccTeamDeleteOnAPIBeforeApply() {
 			// Before running Terraform, delete the team on Rollbar but not in local state
 			{
 				PreConfig: func() {
-					c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+					c := client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
 					teams, err := c.ListCustomTeams()
 					s.Nil(err)
 					for _, t := range teams {
@@ -258,7 +334,9 @@ func (s *AccSuite) checkTeam(rn, teamName, accessLevel string) resource.TestChec
 func sweepResourceTeam(_ string) error {
 	log.Info().Msg("Cleaning up Rollbar teams from acceptance test runs.")
 
-	c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+	prefix := sweepPrefix()
+
+	c := sweepClient()
 	teams, err := c.ListCustomTeams()
 	if err != nil {
 		log.Err(err).Send()
@@ -271,7 +349,7 @@ func sweepResourceTeam(_ string) error {
 			Str("name", t.Name).
 			Int("id", t.ID).
 			Logger()
-		if strings.HasPrefix(t.Name, "tf-acc-test-") {
+		if strings.HasPrefix(t.Name, prefix) {
 			err = c.DeleteTeam(t.ID)
 			if err != nil {
 				l.Err(err).Send()
@@ -324,9 +402,9 @@ func (s *AccSuite) TestAccTeamDeleteTeamWithUsers() {
 	`
 	configRemoveTeam := fmt.Sprintf(tmpl, team2Name)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -377,10 +455,15 @@ func TestTeamValidateAccessLevel(t *testing.T) {
 		"view",
 	}
 	for _, level := range validAccessLevels {
-		d := resourceTeamValidateAccessLevel(level, p)
+		d := validateTeamAccessLevel(level, p)
 		assert.Nil(t, d)
 	}
-	d := resourceTeamValidateAccessLevel("invalid-level", p)
+	for _, level := range []string{"everyone", "owner"} {
+		d := validateTeamAccessLevel(level, p)
+		assert.Len(t, d, 1, level)
+	}
+	d := validateTeamAccessLevel("invalid-level", p)
 	assert.Len(t, d, 1)
 	assert.IsType(t, diag.Diagnostic{}, d[0])
 }
+
@@ -33,17 +33,26 @@ import (
 	"strings"
 )
 
+// teamUserIdentity identifies a rollbar_team_user in import blocks.
+var teamUserIdentity = resourceIdentity{separator: ComplexImportSeparator, attrs: []identityAttr{
+	{name: "team_id", attr: "team_id", typ: schema.TypeInt, description: "ID of the team"},
+	{name: "email", attr: "email", typ: schema.TypeString, description: "Email address of the user"},
+}}
+
 func resourceTeamUser() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceTeamUserCreate,
 		ReadContext:   resourceTeamUserRead,
 		UpdateContext: nil, // resourceTeamUserUpdate,
 		DeleteContext: resourceTeamUserDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceTeamUserImporter,
 		},
 
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
 		Schema: map[string]*schema.Schema{
 			// Required
 			"team_id": {
@@ -76,7 +85,7 @@ func resourceTeamUser() *schema.Resource {
 				Computed:    true,
 			},
 		},
-	}
+	}, teamUserIdentity)
 }
 
 func teamUserID(teamID int, email string) string {
@@ -102,7 +111,10 @@ func teamUserFromID(id string) (teamID int, s string, err error) {
 }
 
 func resourceTeamUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_team_user", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	teamID := d.Get("team_id").(int)
 	email := d.Get("email").(string)
 	l := log.With().
@@ -122,7 +134,7 @@ func resourceTeamUserCreate(ctx context.Context, d *schema.ResourceData, meta in
 		er := c.AssignUserToTeam(teamID, userID)
 		if er != nil {
 			l.Err(er).Msg("error assigning user to team")
-			return diag.FromErr(er)
+			return diagFromErr(er, "team_id", "user_id")
 		}
 		mustSet(d, "invite_id", 0)
 		l.Debug().Msg("Assigned user to team")
@@ -132,7 +144,7 @@ func resourceTeamUserCreate(ctx context.Context, d *schema.ResourceData, meta in
 		inv, er := c.CreateInvitation(teamID, email)
 		if er != nil {
 			l.Err(er).Msg("error assigning user to team")
-			return diag.FromErr(er)
+			return diagFromErr(er, "team_id", "email")
 		}
 		l.Debug().
 			Int("inviteID", inv.ID).
@@ -140,7 +152,7 @@ func resourceTeamUserCreate(ctx context.Context, d *schema.ResourceData, meta in
 		mustSet(d, "invite_id", inv.ID)
 	default: // Actual error
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	d.SetId(teamUserID(teamID, email))
@@ -148,10 +160,10 @@ func resourceTeamUserCreate(ctx context.Context, d *schema.ResourceData, meta in
 	return resourceTeamUserRead(ctx, d, meta)
 }
 
-func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceTeamUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	teamID, email, err := teamUserFromID(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	userID := d.Get("user_id").(int)
 	l := log.With().
@@ -160,7 +172,10 @@ func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interf
 		Int("team_id", teamID).
 		Logger()
 	l.Info().Msg("Reading rollbar_team_user resource")
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_team_user", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 
 	// If user ID is not in state, try to query it from Rollbar
 	if userID == 0 {
@@ -179,7 +194,7 @@ func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interf
 			mustSet(d, "status", "invited")
 		default:
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -188,7 +203,7 @@ func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interf
 		assigned, err := c.IsUserAssignedToTeam(teamID, userID)
 		if err != nil {
 			l.Err(err).Msg("Error checking if user is assigned to team.")
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		if assigned {
 			mustSet(d, "team_id", teamID)
@@ -201,7 +216,7 @@ func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interf
 		invitations, err := c.ListPendingInvitations(teamID)
 		if err != nil {
 			l.Err(err).Msg("Error checking if user has pending invitation.")
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		var invite client.Invitation
 		for _, i := range invitations {
@@ -219,7 +234,7 @@ func resourceTeamUserRead(_ context.Context, d *schema.ResourceData, meta interf
 	return nil
 }
 
-func resourceTeamUserDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceTeamUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	email := d.Id()
 	teamID := d.Get("team_id").(int)
 	l := log.With().
@@ -227,7 +242,10 @@ func resourceTeamUserDelete(_ context.Context, d *schema.ResourceData, meta inte
 		Int("team_id", teamID).
 		Logger()
 	l.Info().Msg("Deleting rollbar_team_user resource")
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_team_user", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 
 	userID := d.Get("user_id").(int)
 	if userID == 0 {
@@ -236,7 +254,7 @@ func resourceTeamUserDelete(_ context.Context, d *schema.ResourceData, meta inte
 		err := c.CancelInvitation(inviteID)
 		if err != client.ErrNotFound {
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	} else {
 		// Remove user from team
@@ -244,7 +262,7 @@ func resourceTeamUserDelete(_ context.Context, d *schema.ResourceData, meta inte
 		if err != nil {
 			if err != client.ErrNotFound {
 				l.Err(err).Send()
-				return diag.FromErr(err)
+				return diagFromErr(err)
 			}
 		}
 	}
@@ -254,3 +272,19 @@ func resourceTeamUserDelete(_ context.Context, d *schema.ResourceData, meta inte
 	l.Debug().Msg("Successfully deleted rollbar_team_user resource")
 	return nil
 }
+
+// resourceTeamUserImporter imports a rollbar_team_user resource from an ID of
+// the form TEAM-ID,EMAIL.
+func resourceTeamUserImporter(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	l := log.With().Str("id", d.Id()).Logger()
+	l.Debug().Msg("Importing rollbar_team_user resource")
+	teamID, email, err := teamUserFromID(d.Id())
+	if err != nil || email == "" {
+		err = fmt.Errorf("unexpected format of ID (%q), expected TEAM-ID%sEMAIL", d.Id(), ComplexImportSeparator)
+		l.Err(err).Send()
+		return nil, err
+	}
+	mustSet(d, "team_id", teamID)
+	mustSet(d, "email", email)
+	return []*schema.ResourceData{d}, nil
+}
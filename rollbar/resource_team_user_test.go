@@ -1,8 +1,15 @@
 package rollbar
 
 import (
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
+	"testing"
 )
 
 // TestAccResourceTeamUser_createInvited tests creating, importing and destroying a new rollbar_team_user
@@ -23,12 +30,17 @@ func (s *AccSuite) TestAccResourceTeamUser_createInvited() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName, email)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					resource.TestCheckResourceAttrSet(rn, "team_id"),
@@ -36,6 +48,9 @@ func (s *AccSuite) TestAccResourceTeamUser_createInvited() {
 					resource.TestCheckResourceAttrSet(rn, "invite_id"),
 					resource.TestCheckNoResourceAttr(rn, "user_id"),
 				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("email"), knownvalue.StringExact(email)),
+				},
 			},
 			{
 				ResourceName:      rn,
@@ -65,9 +80,9 @@ func (s *AccSuite) TestAccResourceTeamUser_createRegistered() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -87,3 +102,21 @@ func (s *AccSuite) TestAccResourceTeamUser_createRegistered() {
 		},
 	})
 }
+
+// TestTeamUserImporter tests parsing of the composite ID used to import a
+// rollbar_team_user resource.
+func TestTeamUserImporter(t *testing.T) {
+	d := resourceTeamUser().TestResourceData()
+	d.SetId("689493,some_dev@company.com")
+	_, err := resourceTeamUserImporter(context.Background(), d, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 689493, d.Get("team_id"))
+	assert.Equal(t, "some_dev@company.com", d.Get("email"))
+
+	for _, id := range []string{"689493", "689493,", "team,some_dev@company.com"} {
+		d = resourceTeamUser().TestResourceData()
+		d.SetId(id)
+		_, err = resourceTeamUserImporter(context.Background(), d, nil)
+		assert.NotNil(t, err, id)
+	}
+}
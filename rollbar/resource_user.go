@@ -24,15 +24,30 @@ package rollbar
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 )
 
+// Account roles of a Rollbar user.  The API has no account roles as such:
+// owners are members of the system team "Owners", and everyone else is a
+// member.
+const (
+	accountRoleOwner  = "owner"
+	accountRoleMember = "member"
+)
+
+// userIdentity identifies a rollbar_user in import blocks.
+var userIdentity = resourceIdentity{attrs: []identityAttr{
+	{name: "email", typ: schema.TypeString, description: "Email address of the user"},
+}}
+
 func resourceUser() *schema.Resource {
-	return &schema.Resource{
+	return withIdentity(&schema.Resource{
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
@@ -42,6 +57,9 @@ func resourceUser() *schema.Resource {
 			StateContext: resourceUserImporter,
 		},
 
+		SchemaVersion:  0,
+		StateUpgraders: []schema.StateUpgrader{},
+
 		Schema: map[string]*schema.Schema{
 			// Required
 			"email": {
@@ -59,6 +77,21 @@ func resourceUser() *schema.Resource {
 				},
 			},
 
+			// Optional
+			"remove_from_account": {
+				Description: "If true, destroying this resource removes the user from the Rollbar account entirely, not just from its teams",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"account_role": {
+				Description:  `The user's role in the account.  Either "owner", making the user a member of the "Owners" team, or "member".  If not set, the role is left as is`,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{accountRoleOwner, accountRoleMember}, false),
+			},
+
 			// Computed
 			"username": {
 				Description: "The user's username",
@@ -76,7 +109,7 @@ func resourceUser() *schema.Resource {
 				Computed:    true,
 			},
 		},
-	}
+	}, userIdentity)
 }
 
 // resourceUserCreate creates a new Rollbar user resource.
@@ -96,7 +129,10 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interf
 // inviting user to specified groups, and removing user from groups no longer
 // specified.
 func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_user", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 	email := d.Get("email").(string)
 	teamIDs := getTeamIDs(d)
 	l := log.With().
@@ -118,7 +154,7 @@ func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 		mustSet(d, "status", "invited")
 	default: // Actual error
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err, "email")
 	}
 
 	// Teams to which this user SHOULD belong
@@ -130,9 +166,12 @@ func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 	teamsCurrent, err := resourceUserCurrentTeams(c, email, userID, true)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
+	// Set the ID before changing teams, so that if a change fails, the
+	// invitations and assignments made so far are recorded in state.
+	d.SetId(email)
 	err = resourceUserAddTeams(resourceUserAddRemoveTeamsArgs{
 		client:        c,
 		userID:        userID,
@@ -142,7 +181,7 @@ func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 	})
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return withPartialState(ctx, d, meta, resourceUserRead, diagFromErr(err, "team_ids", "email"))
 	}
 
 	err = resourceUserRemoveTeams(resourceUserAddRemoveTeamsArgs{
@@ -154,10 +193,17 @@ func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 	})
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return withPartialState(ctx, d, meta, resourceUserRead, diagFromErr(err))
+	}
+
+	if role, ok := d.GetOk("account_role"); ok && d.HasChange("account_role") {
+		err = resourceUserSetAccountRole(c, email, userID, role.(string))
+		if err != nil {
+			l.Err(err).Send()
+			return withPartialState(ctx, d, meta, resourceUserRead, diagFromErr(err, "account_role"))
+		}
 	}
 
-	d.SetId(email)
 	l.Debug().Msg("Successfully created or updated rollbar_user resource")
 	return resourceUserRead(ctx, d, meta)
 }
@@ -274,6 +320,68 @@ func resourceUserRemoveTeams(args resourceUserAddRemoveTeamsArgs) error {
 	return nil
 }
 
+// resourceUserIsOwner reports whether a registered user, or an invited email if
+// userID is 0, belongs to the "Owners" team, and returns the team's ID.
+func resourceUserIsOwner(c *client.RollbarAPIClient, email string, userID int) (ownersID int, owner bool, err error) {
+	ownersID, err = c.FindOwnersTeamID()
+	if err != nil {
+		return
+	}
+	if userID != 0 {
+		owner, err = c.IsUserAssignedToTeam(ownersID, userID)
+		return
+	}
+	invitations, err := c.ListPendingInvitations(ownersID)
+	if err != nil {
+		return
+	}
+	for _, inv := range invitations {
+		if strings.EqualFold(inv.ToEmail, email) {
+			owner = true
+		}
+	}
+	return
+}
+
+// resourceUserSetAccountRole gives a Rollbar user the account role `role`, by
+// adding it to or removing it from the "Owners" team.
+func resourceUserSetAccountRole(c *client.RollbarAPIClient, email string, userID int, role string) error {
+	l := log.With().
+		Str("email", email).
+		Int("user_id", userID).
+		Str("account_role", role).
+		Logger()
+	ownersID, owner, err := resourceUserIsOwner(c, email, userID)
+	if err != nil {
+		l.Err(err).Send()
+		return err
+	}
+	switch {
+	case role == accountRoleOwner && !owner && userID != 0:
+		l.Debug().Msg("Assigning user to owners team")
+		err = c.AssignUserToTeam(ownersID, userID)
+	case role == accountRoleOwner && !owner:
+		l.Debug().Msg("Inviting email to owners team")
+		_, err = c.CreateInvitation(ownersID, email)
+	case role == accountRoleMember && owner && userID != 0:
+		l.Debug().Msg("Removing user from owners team")
+		err = c.RemoveUserFromTeam(userID, ownersID)
+	case role == accountRoleMember && owner:
+		l.Debug().Msg("Canceling invitations to owners team")
+		var invitations []client.Invitation
+		invitations, err = c.ListPendingInvitations(ownersID)
+		for _, inv := range invitations {
+			if err == nil && strings.EqualFold(inv.ToEmail, email) {
+				err = c.CancelInvitation(inv.ID)
+			}
+		}
+	}
+	if err != nil {
+		l.Err(err).Send()
+	}
+	return err
+}
+
 // resourceUserCurrentTeams returns user's current team memberships.
 func resourceUserCurrentTeams(c *client.RollbarAPIClient, email string, userID int, filterSysTeams bool) (currentTeams map[int]bool, err error) {
 	l := log.With().
@@ -316,7 +424,7 @@ func resourceUserCurrentTeams(c *client.RollbarAPIClient, email string, userID i
 	return currentTeams, nil
 }
 
-func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	email := d.Id()
 	userID := d.Get("user_id").(int)
 	l := log.With().
@@ -324,8 +432,10 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 		Int("userID", userID).
 		Logger()
 	l.Info().Msg("Reading rollbar_user resource")
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
-	var err error
+	c, err := clientFor(ctx, meta, "rollbar_user", "read")
+	if err != nil {
+		return diagFromErr(err)
+	}
 
 	// If user ID is not in state, try to query it from Rollbar
 	if userID == 0 {
@@ -341,7 +451,7 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 			l.Debug().Msg("No registered user found")
 		default:
 			l.Err(err).Send()
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -355,7 +465,7 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 	currentTeams, err := resourceUserCurrentTeams(c, email, userID, true)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	teamIDs := []int{}
 	for teamID := range currentTeams {
@@ -363,6 +473,17 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 	}
 	mustSet(d, "team_ids", teamIDs)
 
+	_, owner, err := resourceUserIsOwner(c, email, userID)
+	if err != nil {
+		l.Err(err).Send()
+		return diagFromErr(err)
+	}
+	if owner {
+		mustSet(d, "account_role", accountRoleOwner)
+	} else {
+		mustSet(d, "account_role", accountRoleMember)
+	}
+
 	l.Debug().Msg("Successfully read rollbar_user resource")
 	return nil
 }
@@ -375,16 +496,24 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		Ints("teamIDs", teamIDs).
 		Logger()
 	l.Info().Msg("Updating rollbar_user resource")
+	// remove_from_account only takes effect on destroy
+	if !d.HasChanges("team_ids", "account_role") {
+		l.Debug().Msg("No remote change to rollbar_user resource")
+		return nil
+	}
 	return resourceUserCreateOrUpdate(ctx, d, meta)
 }
 
-func resourceUserDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	email := d.Id()
 	l := log.With().
 		Str("email", email).
 		Logger()
 	l.Info().Msg("Deleting rollbar_user resource")
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_user", "write")
+	if err != nil {
+		return diagFromErr(err)
+	}
 
 	// Try to get user ID
 	userID := d.Get("user_id").(int)
@@ -395,7 +524,7 @@ func resourceUserDelete(_ context.Context, d *schema.ResourceData, meta interfac
 	teamsCurrent, err := resourceUserCurrentTeams(c, email, userID, false)
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	teamsExpected := make(map[int]bool) // Empty
 	err = resourceUserRemoveTeams(resourceUserAddRemoveTeamsArgs{
@@ -407,7 +536,18 @@ func resourceUserDelete(_ context.Context, d *schema.ResourceData, meta interfac
 	})
 	if err != nil {
 		l.Err(err).Send()
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+
+	// Invited users have not joined the account, so there is nothing more to
+	// remove once their invitations are canceled.
+	if d.Get("remove_from_account").(bool) && userID != 0 {
+		l.Debug().Int("user_id", userID).Msg("Removing user from account")
+		err = c.RemoveUserFromAccount(userID)
+		if err != nil && err != client.ErrNotFound {
+			l.Err(err).Send()
+			return diagFromErr(err)
+		}
 	}
 
 	d.SetId("")
@@ -429,6 +569,7 @@ func getTeamIDs(d *schema.ResourceData) []int {
 func resourceUserImporter(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	email := d.Id()
 	mustSet(d, "email", email)
+	mustSet(d, "remove_from_account", false)
 	teamIDsSet := d.Get("team_ids").(*schema.Set)
 	l := log.With().
 		Str("email", email).
@@ -437,7 +578,10 @@ func resourceUserImporter(ctx context.Context, d *schema.ResourceData, meta inte
 	l.Info().Msg("Importing rollbar_user resource")
 
 	teamIDs := []int{}
-	c := meta.(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	c, err := clientFor(ctx, meta, "rollbar_user", "read")
+	if err != nil {
+		return nil, err
+	}
 
 	invitations, err := c.FindInvitations(email)
 	if err != nil && err != client.ErrNotFound {
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/dnaeon/go-vcr/recorder"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/rollbar/terraform-provider-rollbar/client"
 	"github.com/rs/zerolog/log"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
 )
@@ -38,16 +41,26 @@ func (s *AccSuite) TestAccUserCreateInvite() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(rn, plancheck.ResourceActionCreate),
+					},
+				},
 				Check: resource.ComposeTestCheckFunc(
 					s.checkResourceStateSanity(rn),
 					s.checkUserTeams(rn),
 				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("email"),
+						knownvalue.StringExact(fmt.Sprintf("terraform-provider-test+%s@rollbar.com", s.randName))),
+					statecheck.ExpectKnownValue(rn, tfjsonpath.New("team_ids"), knownvalue.SetSizeExact(1)),
+				},
 			},
 		},
 	})
@@ -76,9 +89,9 @@ func (s *AccSuite) TestAccUserCreateInviteMixedCase() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -111,9 +124,9 @@ func (s *AccSuite) TestAccUserCreateAssign() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -143,9 +156,9 @@ func (s *AccSuite) TestAccUserImportInvited() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -176,9 +189,9 @@ func (s *AccSuite) TestAccUserImportRegistered() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -236,9 +249,9 @@ func (s *AccSuite) TestAccInvitedUserAddTeam() {
 	`
 	configAddTeam := fmt.Sprintf(tmpl, s.randName, s.randName, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -301,9 +314,9 @@ func (s *AccSuite) TestAccInvitedUserRemoveTeam() {
 	`
 	configRemoveTeam := fmt.Sprintf(tmpl, s.randName, s.randName, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -368,9 +381,9 @@ func (s *AccSuite) TestAccRegisteredUserAddTeam() {
 	`
 	configAddTeam := fmt.Sprintf(tmpl, s.randName, s.randName)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -435,9 +448,9 @@ func (s *AccSuite) TestAccRegisteredUserRemoveTeam() {
 	`
 	configRemoveTeam := fmt.Sprintf(tmpl, s.randName, s.randName)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -472,9 +485,9 @@ func (s *AccSuite) TestAccUserInvalidConfig() {
 	`
 	config := fmt.Sprintf(tmpl, s.randName)
 	resource.ParallelTest(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config:      config,
@@ -632,9 +645,9 @@ func (s *AccSuite) TestAccUserMoveBetweenTeams() {
 	`
 	configChangeTeams := fmt.Sprintf(tmpl, team1Name, team2Name, user1Email, user2Email)
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				Config: configOrigin,
@@ -790,9 +803,9 @@ func (s *AccSuite) TestAccUserInvitedToRegistered() {
 	var r *recorder.Recorder
 	origTransport := http.DefaultTransport
 	resource.Test(s.T(), resource.TestCase{
-		PreCheck:     func() { s.preCheck() },
-		Providers:    s.providers,
-		CheckDestroy: nil,
+		PreCheck:                 func() { s.preCheck() },
+		ProtoV5ProviderFactories: s.providerFactories,
+		CheckDestroy:             nil,
 		Steps: []resource.TestStep{
 			{
 				PreConfig: func() {
@@ -871,7 +884,9 @@ func vcrFilterHeaders(i *cassette.Interaction) error {
 func sweepResourceUser(_ string) error {
 	log.Info().Msg("Cleaning up Rollbar users from acceptance test runs.")
 
-	c := client.NewClient(client.DefaultBaseURL, os.Getenv("ROLLBAR_API_KEY"))
+	prefix := sweepPrefix()
+
+	c := sweepClient()
 	users, err := c.ListUsers()
 	if err != nil {
 		log.Err(err).Send()
@@ -894,7 +909,7 @@ func sweepResourceUser(_ string) error {
 	count := 0
 	for _, u := range users {
 		// We're only interested in test users
-		if !strings.HasPrefix(u.Username, "tf-acc-test-") {
+		if !strings.HasPrefix(u.Username, prefix) {
 			continue
 		}
 		// Ignore this user, because it is required for acceptance tests that
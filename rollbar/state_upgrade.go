@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+ * State migration helpers
+ *
+ * When a schema changes incompatibly, bump the resource's SchemaVersion, keep a
+ * copy of the old schema, and append an upgrader built with stateUpgrader() to
+ * the resource's StateUpgraders:
+ *
+ *	StateUpgraders: []schema.StateUpgrader{
+ *		stateUpgrader(0, resourceFooV0(), stateRenameAttr("old", "new")),
+ *	},
+ */
+
+// stateMigration is a single step in migrating the raw Terraform state of a
+// resource from one schema version to the next.
+type stateMigration func(rawState map[string]interface{}) error
+
+// stateUpgrader constructs a schema.StateUpgrader that migrates raw state from
+// schema version `version` to `version+1` by applying each migration in order.
+// `prior` is the resource as it was defined at schema version `version`.
+func stateUpgrader(version int, prior *schema.Resource, migrations ...stateMigration) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    prior.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+			l := log.With().
+				Int("from_version", version).
+				Logger()
+			l.Debug().Msg("Upgrading resource state")
+			if rawState == nil {
+				rawState = map[string]interface{}{}
+			}
+			for _, m := range migrations {
+				err := m(rawState)
+				if err != nil {
+					l.Err(err).Msg("Error upgrading resource state")
+					return nil, err
+				}
+			}
+			l.Debug().Msg("Successfully upgraded resource state")
+			return rawState, nil
+		},
+	}
+}
+
+// stateRenameAttr returns a migration that renames a top-level attribute.
+func stateRenameAttr(oldKey, newKey string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		if v, ok := rawState[oldKey]; ok {
+			rawState[newKey] = v
+			delete(rawState, oldKey)
+		}
+		return nil
+	}
+}
+
+// stateRemoveAttr returns a migration that removes a top-level attribute.
+func stateRemoveAttr(key string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		delete(rawState, key)
+		return nil
+	}
+}
+
+// stateDefaultAttr returns a migration that sets a top-level attribute to
+// `value` if it is absent or null.
+func stateDefaultAttr(key string, value interface{}) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		if v, ok := rawState[key]; !ok || v == nil {
+			rawState[key] = value
+		}
+		return nil
+	}
+}
+
+// stateDedupeListAttr returns a migration that removes repeated values from a
+// top-level list attribute, e.g. before the attribute becomes a set.
+func stateDedupeListAttr(key string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		list, ok := rawState[key].([]interface{})
+		if !ok {
+			return nil
+		}
+		seen := make(map[interface{}]bool)
+		deduped := []interface{}{}
+		for _, v := range list {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			deduped = append(deduped, v)
+		}
+		rawState[key] = deduped
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStateUpgrader tests applying a chain of state migrations.
+func TestStateUpgrader(t *testing.T) {
+	u := stateUpgrader(0, resourceTeam(),
+		stateRenameAttr("level", "access_level"),
+		stateRemoveAttr("obsolete"),
+		stateDefaultAttr("account_id", 0),
+	)
+	assert.Equal(t, 0, u.Version)
+	rawState := map[string]interface{}{
+		"id":       "689493",
+		"name":     "foo",
+		"level":    "light",
+		"obsolete": true,
+	}
+	expected := map[string]interface{}{
+		"id":           "689493",
+		"name":         "foo",
+		"access_level": "light",
+		"account_id":   0,
+	}
+	actual, err := u.Upgrade(context.Background(), rawState, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+// TestProjectAccessTokenStateUpgradeV0 tests migrating project access token
+// scopes from a list to a set.
+func TestProjectAccessTokenStateUpgradeV0(t *testing.T) {
+	r := resourceProjectAccessToken()
+	assert.Equal(t, 1, r.SchemaVersion)
+	u := r.StateUpgraders[0]
+	rawState := map[string]interface{}{
+		"id":     "d19f7ada16534b1c94e91d9da3dbae5a",
+		"scopes": []interface{}{"write", "read", "write"},
+	}
+	expected := map[string]interface{}{
+		"id":     "d19f7ada16534b1c94e91d9da3dbae5a",
+		"scopes": []interface{}{"write", "read"},
+	}
+	actual, err := u.Upgrade(context.Background(), rawState, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+}
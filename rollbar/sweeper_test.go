@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+	"os"
+	"strings"
+)
+
+// defaultSweepPrefix is the name prefix of resources created by acceptance
+// tests.
+const defaultSweepPrefix = "tf-acc-test-"
+
+func init() {
+	resource.AddTestSweepers("rollbar_project_access_token", &resource.Sweeper{
+		Name: "rollbar_project_access_token",
+		F:    sweepResourceProjectAccessToken,
+	})
+}
+
+// sweepPrefix returns the name prefix identifying resources to be cleaned up
+// by sweepers.  It can be overridden with environment variable
+// ROLLBAR_SWEEP_PREFIX.
+func sweepPrefix() string {
+	if prefix := os.Getenv("ROLLBAR_SWEEP_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return defaultSweepPrefix
+}
+
+// sweepClient returns an API client for use by sweepers.
+func sweepClient() *client.RollbarAPIClient {
+	return client.NewClient(os.Getenv("ROLLBAR_API_KEY"))
+}
+
+// sweepResourceProjectAccessToken cleans up orphaned Rollbar project access
+// tokens.  Tokens belonging to test projects are removed along with their
+// project, so this only looks for test tokens in other projects.
+func sweepResourceProjectAccessToken(_ string) error {
+	log.Info().Msg("Cleaning up Rollbar project access tokens from acceptance test runs.")
+	prefix := sweepPrefix()
+
+	c := sweepClient()
+	projects, err := c.ListProjects()
+	if err != nil {
+		log.Err(err).Send()
+		return err
+	}
+
+	count := 0
+	for _, p := range projects {
+		if strings.HasPrefix(p.Name, prefix) {
+			continue
+		}
+		tokens, err := c.ListProjectAccessTokens(p.ID)
+		if err != nil {
+			log.Err(err).Send()
+			return err
+		}
+		for _, t := range tokens {
+			l := log.With().
+				Int("project_id", p.ID).
+				Str("name", t.Name).
+				Logger()
+			if !strings.HasPrefix(t.Name, prefix) {
+				continue
+			}
+			err = c.DeleteProjectAccessToken(p.ID, t.AccessToken)
+			if err != nil {
+				l.Err(err).Send()
+				return err
+			}
+			count++
+			l.Debug().Msg("Deleted project access token")
+		}
+	}
+
+	log.Info().Int("count", count).Msg("Project access tokens cleanup complete")
+	return nil
+}
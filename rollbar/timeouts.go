@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultTimeout is how long each operation on a resource may take, unless the
+// resource sets its own default or the configuration's `timeouts` block
+// overrides it.  It matches the Terraform SDK's own default.
+const defaultTimeout = 20 * time.Minute
+
+// withDefaultTimeouts declares the timeouts of each operation that resource `r`
+// implements, so they can be set in a `timeouts` block.  The deadline of an
+// operation bounds the context its API requests are made with; see clientFor.
+// Timeouts the resource already declares are left alone.
+func withDefaultTimeouts(r *schema.Resource) *schema.Resource {
+	if r.Timeouts == nil {
+		r.Timeouts = &schema.ResourceTimeout{}
+	}
+	t := r.Timeouts
+	if t.Create == nil && (r.CreateContext != nil || r.Create != nil) {
+		t.Create = schema.DefaultTimeout(defaultTimeout)
+	}
+	if t.Read == nil && (r.ReadContext != nil || r.Read != nil) {
+		t.Read = schema.DefaultTimeout(defaultTimeout)
+	}
+	if t.Update == nil && (r.UpdateContext != nil || r.Update != nil) {
+		t.Update = schema.DefaultTimeout(defaultTimeout)
+	}
+	if t.Delete == nil && (r.DeleteContext != nil || r.Delete != nil) {
+		t.Delete = schema.DefaultTimeout(defaultTimeout)
+	}
+	return r
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithDefaultTimeouts tests that every resource accepts a `timeouts` block
+// for the operations it implements.
+func TestWithDefaultTimeouts(t *testing.T) {
+	p := Provider()
+	for name, r := range p.ResourcesMap {
+		require.NotNil(t, r.Timeouts, name)
+		assert.NotNil(t, r.Timeouts.Create, name)
+		assert.NotNil(t, r.Timeouts.Read, name)
+		assert.NotNil(t, r.Timeouts.Delete, name)
+		assert.Equal(t, r.UpdateContext != nil, r.Timeouts.Update != nil, name)
+	}
+
+	// Timeouts a resource declares itself are kept
+	r := p.ResourcesMap["rollbar_person_data_deletion"]
+	assert.Equal(t, 30*time.Minute, *r.Timeouts.Create)
+	assert.Equal(t, defaultTimeout, *r.Timeouts.Read)
+}
+
+// TestOfflineRequestDeadline tests that an API request still in progress when
+// the operation's deadline passes is abandoned, with a diagnostic naming the
+// endpoint.
+func TestOfflineRequestDeadline(t *testing.T) {
+	f := newFakeAPI(t)
+	m := offlineMeta(f)
+	f.fault = func(r *http.Request) int {
+		time.Sleep(500 * time.Millisecond)
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]interface{}{})
+	d.SetId("1")
+	start := time.Now()
+	diags := resourceTeamRead(ctx, d, m)
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Rollbar API request timed out", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "GET /api/1/team/1 did not complete in time.")
+	assert.Contains(t, diags[0].Detail, "`timeouts` block")
+}
+
+// TestOfflineInterrupt tests that interrupting Terraform, which cancels the
+// stop context the provider is configured with, abandons API requests in
+// flight.
+func TestOfflineInterrupt(t *testing.T) {
+	f := newFakeAPI(t)
+	stop, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := context.WithValue(context.Background(), schema.StopContextKey, stop)
+	pd := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		schemaKeyToken:   "fakeTokenString",
+		schemaKeyBaseURL: f.URL,
+	})
+	m, diags := providerConfigure(ctx, pd)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	f.fault = func(r *http.Request) int {
+		time.Sleep(500 * time.Millisecond)
+		return 0
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]interface{}{})
+	d.SetId("1")
+	start := time.Now()
+	diags = resourceTeamRead(context.Background(), d, m)
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Rollbar API request interrupted", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "GET /api/1/team/1 was abandoned")
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+ * Token routing
+ *
+ * The provider is configured with an account access token, api_key, and a
+ * project access token, project_api_key.  Each resource and data source calls
+ * endpoints that accept only one kind, so gets its client by type name with
+ * clientFor(), rather than picking a token itself:
+ *
+ *	c, err := clientFor(ctx, m, "rollbar_team", "write")
+ *
+ * Reads need scope "read", and all other operations "write".  Before a write
+ * with the project access token, its scopes are looked up with the account
+ * access token, so a token lacking write scope fails with an error naming the
+ * scope, rather than a bare 403 from the API.  The API cannot introspect a
+ * token, so the lookup searches the tokens of each project, and is skipped
+ * for accounts with more than client.MaxTokenScopeSearch projects.  Account
+ * access tokens cannot be looked up, so are not checked.
+ */
+
+// tokenRoutes maps the resource and data source types needing a project access
+// token to the provider argument holding it.  All other types need the
+// account access token, api_key.
+var tokenRoutes = map[string]string{
+	"rollbar_item":                 projectKeyToken,
+	"rollbar_item_occurrences":     projectKeyToken,
+	"rollbar_notification":         projectKeyToken,
+	"rollbar_notification_rules":   projectKeyToken,
+	"rollbar_notifications":        projectKeyToken,
+	"rollbar_people":               projectKeyToken,
+	"rollbar_person_data_deletion": projectKeyToken,
+}
+
+// tokenEnvVars maps the provider arguments holding tokens to the environment
+// variables they are sourced from.
+var tokenEnvVars = map[string]string{
+	schemaKeyToken:  "ROLLBAR_API_KEY",
+	projectKeyToken: "ROLLBAR_PROJECT_API_KEY",
+}
+
+// missingTokenError is returned by clientFor when the token needed by a
+// resource or data source is not configured.
+type missingTokenError struct {
+	typeName string // Resource or data source type
+	key      string // Provider argument holding the token
+	scope    string // Scope the token needs
+}
+
+func (e *missingTokenError) Error() string {
+	kind := "an account"
+	if e.key == projectKeyToken {
+		kind = "a project"
+	}
+	return fmt.Sprintf("%s needs %s access token with %s scope: set provider argument %s or environment variable %s", e.typeName, kind, e.scope, e.key, tokenEnvVars[e.key])
+}
+
+// missingScopeError is returned by clientFor when the token needed by a
+// resource or data source is known to lack the scope needed.
+type missingScopeError struct {
+	missingTokenError
+	scopes []client.Scope // Scopes the token has
+}
+
+func (e *missingScopeError) Error() string {
+	scopes := make([]string, len(e.scopes))
+	for i, s := range e.scopes {
+		scopes[i] = string(s)
+	}
+	return fmt.Sprintf("%s needs a token with %s scope, but the token set by provider argument %s has only %s", e.typeName, e.scope, e.key, quotedList(scopes, "and"))
+}
+
+// clientFor returns the API client authenticated with the token needed by
+// resource or data source type `typeName`, whose operations need token scope
+// `scope`.  It returns a missingTokenError if that token is not configured, or
+// a missingScopeError if it is known to lack the scope.  The client's requests
+// are bound to `ctx`, so are abandoned once the operation's timeout passes.
+func clientFor(ctx context.Context, m interface{}, typeName, scope string) (*client.RollbarAPIClient, error) {
+	key, ok := tokenRoutes[typeName]
+	if !ok {
+		key = schemaKeyToken
+	}
+	clients := m.(map[string]*client.RollbarAPIClient)
+	c := clients[key]
+	if c == nil || !c.HasToken() {
+		return nil, &missingTokenError{typeName: typeName, key: key, scope: scope}
+	}
+	if key == projectKeyToken && scope != string(client.ScopeRead) {
+		account := clients[schemaKeyToken]
+		if account == nil || !account.HasToken() {
+			return c.WithContext(ctx), nil
+		}
+		scopes, err := c.ProjectTokenScopes(account.WithContext(ctx))
+		if err != nil {
+			// Unknown scopes are left for the API to check
+			log.Debug().Err(err).Msg("Could not check scopes of project access token")
+			return c.WithContext(ctx), nil
+		}
+		for _, s := range scopes {
+			if string(s) == scope {
+				return c.WithContext(ctx), nil
+			}
+		}
+		return nil, &missingScopeError{
+			missingTokenError: missingTokenError{typeName: typeName, key: key, scope: scope},
+			scopes:            scopes,
+		}
+	}
+	return c.WithContext(ctx), nil
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2021 Rollbar, Inc.
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rollbar/terraform-provider-rollbar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientFor tests routing resources and data sources to the client with
+// the token they need.
+func TestClientFor(t *testing.T) {
+	m := newClients("http://localhost", "accountToken", "", clientOptions{})
+
+	c, err := clientFor(context.Background(), m, "rollbar_team", "write")
+	assert.NoError(t, err)
+	assert.Same(t, m[schemaKeyToken].Resty, c.Resty)
+
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.EqualError(t, err, "rollbar_notification needs a project access token with write scope: set provider argument project_api_key or environment variable ROLLBAR_PROJECT_API_KEY")
+	diags := diagFromErr(err)
+	assert.Equal(t, "Missing Rollbar access token", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "project_api_key")
+
+	m = newClients("http://localhost", "", "projectToken", clientOptions{})
+	c, err = clientFor(context.Background(), m, "rollbar_people", "read")
+	assert.NoError(t, err)
+	assert.Same(t, m[projectKeyToken].Resty, c.Resty)
+	_, err = clientFor(context.Background(), m, "rollbar_projects", "read")
+	assert.EqualError(t, err, "rollbar_projects needs an account access token with read scope: set provider argument api_key or environment variable ROLLBAR_API_KEY")
+
+	// Every route is to a type the provider implements
+	p := Provider()
+	for typeName := range tokenRoutes {
+		_, isResource := p.ResourcesMap[typeName]
+		_, isDataSource := p.DataSourcesMap[typeName]
+		assert.True(t, isResource || isDataSource, typeName)
+	}
+}
+
+// TestOfflineClientForScope tests that writes with a project access token
+// lacking write scope fail before reaching the API.
+func TestOfflineClientForScope(t *testing.T) {
+	f := newFakeAPI(t)
+	account := offlineMeta(f).(map[string]*client.RollbarAPIClient)[schemaKeyToken]
+	p, err := account.CreateProject("scoped-project")
+	require.NoError(t, err)
+	newToken := func(name string, scopes ...client.Scope) string {
+		pat, err := account.CreateProjectAccessToken(client.ProjectAccessTokenCreateArgs{
+			ProjectID: p.ID,
+			Name:      name,
+			Scopes:    scopes,
+			Status:    client.StatusEnabled,
+		})
+		require.NoError(t, err)
+		return pat.AccessToken
+	}
+
+	m := newClients(f.URL, "fakeTokenString", newToken("reader", client.ScopeRead), clientOptions{})
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "read")
+	assert.NoError(t, err)
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.EqualError(t, err, `rollbar_notification needs a token with write scope, but the token set by provider argument project_api_key has only "read"`)
+	diags := diagFromErr(err)
+	assert.Equal(t, "Rollbar access token lacks write scope", diags[0].Summary)
+
+	m = newClients(f.URL, "fakeTokenString", newToken("writer", client.ScopeRead, client.ScopeWrite), clientOptions{})
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.NoError(t, err)
+
+	// A token that cannot be looked up is left for the API to check
+	m = newClients(f.URL, "fakeTokenString", "unknownToken", clientOptions{})
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.NoError(t, err)
+
+	// Failed lookups are not remembered
+	m = newClients(f.URL, "fakeTokenString", newToken("flaky", client.ScopeRead), clientOptions{})
+	f.fault = func(r *http.Request) int {
+		return http.StatusServiceUnavailable
+	}
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.NoError(t, err)
+	f.fault = nil
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.Error(t, err)
+
+	// Nor are accounts with too many projects searched
+	for i := 0; i < client.MaxTokenScopeSearch; i++ {
+		_, err = account.CreateProject(fmt.Sprintf("project-%d", i))
+		require.NoError(t, err)
+	}
+	m = newClients(f.URL, "fakeTokenString", newToken("unchecked", client.ScopeRead), clientOptions{})
+	_, err = clientFor(context.Background(), m, "rollbar_notification", "write")
+	assert.NoError(t, err)
+}